@@ -0,0 +1,998 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "fmt"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "marketplace-platform/pkg/service"
+    "marketplace-platform/pkg/util"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// running is held while a command runs, so that the CLI exits between commands on shutdown
+var running sync.Mutex
+
+// idempotentCommands accept the option "--idempotency-key <key>", running the command once per key of the user and
+// printing the result of the first run on replay
+var idempotentCommands = map[string]bool{
+    "CREATE_LISTING":    true,
+    "UPDATE_LISTING":    true,
+    "DELETE_LISTING":    true,
+    "PUBLISH_LISTING":   true,
+    "RESERVE_LISTING":   true,
+    "UNRESERVE_LISTING": true,
+    "WITHDRAW_LISTING":  true,
+    "HIDE_LISTING":      true,
+    "UNHIDE_LISTING":    true,
+    "BUY":               true,
+    "CREATE_CATEGORY":   true,
+    "RETIRE_CATEGORY":   true,
+    "RENAME_CATEGORY":   true,
+    "MERGE_CATEGORY":    true,
+    "GRANT_ROLE":        true,
+    "REVOKE_ROLE":       true,
+}
+
+// credentialArgs lists the positions of the passwords and session tokens in the arguments of each command, which are
+// never logged
+var credentialArgs = map[string][]int{
+    "REGISTER":          {1},
+    "LOGIN":             {1},
+    "LOGOUT":            {0},
+    "CREATE_API_KEY":    {0},
+    "GET_API_KEYS":      {0},
+    "REVOKE_API_KEY":    {0},
+    "CREATE_LISTING":    {0},
+    "UPDATE_LISTING":    {0},
+    "DELETE_LISTING":    {0},
+    "PUBLISH_LISTING":   {0},
+    "RESERVE_LISTING":   {0},
+    "UNRESERVE_LISTING": {0},
+    "WITHDRAW_LISTING":  {0},
+    "HIDE_LISTING":      {0},
+    "UNHIDE_LISTING":    {0},
+    "BUY":               {0},
+    "CREATE_CATEGORY":   {0},
+    "RETIRE_CATEGORY":   {0},
+    "RENAME_CATEGORY":   {0},
+    "MERGE_CATEGORY":    {0},
+    "GRANT_ROLE":        {0},
+    "REVOKE_ROLE":       {0},
+}
+
+// redactedArg replaces the credentials in logged arguments
+const redactedArg = "[REDACTED]"
+
+// listingTransitionCommands maps the listing lifecycle commands to their transition
+var listingTransitionCommands = map[string]enum.ListingTransition{
+    "PUBLISH_LISTING":   enum.ListingTransitionPublish,
+    "RESERVE_LISTING":   enum.ListingTransitionReserve,
+    "UNRESERVE_LISTING": enum.ListingTransitionUnreserve,
+    "WITHDRAW_LISTING":  enum.ListingTransitionWithdraw,
+    "HIDE_LISTING":      enum.ListingTransitionHide,
+    "UNHIDE_LISTING":    enum.ListingTransitionUnhide,
+}
+
+// runCli reads commands from stdin until EOF and prints the results to stdout. Each command runs within its timeout,
+// and is cancelled with ctx on shutdown.
+func runCli(ctx context.Context) {
+    // Create a new reader to read input from the command line
+    reader := bufio.NewReader(os.Stdin)
+
+    for {
+        // Print the prompt
+        _, err := fmt.Fprint(os.Stderr, "# ")
+        if err != nil {
+            log.Errorf("Error printing prompt: %v", err)
+            return
+        }
+
+        input, err := reader.ReadString('\n')
+        if err != nil {
+            log.Errorf("Error reading input: %v", err)
+            return
+        }
+
+        input = strings.TrimSpace(input)
+        if input == "" {
+            continue
+        }
+        args := util.SplitArgs(input)
+        cmd := args[0]
+        args = args[1:]
+
+        log.Info("Received command: " + cmd)
+        log.Info("Received arguments: " + strings.Join(redactArgs(cmd, args), ", "))
+
+        var idempotencyKey string
+        if idempotentCommands[cmd] {
+            args, idempotencyKey, err = cutOption(args, "idempotency-key")
+            if err != nil {
+                log.Errorf("Error parsing options: %v", err)
+                fmt.Println("Error - invalid input")
+                continue
+            }
+        }
+
+        running.Lock()
+        commandCtx, cancel := context.WithTimeout(ctx, svc.Timeout(cmd))
+        runCommand(service.WithIdempotencyKey(commandCtx, idempotencyKey), cmd, args)
+        cancel()
+        running.Unlock()
+    }
+}
+
+// runCommand checks the command and executes the corresponding function. Additional arguments ignored.
+func runCommand(ctx context.Context, cmd string, args []string) {
+    var err error
+    switch cmd {
+    case "REGISTER", "LOGIN":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+        password := args[1]
+
+        if cmd == "REGISTER" {
+            register(ctx, username, password)
+        } else {
+            login(ctx, username, password)
+        }
+
+    case "LOGOUT":
+        if len(args) < 1 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+
+        logout(ctx, token)
+
+    case "CREATE_API_KEY":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        scope := enum.ApiKeyScope(strings.ToLower(args[1]))
+        ttl := constant.DefaultApiKeyTtl
+        if len(args) > 2 {
+            ttl, err = time.ParseDuration(args[2])
+            if err != nil {
+                log.Errorf("Error parsing api key lifetime '%s': %v", args[2], err)
+                fmt.Println("Error - invalid input")
+                return
+            }
+        }
+
+        createApiKey(ctx, token, scope, ttl)
+
+    case "GET_API_KEYS":
+        if len(args) < 1 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+
+        getApiKeys(ctx, token)
+
+    case "REVOKE_API_KEY":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        keyId := args[1]
+
+        revokeApiKey(ctx, token, keyId)
+
+    case "CREATE_LISTING":
+        if len(args) < 5 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        title := args[1]
+        description := args[2]
+        price := args[3]
+        category := args[4]
+        draft := len(args) > 5 && args[5] == "--draft"
+
+        createListing(ctx, token, title, description, price, category, draft)
+    case "GET_LISTING":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+        // convert listingId to int
+        listingId, err := strconv.Atoi(args[1])
+        if err != nil {
+            log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+
+        getListing(ctx, username, listingId)
+    case "GET_CATEGORY":
+        // the sort key and order are optional, followed by the optional paging options
+        positional := len(args)
+        for i, arg := range args {
+            if strings.HasPrefix(arg, "--") {
+                positional = i
+                break
+            }
+        }
+        if positional < 2 || positional == 3 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+        category := args[1]
+
+        // default sort by descending created time
+        sortBy := enum.SortBy(enum.SortByCreatedAt)
+        orderBy := enum.OrderBy(enum.OrderByDescending)
+        if positional >= 4 {
+            sortKeyStr := args[2]
+            sortOrderStr := args[3]
+
+            sortBy, err = parseSortBy(sortKeyStr)
+            if err != nil {
+                log.Errorf("Error parsing sort key '%s': %v", sortKeyStr, err)
+                fmt.Println("Error - invalid sort key")
+                return
+            }
+            orderBy, err = parseOrderBy(sortOrderStr)
+            if err != nil {
+                log.Errorf("Error parsing sort order '%s': %v", sortOrderStr, err)
+                fmt.Println("Error - invalid sort order")
+                return
+            }
+        }
+
+        options, err := parseOptions(args[positional:], "limit", "cursor", "min-price", "max-price", "created-after", "created-before", "title")
+        if err != nil {
+            log.Errorf("Error parsing options: %v", err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+        query := model.CategoryQuery{
+            Category: category,
+            SortBy:   sortBy,
+            OrderBy:  orderBy,
+            Cursor:   options["cursor"],
+
+            TitleContains: options["title"],
+        }
+        if limit, ok := options["limit"]; ok {
+            query.Limit, err = strconv.Atoi(limit)
+            if err != nil || query.Limit < 1 {
+                log.Errorf("Error converting limit '%s' to a positive int: %v", limit, err)
+                fmt.Println("Error - invalid input")
+                return
+            }
+        }
+        query.MinPrice, err = parsePriceOption(options, "min-price")
+        if err != nil {
+            fmt.Println("Error - invalid price")
+            return
+        }
+        query.MaxPrice, err = parsePriceOption(options, "max-price")
+        if err != nil {
+            fmt.Println("Error - invalid price")
+            return
+        }
+        query.CreatedAfter, err = parseTimeOption(options, "created-after")
+        if err != nil {
+            fmt.Println("Error - invalid time")
+            return
+        }
+        query.CreatedBefore, err = parseTimeOption(options, "created-before")
+        if err != nil {
+            fmt.Println("Error - invalid time")
+            return
+        }
+
+        getCategory(ctx, username, query)
+
+    case "SEARCH":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+        options, err := parseOptions(args[2:], "category", "min-price", "max-price", "limit")
+        if err != nil {
+            log.Errorf("Error parsing options: %v", err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+        query := model.SearchQuery{
+            Text:     args[1],
+            Category: options["category"],
+            Limit:    constant.DefaultSearchLimit,
+        }
+        if limit, ok := options["limit"]; ok {
+            query.Limit, err = strconv.Atoi(limit)
+            if err != nil || query.Limit < 1 {
+                log.Errorf("Error converting limit '%s' to a positive int: %v", limit, err)
+                fmt.Println("Error - invalid input")
+                return
+            }
+        }
+        query.MinPrice, err = parsePriceOption(options, "min-price")
+        if err != nil {
+            fmt.Println("Error - invalid price")
+            return
+        }
+        query.MaxPrice, err = parsePriceOption(options, "max-price")
+        if err != nil {
+            fmt.Println("Error - invalid price")
+            return
+        }
+
+        search(ctx, username, query)
+
+    case "GET_TOP_CATEGORY":
+        if len(args) < 1 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+        // the top subcategory of parent if given, and the top top-level category otherwise
+        parent := ""
+        if len(args) > 1 {
+            parent = args[1]
+        }
+
+        getTopCategory(ctx, username, parent)
+
+    case "GET_TOP_CATEGORIES":
+        if len(args) < 1 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+        args = args[1:]
+
+        // the number of categories is optional, followed by the options, of which --exclude-empty takes no value
+        query := model.TopCategoriesQuery{}
+        if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+            query.Limit, err = strconv.Atoi(args[0])
+            if err != nil || query.Limit < 1 {
+                log.Errorf("Error converting limit '%s' to a positive int: %v", args[0], err)
+                fmt.Println("Error - invalid input")
+                return
+            }
+            args = args[1:]
+        }
+        var optionArgs []string
+        for _, arg := range args {
+            if arg == "--exclude-empty" {
+                query.ExcludeEmpty = true
+            } else {
+                optionArgs = append(optionArgs, arg)
+            }
+        }
+        options, err := parseOptions(optionArgs, "parent")
+        if err != nil {
+            log.Errorf("Error parsing options: %v", err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+        query.Parent = options["parent"]
+
+        getTopCategories(ctx, username, query)
+
+    case "DELETE_LISTING":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        listingId, err := strconv.Atoi(args[1])
+        if err != nil {
+            log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+
+        deleteListing(ctx, token, listingId)
+
+    case "UPDATE_LISTING":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        listingId, err := strconv.Atoi(args[1])
+        if err != nil {
+            log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+        options, err := parseOptions(args[2:], "title", "description", "price", "category")
+        if err != nil {
+            log.Errorf("Error parsing options: %v", err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+
+        var update model.ListingUpdate
+        if title, ok := options["title"]; ok {
+            update.Title = &title
+        }
+        if description, ok := options["description"]; ok {
+            update.Description = &description
+        }
+        if category, ok := options["category"]; ok {
+            update.Category = &category
+        }
+        if price, ok := options["price"]; ok {
+            priceInt, err := util.ConvertPriceStringToInt(price)
+            if err != nil {
+                log.Errorf("Error converting price '%s' to int: %v", price, err)
+                fmt.Println("Error - invalid price")
+                return
+            }
+            update.Price = &priceInt
+        }
+
+        updateListing(ctx, token, listingId, update)
+
+    case "PUBLISH_LISTING", "RESERVE_LISTING", "UNRESERVE_LISTING", "WITHDRAW_LISTING", "HIDE_LISTING", "UNHIDE_LISTING":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        listingId, err := strconv.Atoi(args[1])
+        if err != nil {
+            log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+
+        transitionListing(ctx, token, listingId, listingTransitionCommands[cmd])
+
+    case "BUY":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        listingId, err := strconv.Atoi(args[1])
+        if err != nil {
+            log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
+            fmt.Println("Error - invalid input")
+            return
+        }
+
+        buyListing(ctx, token, listingId)
+
+    case "GET_ORDERS":
+        if len(args) < 1 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+
+        getOrders(ctx, username)
+
+    case "CREATE_CATEGORY", "RETIRE_CATEGORY":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        name := args[1]
+
+        if cmd == "CREATE_CATEGORY" {
+            createCategory(ctx, token, name)
+        } else {
+            retireCategory(ctx, token, name)
+        }
+
+    case "RENAME_CATEGORY", "MERGE_CATEGORY":
+        if len(args) < 3 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        from := args[1]
+        to := args[2]
+
+        moveCategory(ctx, token, from, to, cmd == "MERGE_CATEGORY")
+
+    case "GET_CATEGORIES":
+        if len(args) < 1 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        username := args[0]
+
+        getCategories(ctx, username)
+
+    case "GRANT_ROLE":
+        if len(args) < 3 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        username := args[1]
+        role := enum.Role(strings.ToUpper(args[2]))
+
+        grantRole(ctx, token, username, role)
+
+    case "REVOKE_ROLE":
+        if len(args) < 2 {
+            fmt.Println("Error - invalid number of arguments")
+            return
+        }
+        token := args[0]
+        username := args[1]
+
+        revokeRole(ctx, token, username)
+
+    default:
+        log.Error("Unknown command", cmd)
+        fmt.Println("Unknown command", cmd)
+    }
+}
+
+func register(ctx context.Context, username string, password string) {
+    user, err := svc.Register(ctx, username, password)
+    if err != nil {
+        log.Errorf("Error registering user '%s': %v", username, err)
+        printError(err)
+        return
+    }
+
+    if user == nil {
+        fmt.Println("Error - user already existing")
+    } else {
+        fmt.Println("Success")
+    }
+}
+
+// login prints the token of a new session, to be passed to the mutating commands
+func login(ctx context.Context, username string, password string) {
+    token, _, err := svc.Login(ctx, username, password)
+    if err != nil {
+        log.Errorf("Error logging in user '%s': %v", username, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println(token)
+}
+
+func createApiKey(ctx context.Context, token string, scope enum.ApiKeyScope, ttl time.Duration) {
+    key, _, err := svc.CreateApiKey(ctx, token, scope, ttl)
+    if err != nil {
+        log.Errorf("Error creating api key: %v", err)
+        printError(err)
+        return
+    }
+
+    fmt.Println(key)
+}
+
+func getApiKeys(ctx context.Context, token string) {
+    keys, err := svc.GetApiKeys(ctx, token)
+    if err != nil {
+        log.Errorf("Error getting api keys: %v", err)
+        printError(err)
+        return
+    }
+
+    if len(keys) == 0 {
+        fmt.Println("Error - no api keys found")
+    } else {
+        for _, key := range keys {
+            fmt.Println(key)
+        }
+    }
+}
+
+func revokeApiKey(ctx context.Context, token string, keyId string) {
+    err := svc.RevokeApiKey(ctx, token, keyId)
+    if err != nil {
+        log.Errorf("Error revoking api key %s: %v", keyId, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func logout(ctx context.Context, token string) {
+    err := svc.Logout(ctx, token)
+    if err != nil {
+        log.Errorf("Error logging out: %v", err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func createListing(ctx context.Context, token string, title string, description string, price string, category string, draft bool) {
+    priceInt, err := util.ConvertPriceStringToInt(price)
+    if err != nil {
+        log.Errorf("Error converting price '%s' to int: %v", price, err)
+        fmt.Println("Error - invalid price")
+        return
+    }
+    listing, err := svc.CreateListing(ctx, token, title, description, priceInt, category, draft)
+    if err != nil {
+        log.Errorf("Error creating listing: %v", err)
+        printError(err)
+        return
+    }
+    if listing == nil {
+        fmt.Println("Error - listing already existing")
+    } else {
+        fmt.Println(listing.ListingId)
+    }
+}
+
+func getListing(ctx context.Context, username string, listingId int) {
+    listing, err := svc.GetListing(ctx, username, listingId)
+    if err != nil {
+        log.Errorf("Error getting listing '%d': %v", listingId, err)
+        printError(err)
+        return
+    }
+    if listing == nil {
+        fmt.Println("Error - not found")
+    } else {
+        fmt.Println(listing)
+    }
+}
+
+func getCategory(ctx context.Context, username string, query model.CategoryQuery) {
+    page, err := svc.GetCategory(ctx, username, query)
+    if err != nil {
+        log.Errorf("Error getting category '%s': %v", query.Category, err)
+        printError(err)
+        return
+    }
+
+    if len(page.Listings) == 0 && query.Cursor != "" {
+        fmt.Println("Error - no more listings")
+        return
+    }
+    if len(page.Listings) == 0 && query.HasFilters() {
+        fmt.Println("Error - no matching listings")
+        return
+    }
+    if len(page.Listings) == 0 {
+        fmt.Println("Error - category not found")
+        return
+    }
+    for _, listing := range page.Listings {
+        fmt.Println(listing)
+    }
+    if page.Cursor != "" {
+        fmt.Println("Next cursor: " + page.Cursor)
+    }
+}
+
+func search(ctx context.Context, username string, query model.SearchQuery) {
+    listings, err := svc.Search(ctx, username, query)
+    if err != nil {
+        log.Errorf("Error searching '%s': %v", query.Text, err)
+        printError(err)
+        return
+    }
+
+    if len(listings) == 0 {
+        fmt.Println("Error - no matching listings")
+        return
+    }
+    for _, listing := range listings {
+        fmt.Println(listing)
+    }
+}
+
+func getTopCategory(ctx context.Context, username string, parent string) {
+    categoryMetric, err := svc.GetTopCategory(ctx, username, parent)
+    if err != nil {
+        log.Errorf("Error getting top category: %v", err)
+        printError(err)
+        return
+    }
+    if categoryMetric == nil {
+        fmt.Println("Error - no category found")
+    } else {
+        fmt.Println(categoryMetric.Category)
+    }
+}
+
+func getTopCategories(ctx context.Context, username string, query model.TopCategoriesQuery) {
+    categoryMetrics, err := svc.GetTopCategories(ctx, username, query)
+    if err != nil {
+        log.Errorf("Error getting top categories: %v", err)
+        printError(err)
+        return
+    }
+
+    if len(categoryMetrics) == 0 {
+        fmt.Println("Error - no category found")
+        return
+    }
+    for _, categoryMetric := range categoryMetrics {
+        fmt.Println(categoryMetric)
+    }
+}
+
+func deleteListing(ctx context.Context, token string, listingId int) {
+    err := svc.DeleteListing(ctx, token, listingId)
+    if err != nil {
+        log.Errorf("Error deleting listing '%d': %v", listingId, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func updateListing(ctx context.Context, token string, listingId int, update model.ListingUpdate) {
+    _, err := svc.UpdateListing(ctx, token, listingId, update)
+    if err != nil {
+        log.Errorf("Error updating listing '%d': %v", listingId, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func transitionListing(ctx context.Context, token string, listingId int, transition enum.ListingTransition) {
+    _, err := svc.TransitionListing(ctx, token, listingId, transition)
+    if err != nil {
+        log.Errorf("Error trying to %s listing '%d': %v", transition, listingId, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func buyListing(ctx context.Context, token string, listingId int) {
+    _, err := svc.BuyListing(ctx, token, listingId)
+    if err != nil {
+        log.Errorf("Error buying listing '%d': %v", listingId, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func getOrders(ctx context.Context, username string) {
+    orders, err := svc.GetOrders(ctx, username)
+    if err != nil {
+        log.Errorf("Error getting orders of '%s': %v", username, err)
+        printError(err)
+        return
+    }
+
+    if len(orders) == 0 {
+        fmt.Println("Error - no orders found")
+    } else {
+        for _, order := range orders {
+            fmt.Println(order)
+        }
+    }
+}
+
+func createCategory(ctx context.Context, token string, name string) {
+    category, err := svc.CreateCategory(ctx, token, name)
+    if err != nil {
+        log.Errorf("Error creating category '%s': %v", name, err)
+        printError(err)
+        return
+    }
+
+    if category == nil {
+        fmt.Println("Error - category already existing")
+    } else {
+        fmt.Println("Success")
+    }
+}
+
+func retireCategory(ctx context.Context, token string, name string) {
+    err := svc.RetireCategory(ctx, token, name)
+    if err != nil {
+        log.Errorf("Error retiring category '%s': %v", name, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+// moveCategory renames from to to, or merges it into to if merge is set
+func moveCategory(ctx context.Context, token string, from string, to string, merge bool) {
+    var err error
+    if merge {
+        err = svc.MergeCategory(ctx, token, from, to)
+    } else {
+        err = svc.RenameCategory(ctx, token, from, to)
+    }
+    if err != nil {
+        log.Errorf("Error moving category '%s' to '%s': %v", from, to, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func getCategories(ctx context.Context, username string) {
+    categories, err := svc.GetCategories(ctx, username)
+    if err != nil {
+        log.Errorf("Error getting categories: %v", err)
+        printError(err)
+        return
+    }
+
+    if len(categories) == 0 {
+        fmt.Println("Error - no category found")
+    } else {
+        for _, category := range categories {
+            fmt.Println(category)
+        }
+    }
+}
+
+func grantRole(ctx context.Context, token string, username string, role enum.Role) {
+    _, err := svc.GrantRole(ctx, token, username, role)
+    if err != nil {
+        log.Errorf("Error granting role %s to '%s': %v", role, username, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+func revokeRole(ctx context.Context, token string, username string) {
+    _, err := svc.RevokeRole(ctx, token, username)
+    if err != nil {
+        log.Errorf("Error revoking role of '%s': %v", username, err)
+        printError(err)
+        return
+    }
+
+    fmt.Println("Success")
+}
+
+// printError prints the error response for the errors shared by all commands
+func printError(err error) {
+    _, message := exception.Classify(err)
+    fmt.Println("Error - " + message)
+}
+
+// parseOptions parses "--name value" pairs, accepting only the given option names
+func parseOptions(args []string, names ...string) (map[string]string, error) {
+    options := make(map[string]string)
+    for i := 0; i < len(args); i += 2 {
+        name, found := strings.CutPrefix(args[i], "--")
+        if !found {
+            return nil, fmt.Errorf("expected option, got '%s'", args[i])
+        }
+        if !contains(names, name) {
+            return nil, fmt.Errorf("unknown option '%s'", args[i])
+        }
+        if i+1 >= len(args) {
+            return nil, fmt.Errorf("missing value of option '%s'", args[i])
+        }
+        options[name] = args[i+1]
+    }
+    return options, nil
+}
+
+// redactArgs returns a copy of the arguments of a command to log, with its passwords, session tokens and API keys
+// redacted. API keys are accepted in place of usernames, so they are redacted wherever they appear.
+func redactArgs(cmd string, args []string) []string {
+    redacted := append([]string{}, args...)
+    for _, position := range credentialArgs[cmd] {
+        if position < len(redacted) {
+            redacted[position] = redactedArg
+        }
+    }
+    for i, arg := range redacted {
+        if strings.HasPrefix(arg, model.ApiKeyPrefix) {
+            redacted[i] = redactedArg
+        }
+    }
+    return redacted
+}
+
+// cutOption removes the option "--name value" from args wherever it appears
+// Returns the remaining arguments and the value of the option, empty if it is absent
+func cutOption(args []string, name string) ([]string, string, error) {
+    for i, arg := range args {
+        if arg != "--"+name {
+            continue
+        }
+        if i+1 >= len(args) || args[i+1] == "" {
+            return nil, "", fmt.Errorf("missing value of option '%s'", arg)
+        }
+        remaining := append(append([]string{}, args[:i]...), args[i+2:]...)
+        return remaining, args[i+1], nil
+    }
+    return args, "", nil
+}
+
+// parsePriceOption converts the price of an option to cents. Returns nil if the option is not set.
+func parsePriceOption(options map[string]string, name string) (*int, error) {
+    price, ok := options[name]
+    if !ok {
+        return nil, nil
+    }
+    priceInt, err := util.ConvertPriceStringToInt(price)
+    if err != nil {
+        log.Errorf("Error converting %s '%s' to int: %v", name, price, err)
+        return nil, err
+    }
+    return &priceInt, nil
+}
+
+// parseTimeOption parses the time of an option. Returns nil if the option is not set.
+func parseTimeOption(options map[string]string, name string) (*time.Time, error) {
+    value, ok := options[name]
+    if !ok {
+        return nil, nil
+    }
+    t, err := util.ParseTime(value)
+    if err != nil {
+        log.Errorf("Error parsing %s '%s': %v", name, value, err)
+        return nil, err
+    }
+    return &t, nil
+}
+
+func contains(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
+
+func parseSortBy(s string) (enum.SortBy, error) {
+    switch s {
+    case "sort_time":
+        return enum.SortByCreatedAt, nil
+    case "sort_price":
+        return enum.SortByPrice, nil
+    default:
+        return 0, fmt.Errorf("invalid SortBy value: %s", s)
+    }
+}
+
+func parseOrderBy(s string) (enum.OrderBy, error) {
+    switch s {
+    case "dsc":
+        return enum.OrderByDescending, nil
+    case "asc":
+        return enum.OrderByAscending, nil
+    default:
+        return 0, fmt.Errorf("invalid OrderBy value: %s", s)
+    }
+}
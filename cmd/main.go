@@ -1,398 +1,249 @@
 package main
 
 import (
-    "bufio"
+    "context"
+    "errors"
+    "flag"
     "fmt"
-    "github.com/go-playground/validator/v10"
+    "google.golang.org/grpc"
+    "marketplace-platform/pkg/api/pb"
+    "marketplace-platform/pkg/api/rest"
+    "marketplace-platform/pkg/api/rpc"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
     "marketplace-platform/pkg/data/ddb"
-    "marketplace-platform/pkg/data/model"
-    "marketplace-platform/pkg/data/model/enum"
-    "marketplace-platform/pkg/exception"
+    "marketplace-platform/pkg/data/memory"
+    "marketplace-platform/pkg/data/sqlite"
     "marketplace-platform/pkg/logger"
-    "marketplace-platform/pkg/util"
+    "marketplace-platform/pkg/service"
+    "net"
+    "net/http"
     "os"
     "os/signal"
-    "strconv"
     "strings"
     "syscall"
+    "time"
 )
 
 var (
     log = logger.NewLogger()
-    dao = ddb.NewDynamoDataAccess(log)
+    svc *service.Marketplace
 )
 
+// main starts the interactive CLI, or with the "serve" argument, the long-running HTTP/JSON and gRPC servers
 func main() {
-    exists, err := dao.ListingTableExists()
-    if err != nil {
-        log.Fatalf("Error checking if listing table exists: %v", err)
-        return
-    }
-    if exists {
-        log.Info("Listing table exists before initialization. Cleaning up")
-        err := dao.DeleteTable()
+    defaultBackend, backendExists := os.LookupEnv(constant.StorageBackendEnvKey)
+    if !backendExists {
+        defaultBackend = constant.StorageBackendDynamoDb
+    }
+    backend := flag.String("store", defaultBackend, "storage backend: "+
+        strings.Join([]string{constant.StorageBackendDynamoDb, constant.StorageBackendMemory, constant.StorageBackendSqlite}, ", "))
+    reset := flag.Bool("reset", false, "delete all existing data and recreate the storage schema on startup")
+    addr := flag.String("addr", ":8080", "listen address of the HTTP/JSON server in serve mode")
+    grpcAddr := flag.String("grpc-addr", ":9090", "listen address of the gRPC server in serve mode")
+    reindex := flag.Bool("reindex", false, "rebuild the search index from the stored listings on startup")
+    flag.Parse()
+
+    // the context is cancelled on the first SIGINT or SIGTERM, cancelling the requests to the store in flight
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+    defer stop()
+
+    store := newStore(ctx, *backend, *reset)
+    if *reindex {
+        log.Info("Reindex requested. Rebuilding the search index")
+        count, err := store.RebuildSearchIndex(ctx)
         if err != nil {
-            log.Fatalf("Error deleting listing table: %v", err)
-            return
+            log.Fatalf("Error rebuilding search index: %v", err)
         }
-    } else {
-        log.Info("Listing table does not exist before initialization")
+        log.Infof("Search index rebuilt with %d listings", count)
     }
+    svc = service.NewMarketplace(store, log)
 
-    log.Info("Initializing empty Listing table")
-    _, err = dao.CreateListingTable()
-    if err != nil {
-        log.Fatalf("Error creating Listing table: %v", err)
+    if flag.Arg(0) == "serve" {
+        serve(ctx, *addr, *grpcAddr)
         return
     }
 
-    exists, err = dao.ListingTableExists()
-    if err != nil {
-        log.Fatalf("Error checking if listing table exists: %v", err)
-        return
-    }
-    log.Info("Empty Listing table initialized")
-
-    // Create a channel to receive the SIGTERM signal
-    c := make(chan os.Signal, 1)
-    signal.Notify(c, syscall.SIGTERM)
-
-    // Create a goroutine to handle the SIGTERM signal
+    // Exit on shutdown once the command in flight has given up
     go func() {
-        <-c
-        fmt.Println("Received SIGTERM, exiting...")
+        <-ctx.Done()
+        running.Lock()
+        fmt.Println("Received shutdown signal, exiting...")
         os.Exit(0)
     }()
 
-    // Create a new reader to read input from the command line
-    reader := bufio.NewReader(os.Stdin)
+    runCli(ctx)
+}
 
-    for {
-        // Print the prompt
-        _, err = fmt.Fprint(os.Stderr, "# ")
+// serve runs the HTTP/JSON and gRPC servers until ctx is cancelled, then drains in-flight requests for up to
+// constant.ShutdownTimeout and cancels those still running
+func serve(ctx context.Context, addr string, grpcAddr string) {
+    // requests outlive the signal context so that they can be drained
+    requests, cancelRequests := context.WithCancel(context.Background())
+    server := &http.Server{
+        Addr:              addr,
+        Handler:           rest.NewServer(svc, log),
+        ReadHeaderTimeout: 10 * time.Second,
+        BaseContext: func(net.Listener) context.Context {
+            return requests
+        },
+    }
+
+    rpcServer := rpc.NewServer(svc, log)
+    grpcServer := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(rpcServer.UnaryDeadline, rpcServer.UnaryIdempotencyKey),
+        grpc.StreamInterceptor(rpcServer.StreamDeadline))
+    pb.RegisterMarketplaceServiceServer(grpcServer, rpcServer)
+    grpcListener, err := net.Listen("tcp", grpcAddr)
+    if err != nil {
+        log.Fatalf("Error listening on %s: %v", grpcAddr, err)
+    }
+    go func() {
+        log.Infof("Serving gRPC on %s", grpcAddr)
+        err := grpcServer.Serve(grpcListener)
         if err != nil {
-            log.Errorf("Error printing prompt: %v", err)
-            return
+            log.Fatalf("Error serving gRPC: %v", err)
         }
+    }()
 
-        input, err := reader.ReadString('\n')
+    drained := make(chan struct{})
+    go func() {
+        defer close(drained)
+        <-ctx.Done()
+        log.Info("Received shutdown signal, draining servers")
+        drainCtx, cancel := context.WithTimeout(context.Background(), constant.ShutdownTimeout)
+        defer cancel()
+
+        grpcStopped := make(chan struct{})
+        go func() {
+            grpcServer.GracefulStop()
+            close(grpcStopped)
+        }()
+        err := server.Shutdown(drainCtx)
         if err != nil {
-            log.Errorf("Error reading input: %v", err)
-            return
+            log.Errorf("Error shutting down server: %v", err)
         }
-
-        input = strings.TrimSpace(input)
-        if input == "" {
-            continue
+        select {
+        case <-grpcStopped:
+        case <-drainCtx.Done():
         }
-        args := util.SplitArgs(input)
-        cmd := args[0]
-        args = args[1:]
-
-        log.Info("Received command: " + cmd)
-        log.Info("Received arguments: " + strings.Join(args, ", "))
-
-        // Check the command and execute the corresponding function. Additional arguments ignored.
-        switch cmd {
-        case "REGISTER":
-            if len(args) < 1 {
-                fmt.Println("Error - invalid number of arguments")
-                continue
-            }
-            username := args[0]
-            register(username)
-        case "CREATE_LISTING":
-            if len(args) < 5 {
-                fmt.Println("Error - invalid number of arguments")
-                continue
-            }
-            username := args[0]
-            title := args[1]
-            description := args[2]
-            price := args[3]
-            category := args[4]
 
-            user, err := authUser(username)
-            if err != nil {
-                log.Errorf("Error authenticating user '%s': %v", username, err)
-                continue
-            }
-            if user == nil {
-                fmt.Println("Error - unknown user")
-                continue
-            }
-
-            createListing(username, title, description, price, category)
-        case "GET_LISTING":
-            if len(args) < 2 {
-                fmt.Println("Error - invalid number of arguments")
-                continue
-            }
-            username := args[0]
-            // convert listingId to int
-            listingId, err := strconv.Atoi(args[1])
-            if err != nil {
-                log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
-                fmt.Println("Error - invalid input")
-                continue
-            }
-
-            user, err := authUser(username)
-            if err != nil {
-                log.Errorf("Error authenticating user '%s': %v", username, err)
-                fmt.Println("Error - internal server error")
-                continue
-            }
-            if user == nil {
-                fmt.Println("Error - unknown user")
-                continue
-            }
-
-            getListing(listingId)
-        case "GET_CATEGORY":
-            if len(args) < 2 || len(args) == 3 {
-                fmt.Println("Error - invalid number of arguments")
-                continue
-            }
-            username := args[0]
-            category := args[1]
-
-            user, err := authUser(username)
-            if err != nil {
-                log.Errorf("Error authenticating user '%s': %v", username, err)
-                fmt.Println("Error - internal server error")
-                continue
-            }
-            if user == nil {
-                fmt.Println("Error - unknown user")
-                continue
-            }
-            //
-            // // DEBUG
-            // fmt.Println("args: ", args)
-
-            if len(args) >= 4 {
-                sortKeyStr := args[2]
-                sortOrderStr := args[3]
-
-                sortBy, err := parseSortBy(sortKeyStr)
-                if err != nil {
-                    log.Errorf("Error parsing sort key '%s': %v", sortKeyStr, err)
-                    fmt.Println("Error - invalid sort key")
-                    continue
-                }
-                orderBy, err := parseOrderBy(sortOrderStr)
-                if err != nil {
-                    log.Errorf("Error parsing sort order '%s': %v", sortOrderStr, err)
-                    fmt.Println("Error - invalid sort order")
-                    continue
-                }
-
-                getCategory(category, &sortBy, &orderBy)
-            } else {
-                getCategory(category, nil, nil)
-            }
-
-        case "GET_TOP_CATEGORY":
-            if len(args) < 1 {
-                fmt.Println("Error - invalid number of arguments")
-                continue
-            }
-            username := args[0]
-
-            user, err := authUser(username)
-            if err != nil {
-                log.Errorf("Error authenticating user '%s': %v", username, err)
-                fmt.Println("Error - internal server error")
-                continue
-            }
-            if user == nil {
-                fmt.Println("Error - unknown user")
-                continue
-            }
-
-            getTopCategory()
-
-        case "DELETE_LISTING":
-            if len(args) < 2 {
-                fmt.Println("Error - invalid number of arguments")
-                continue
-            }
-            username := args[0]
-            listingId, err := strconv.Atoi(args[1])
-            if err != nil {
-                log.Errorf("Error converting listingId '%s' to int: %v", args[1], err)
-                fmt.Println("Error - invalid input")
-                continue
-            }
-
-            user, err := authUser(username)
-            if err != nil {
-                log.Errorf("Error authenticating user '%s': %v", username, err)
-                fmt.Println("Error - internal server error")
-                continue
-            }
-            if user == nil {
-                fmt.Println("Error - unknown user")
-                continue
-            }
-
-            deleteListing(username, listingId)
+        // cancel the requests that did not finish in time
+        cancelRequests()
+        grpcServer.Stop()
+    }()
 
-        default:
-            log.Error("Unknown command", cmd)
-            fmt.Println("Unknown command", cmd)
-        }
+    log.Infof("Serving HTTP on %s", addr)
+    fmt.Fprintf(os.Stderr, "Serving HTTP on %s and gRPC on %s\n", addr, grpcAddr)
+    err = server.ListenAndServe()
+    if err != nil && !errors.Is(err, http.ErrServerClosed) {
+        log.Fatalf("Error serving HTTP: %v", err)
     }
+    <-drained
+    log.Info("Server stopped")
 }
 
-func register(username string) {
-    user, err := dao.PutUser(username)
-    if err != nil {
-        log.Errorf("Error registering user '%s': %v", username, err)
-        fmt.Println("Error - internal server error")
-        return
-    }
-
-    if user == nil {
-        fmt.Println("Error - user already existing")
-    } else {
-        fmt.Println("Success")
+// newStore initializes the storage backend used by the commands
+// Existing data is kept unless reset is set
+func newStore(ctx context.Context, backend string, reset bool) data.MarketplaceStore {
+    log.Info("Storage backend: " + backend)
+    switch backend {
+    case constant.StorageBackendMemory:
+        return memory.NewMemoryDataAccess(log)
+    case constant.StorageBackendSqlite:
+        return newSqliteStore(ctx, reset)
+    case constant.StorageBackendDynamoDb:
+        return newDynamoStore(ctx, reset)
+    default:
+        log.Fatalf("Unknown storage backend '%s'", backend)
+        return nil
     }
 }
 
-func createListing(username string, title string, description string, price string, category string) {
-    priceInt, err := util.ConvertPriceStringToInt(price)
-    if err != nil {
-        log.Errorf("Error converting price '%s' to int: %v", price, err)
-        fmt.Println("Error - invalid price")
-        return
-    }
-    listing, err := dao.PutListing(username, title, description, priceInt, category)
-    if err != nil {
-        log.Errorf("Error creating listing: %v", err)
+func newSqliteStore(ctx context.Context, reset bool) data.MarketplaceStore {
+    sqliteDao := sqlite.NewSqliteDataAccess(log)
 
-        if _, ok := err.(validator.ValidationErrors); ok {
-            fmt.Println("Error - invalid input")
-        } else {
-            fmt.Println("Error - internal server error")
+    if reset {
+        log.Info("Reset requested. Dropping all SQLite tables")
+        err := sqliteDao.Reset(ctx)
+        if err != nil {
+            log.Fatalf("Error resetting SQLite database: %v", err)
         }
-        return
     }
-    if listing == nil {
-        fmt.Println("Error - listing already existing")
-    } else {
-        fmt.Println(listing.ListingId)
-    }
-}
 
-func getListing(listingId int) {
-    listing, err := dao.GetListing(listingId)
+    err := sqliteDao.Migrate(ctx)
     if err != nil {
-        log.Errorf("Error getting listing '%s': %v", listingId, err)
-        fmt.Println("Error - internal server error")
-        return
-    }
-    if listing == nil {
-        fmt.Println("Error - not found")
-    } else {
-        fmt.Println(listing)
+        log.Fatalf("Error migrating SQLite schema: %v", err)
     }
-}
-
-func getCategory(category string, sortKey *enum.SortBy, sortOrder *enum.OrderBy) {
-    var listings []model.Listing
-    var err error
-    if sortKey == nil && sortOrder == nil {
-        // default sort by descending created time
-        listings, err = dao.GetCategory(category, enum.SortByCreatedAt, enum.OrderByDescending)
-    } else {
-        listings, err = dao.GetCategory(category, *sortKey, *sortOrder)
-    }
-
+    version, err := sqliteDao.SchemaVersion(ctx)
     if err != nil {
-        log.Errorf("Error getting category '%s': %v", category, err)
-        fmt.Println("Error - internal server error")
-        return
+        log.Fatalf("Error reading SQLite schema version: %v", err)
     }
+    log.Infof("SQLite schema at version %d", version)
 
-    if len(listings) == 0 {
-        fmt.Println("Error - category not found")
-    } else {
-        for _, listing := range listings {
-            fmt.Println(listing)
-        }
-    }
+    return sqliteDao
 }
 
-func getTopCategory() {
-    category, err := dao.GetTopCategory()
+func newDynamoStore(ctx context.Context, reset bool) data.MarketplaceStore {
+    ddbDao := ddb.NewDynamoDataAccess(log)
+
+    exists, err := ddbDao.ListingTableExists(ctx)
     if err != nil {
-        log.Errorf("Error getting top category: %v", err)
-        fmt.Println("Error - internal server error")
-        return
+        log.Fatalf("Error checking if listing table exists: %v", err)
     }
-    if category == "" {
-        fmt.Println("Error - no category found")
-    } else {
-        fmt.Println(category)
+    if exists && reset {
+        log.Info("Reset requested. Deleting existing Listing table")
+        err := ddbDao.DeleteTable(ctx)
+        if err != nil {
+            log.Fatalf("Error deleting listing table: %v", err)
+        }
+        exists = false
     }
-}
 
-func deleteListing(username string, listingId int) {
-    err := dao.DeleteListing(username, listingId)
-    if err != nil {
-        // handle exception.OwnershipMismatchException and exception.ListingNotFoundException
-        log.Errorf("Error deleting listing '%d': %v", listingId, err)
-        switch err.(type) {
-        case *exception.OwnershipMismatchException:
-            fmt.Println("Error - listing owner mismatch")
-        case *exception.ListingDoesNotExistException:
-            fmt.Println("Error - listing does not exist")
-        default:
-            fmt.Println("Error - internal server error")
+    if exists {
+        log.Info("Listing table exists. Validating schema")
+        err = ddbDao.ValidateListingTable(ctx)
+        if err != nil {
+            log.Fatalf("Existing Listing table does not match the expected schema, restart with -reset to recreate it: %v", err)
         }
-        return
+        log.Info("Existing Listing table is valid. Keeping existing data")
+        migrated, err := ddbDao.MigrateUserKeys(ctx)
+        if err != nil {
+            log.Fatalf("Error migrating user records: %v", err)
+        }
+        if migrated > 0 {
+            log.Infof("Keyed %d existing users by their canonical username", migrated)
+        }
+        migrated, err = ddbDao.MigrateCategoryCatalog(ctx)
+        if err != nil {
+            log.Fatalf("Error migrating category catalog: %v", err)
+        }
+        if migrated > 0 {
+            log.Infof("Migrated category catalog to %d categories", migrated)
+        }
+        lastListingId, err := ddbDao.SeedListingIdCounter(ctx)
+        if err != nil {
+            log.Fatalf("Error seeding listing ID counter: %v", err)
+        }
+        if lastListingId > 0 {
+            log.Infof("Seeded listing ID counter at existing listing %d", lastListingId)
+        }
+        enabled, err := ddbDao.EnableTimeToLive(ctx)
+        if err != nil {
+            log.Fatalf("Error enabling TTL on listing table: %v", err)
+        }
+        if enabled {
+            log.Info("Enabled TTL on idempotency records of existing Listing table")
+        }
+        return ddbDao
     }
 
-    fmt.Println("Success")
-}
-
-// authUser determines if the user is authorized to perform the action
-// Returns the user if authorized, otherwise return nil
-func authUser(username string) (*model.User, error) {
-    user, err := dao.GetUser(username)
+    log.Info("Initializing empty Listing table")
+    _, err = ddbDao.CreateListingTable(ctx)
     if err != nil {
-        log.Debugf("Error getting user '%s': %v", username, err)
-        return nil, err
-    }
-    if user == nil {
-        log.Debugf("User '%s' does not exist", username)
-        return nil, nil
-    }
-    log.Debugf("Retrieved user '%+v'", user)
-    log.Debugf("User with username '%s' exist", username)
-    return user, nil
-}
-
-func parseSortBy(s string) (enum.SortBy, error) {
-    switch s {
-    case "sort_time":
-        return enum.SortByCreatedAt, nil
-    case "sort_price":
-        return enum.SortByPrice, nil
-    default:
-        return 0, fmt.Errorf("invalid SortBy value: %s", s)
+        log.Fatalf("Error creating Listing table: %v", err)
     }
-}
+    log.Info("Empty Listing table initialized")
 
-func parseOrderBy(s string) (enum.OrderBy, error) {
-    switch s {
-    case "dsc":
-        return enum.OrderByDescending, nil
-    case "asc":
-        return enum.OrderByAscending, nil
-    default:
-        return 0, fmt.Errorf("invalid OrderBy value: %s", s)
-    }
+    return ddbDao
 }
@@ -1,17 +1,54 @@
 package main
 
 import (
+    "bufio"
     "bytes"
     "fmt"
+    "io"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
     "os"
     "os/exec"
+    "path/filepath"
     "strings"
     "testing"
 )
 
+func TestMain(m *testing.M) {
+    // Build the program under test so that `go test` works without a manual build step
+    build := exec.Command("go", "build", "-o", "main", ".")
+    build.Stdout = os.Stdout
+    build.Stderr = os.Stderr
+    if err := build.Run(); err != nil {
+        fmt.Printf("could not build program under test: %v\n", err)
+        os.Exit(1)
+    }
+
+    os.Exit(m.Run())
+}
+
 func TestIntegration(t *testing.T) {
+    // Run against the explicitly configured storage backend, otherwise against every backend that runs in-process,
+    // so DynamoDB Local is not required
+    if _, exists := os.LookupEnv(constant.StorageBackendEnvKey); exists {
+        runIntegration(t, os.Environ())
+        return
+    }
+
+    t.Run(constant.StorageBackendMemory, func(t *testing.T) {
+        runIntegration(t, append(os.Environ(), constant.StorageBackendEnvKey+"="+constant.StorageBackendMemory))
+    })
+    t.Run(constant.StorageBackendSqlite, func(t *testing.T) {
+        runIntegration(t, append(os.Environ(),
+            constant.StorageBackendEnvKey+"="+constant.StorageBackendSqlite,
+            constant.SqlitePathEnvKey+"="+filepath.Join(t.TempDir(), "marketplace.db")))
+    })
+}
+
+func runIntegration(t *testing.T, env []string) {
     // Start the program as a separate process
     cmd := exec.Command("./main")
+    cmd.Env = append(env, constant.AdminUsersEnvKey+"=admin", constant.RateLimitLoginEnvKey+"=5/1h")
     stdin, err := cmd.StdinPipe()
     if err != nil {
         t.Fatalf("could not get stdin pipe: %v", err)
@@ -31,14 +68,21 @@ func TestIntegration(t *testing.T) {
         expected string
     }{
         // authentication errors
-        {"CREATE_LISTING user1 'Phone model 8' 'Black color, brand new' 1000 'Electronics'\n", "Error - unknown user\n"},
-        {"DELETE_LISTING user1 100001\n", "Error - unknown user\n"},
+        {"CREATE_LISTING user1 'Phone model 8' 'Black color, brand new' 1000 'Electronics'\n", "Error - invalid session\n"},
+        {"DELETE_LISTING user1 100001\n", "Error - invalid session\n"},
         {"GET_LISTING user1 100001\n", "Error - unknown user\n"},
         {"GET_CATEGORY user1 'Electronics'\n", "Error - unknown user\n"},
         {"GET_TOP_CATEGORY user1\n", "Error - unknown user\n"},
+        {"SEARCH user1 'phone'\n", "Error - unknown user\n"},
+        {"GET_CATEGORIES user1\n", "Error - unknown user\n"},
+        {"CREATE_CATEGORY user1 'Electronics'\n", "Error - invalid session\n"},
+        {"LOGIN user1 'password 1'\n", "Error - invalid credentials\n"},
 
         // input validation errors
         {"REGISTER \n", "Error - invalid number of arguments\n"},
+        {"REGISTER user1\n", "Error - invalid number of arguments\n"},
+        {"REGISTER user1 short\n", "Error - invalid input\n"},
+        {"LOGIN user1\n", "Error - invalid number of arguments\n"},
         {"CREATE_LISTING user1 'Phone model 8' 'Black color, brand new' 1000 \n", "Error - invalid number of arguments\n"},
         {"DELETE_LISTING user1 \n", "Error - invalid number of arguments\n"},
         {"GET_LISTING user1 \n", "Error - invalid number of arguments\n"},
@@ -47,25 +91,38 @@ func TestIntegration(t *testing.T) {
         {"GET_TOP_CATEGORY \n", "Error - invalid number of arguments\n"},
 
         // happy path
-        {"REGISTER user1\n", "Success\n"},
-        {"CREATE_LISTING user1 'Phone model 8' 'Black color, brand new' 1000 'Electronics'\n", "100001\n"},
+        {"REGISTER user1 'password 1'\n", "Success\n"},
+        {"REGISTER admin password3\n", "Success\n"},
+        {"LOGIN user1 'password 2'\n", "Error - invalid credentials\n"},
+        // mutating commands take the token printed by LOGIN, captured as {user1} for the following commands
+        {"LOGIN user1 'password 1'\n", "{token:user1}\n"},
+        {"LOGIN admin password3\n", "{token:admin}\n"},
+        // an empty marketplace has no top category
+        {"GET_TOP_CATEGORY user1\n", "Error - no category found\n"},
+        {"GET_TOP_CATEGORY user1 'Electronics'\n", "Error - no category found\n"},
+        {"GET_TOP_CATEGORIES user1\n", "Error - no category found\n"},
+        {"CREATE_CATEGORY {admin} 'Electronics'\n", "Success\n"},
+        {"CREATE_CATEGORY {admin} Sports\n", "Success\n"},
+        {"CREATE_CATEGORY {admin} 'Fashion'\n", "Success\n"},
+        {"CREATE_LISTING {user1} 'Phone model 8' 'Black color, brand new' 1000 'Electronics'\n", "100001\n"},
         {"GET_LISTING user1 100001\n", "Phone model 8|Black color, brand new|1000|2019-02-22 12:34:56|Electronics|user1\n"},
 
         // title cannot be empty
-        {"CREATE_LISTING user1 '' 'Black color, brand new' 1000 'Electronics'\n", "Error - invalid input\n"},
+        {"CREATE_LISTING {user1} '' 'Black color, brand new' 1000 'Electronics'\n", "Error - invalid input\n"},
         // category cannot be empty
-        {"CREATE_LISTING user1 'Phone model 8' 'Black color, brand new' 1000 ''\n", "Error - invalid input\n"},
+        {"CREATE_LISTING {user1} 'Phone model 8' 'Black color, brand new' 1000 ''\n", "Error - invalid input\n"},
         // listing ID validation error
         {"GET_LISTING user1 100xxx\n", "Error - invalid input\n"},
         // listing not found
         {"GET_LISTING user1 900001\n", "Error - not found\n"},
 
         // unquoted non-whitespace string field should still work
-        {"CREATE_LISTING user1 'Black shoes' 'Training shoes' 100 Sports\n", "100002\n"},
+        {"CREATE_LISTING {user1} 'Black shoes' 'Training shoes' 100 Sports\n", "100002\n"},
 
-        {"REGISTER user2\n", "Success\n"},
-        {"REGISTER user2\n", "Error - user already existing\n"},
-        {"CREATE_LISTING user2 'T-shirt' 'White color' 20 'Sports'\n", "100003\n"},
+        {"REGISTER user2 password2\n", "Success\n"},
+        {"REGISTER user2 password2\n", "Error - user already existing\n"},
+        {"LOGIN user2 password2\n", "{token:user2}\n"},
+        {"CREATE_LISTING {user2} 'T-shirt' 'White color' 20 'Sports'\n", "100003\n"},
         {"GET_LISTING user1 100003\n", "T-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\n"},
 
         // invalid sort key and order
@@ -84,18 +141,218 @@ func TestIntegration(t *testing.T) {
         // default sort order is sort_time dsc
         {"GET_CATEGORY user1 'Sports'\n", "T-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\nBlack shoes|Training shoes|100|2019-02-22 12:34:57|Sports|user1\n"},
 
+        // paging follows the cursor printed after a full page
+        {"GET_CATEGORY user1 'Sports' sort_price asc --limit 1\n", "T-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\nNext cursor: eyJjIjoiU3BvcnRzIiwicyI6MSwidiI6MjAwMCwiaWQiOjEwMDAwM30\n"},
+        {"GET_CATEGORY user1 'Sports' sort_price asc --limit 1 --cursor eyJjIjoiU3BvcnRzIiwicyI6MSwidiI6MjAwMCwiaWQiOjEwMDAwM30\n", "Black shoes|Training shoes|100|2019-02-22 12:34:57|Sports|user1\n"},
+        {"GET_CATEGORY user1 'Sports' sort_time asc --cursor eyJjIjoiU3BvcnRzIiwicyI6MSwidiI6MjAwMCwiaWQiOjEwMDAwM30\n", "Error - invalid input\n"},
+        {"GET_CATEGORY user1 'Sports' --limit 0\n", "Error - invalid input\n"},
+        {"GET_CATEGORY user1 'Sports' --cursor xyz\n", "Error - invalid input\n"},
+
+        // filters
+        {"GET_CATEGORY user1 'Sports' sort_price asc --min-price 50 --max-price 200\n", "Black shoes|Training shoes|100|2019-02-22 12:34:57|Sports|user1\n"},
+        {"GET_CATEGORY user1 'Sports' --max-price 20\n", "T-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\n"},
+        {"GET_CATEGORY user1 'Sports' --title shirt\n", "T-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\n"},
+        {"GET_CATEGORY user1 'Sports' --title Shirt\n", "Error - no matching listings\n"},
+        {"GET_CATEGORY user1 'Sports' sort_time asc --created-after 2000-01-01 --created-before '2999-12-31 23:59:59'\n", "Black shoes|Training shoes|100|2019-02-22 12:34:57|Sports|user1\nT-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\n"},
+        {"GET_CATEGORY user1 'Sports' --created-before 2000-01-01\n", "Error - no matching listings\n"},
+        {"GET_CATEGORY user1 'Sports' --min-price abc\n", "Error - invalid price\n"},
+        {"GET_CATEGORY user1 'Sports' --created-after yesterday\n", "Error - invalid time\n"},
+        {"GET_CATEGORY user1 'Sports' --min-price 200 --max-price 50\n", "Error - invalid input\n"},
+
+        // search ranks stemmed, case-folded matches of titles and descriptions, shorter listings first for the same term frequency
+        {"SEARCH user1 'COLORS'\n", "T-shirt|White color|20|2019-02-22 12:34:58|Sports|user2\nPhone model 8|Black color, brand new|1000|2019-02-22 12:34:56|Electronics|user1\n"},
+        {"SEARCH user1 black --limit 1\n", "Black shoes|Training shoes|100|2019-02-22 12:34:57|Sports|user1\n"},
+        {"SEARCH user1 'black color' --category Electronics\n", "Phone model 8|Black color, brand new|1000|2019-02-22 12:34:56|Electronics|user1\n"},
+        {"SEARCH user1 color --min-price 50\n", "Phone model 8|Black color, brand new|1000|2019-02-22 12:34:56|Electronics|user1\n"},
+        {"SEARCH user1 'jacket'\n", "Error - no matching listings\n"},
+        {"SEARCH user1 ''\n", "Error - invalid input\n"},
+        {"SEARCH user1 color --max-price abc\n", "Error - invalid price\n"},
+        {"SEARCH user1\n", "Error - invalid number of arguments\n"},
+
         {"GET_TOP_CATEGORY user1\n", "Sports\n"},
-        {"DELETE_LISTING user1 100003\n", "Error - listing owner mismatch\n"},
-        {"DELETE_LISTING user2 100003\n", "Success\n"},
+        {"DELETE_LISTING {user1} 100003\n", "Error - listing owner mismatch\n"},
+        {"DELETE_LISTING {user2} 100003\n", "Success\n"},
+        // Sports ties with Electronics at 1 listing, and the tie has gone to the greater name since the first version
         {"GET_TOP_CATEGORY user2\n", "Sports\n"},
-        {"DELETE_LISTING user1 100002\n", "Success\n"},
+        {"DELETE_LISTING {user1} 100002\n", "Success\n"},
         {"GET_TOP_CATEGORY user1\n", "Electronics\n"},
 
         // create listing with empty description should still work
-        {"CREATE_LISTING user1 'Black shoes' '' 100 Sports\n", "100002\n"},
+        // IDs of deleted listings are never reused
+        {"CREATE_LISTING {user1} 'Black shoes' '' 100 Sports\n", "100004\n"},
 
         // listing ID validation error
-        {"DELETE_LISTING user1 100xxx\n", "Error - invalid input\n"},
+        {"DELETE_LISTING {user1} 100xxx\n", "Error - invalid input\n"},
+
+        // update listing
+        {"UPDATE_LISTING {user1}\n", "Error - invalid number of arguments\n"},
+        {"UPDATE_LISTING user3 100004 --price 150\n", "Error - invalid session\n"},
+        {"UPDATE_LISTING {user1} 100004\n", "Error - invalid input\n"},
+        {"UPDATE_LISTING {user1} 100004 --colour red\n", "Error - invalid input\n"},
+        {"UPDATE_LISTING {user1} 100004 --price\n", "Error - invalid input\n"},
+        {"UPDATE_LISTING {user1} 100004 --price abc\n", "Error - invalid price\n"},
+        {"UPDATE_LISTING {user1} 100004 --title ''\n", "Error - invalid input\n"},
+        {"UPDATE_LISTING {user1} 900001 --price 150\n", "Error - listing does not exist\n"},
+        {"UPDATE_LISTING {user2} 100004 --price 150\n", "Error - listing owner mismatch\n"},
+        {"UPDATE_LISTING {user1} 100004 --price 150 --description 'Worn twice'\n", "Success\n"},
+        {"GET_LISTING user1 100004\n", "Black shoes|Worn twice|150|2019-02-22 12:34:57|Sports|user1\n"},
+        // moving category keeps the category counts in step
+        {"UPDATE_LISTING {user1} 100004 --category Fashion\n", "Success\n"},
+        {"GET_CATEGORY user1 'Sports'\n", "Error - category not found\n"},
+        {"GET_TOP_CATEGORY user1\n", "Fashion\n"},
+
+        // buy listing
+        {"BUY {user1}\n", "Error - invalid number of arguments\n"},
+        {"GET_ORDERS \n", "Error - invalid number of arguments\n"},
+        {"BUY user3 100001\n", "Error - invalid session\n"},
+        {"GET_ORDERS user3\n", "Error - unknown user\n"},
+        {"BUY {user2} 100xxx\n", "Error - invalid input\n"},
+        {"BUY {user2} 900001\n", "Error - listing does not exist\n"},
+        {"BUY {user1} 100001\n", "Error - cannot buy or reserve own listing\n"},
+        {"GET_ORDERS user2\n", "Error - no orders found\n"},
+        {"BUY {user2} 100001\n", "Success\n"},
+        {"BUY {user2} 100001\n", "Error - listing already sold\n"},
+        // sold listings leave the category and its count
+        {"GET_CATEGORY user1 'Electronics'\n", "Error - category not found\n"},
+        {"GET_LISTING user1 100001\n", "Phone model 8|Black color, brand new|1000|2019-02-22 12:34:56|Electronics|user1\n"},
+        {"GET_TOP_CATEGORY user1\n", "Fashion\n"},
+        {"UPDATE_LISTING {user1} 100001 --price 900\n", "Error - listing already sold\n"},
+        {"DELETE_LISTING {user1} 100001\n", "Error - listing already sold\n"},
+        // orders are listed for both the buyer and the seller
+        {"GET_ORDERS user2\n", "100001|Phone model 8|1000|2019-02-22 12:34:59|user1|user2\n"},
+        {"GET_ORDERS user1\n", "100001|Phone model 8|1000|2019-02-22 12:34:59|user1|user2\n"},
+
+        // listing lifecycle
+        {"PUBLISH_LISTING {user2}\n", "Error - invalid number of arguments\n"},
+        {"PUBLISH_LISTING {user2} 100xxx\n", "Error - invalid input\n"},
+        {"CREATE_LISTING {user2} 'Jacket' 'Rain jacket' 80 Fashion --draft\n", "100005\n"},
+        // drafts are hidden and not counted until published
+        {"GET_CATEGORY user1 'Fashion'\n", "Black shoes|Worn twice|150|2019-02-22 12:34:57|Fashion|user1\n"},
+        {"RESERVE_LISTING {user1} 100005\n", "Error - cannot reserve a draft listing\n"},
+        {"PUBLISH_LISTING {user1} 100005\n", "Error - listing owner mismatch\n"},
+        {"PUBLISH_LISTING {user2} 100005\n", "Success\n"},
+        {"PUBLISH_LISTING {user2} 100005\n", "Error - cannot publish an active listing\n"},
+        {"GET_CATEGORY user1 'Fashion' sort_price asc\n", "Jacket|Rain jacket|80|2019-02-22 12:35:00|Fashion|user2\nBlack shoes|Worn twice|150|2019-02-22 12:34:57|Fashion|user1\n"},
+        {"RESERVE_LISTING {user2} 100005\n", "Error - cannot buy or reserve own listing\n"},
+        {"RESERVE_LISTING {user1} 100005\n", "Success\n"},
+        {"GET_CATEGORY user1 'Fashion' sort_price asc\n", "Black shoes|Worn twice|150|2019-02-22 12:34:57|Fashion|user1\n"},
+        {"UNRESERVE_LISTING {user1} 100005\n", "Success\n"},
+        {"UNRESERVE_LISTING {user2} 100005\n", "Error - cannot unreserve an active listing\n"},
+        {"WITHDRAW_LISTING {user2} 100005\n", "Success\n"},
+        {"WITHDRAW_LISTING {user2} 100005\n", "Error - cannot withdraw a withdrawn listing\n"},
+        {"UPDATE_LISTING {user2} 100005 --price 90\n", "Error - cannot update a withdrawn listing\n"},
+        {"BUY {user1} 100005\n", "Error - cannot buy a withdrawn listing\n"},
+        {"GET_CATEGORY user1 'Fashion' sort_price asc\n", "Black shoes|Worn twice|150|2019-02-22 12:34:57|Fashion|user1\n"},
+
+        // subcategories are listed under their ancestors, and counted in them
+        {"CREATE_CATEGORY {admin} 'Fashion/Shoes'\n", "Success\n"},
+        {"CREATE_CATEGORY {admin} 'Fashion/Shoes/Boots'\n", "Success\n"},
+        {"CREATE_LISTING {user1} 'Sneakers' 'White' 60 'Fashion/Shoes'\n", "100006\n"},
+        {"CREATE_LISTING {user1} 'Boots' 'Brown' 90 'Fashion/Shoes/Boots'\n", "100007\n"},
+        {"CREATE_LISTING {user1} 'Scarf' 'Wool' 30 'Fashion//Scarves'\n", "Error - invalid input\n"},
+        {"GET_CATEGORY user1 'Fashion' sort_price asc\n", "Sneakers|White|60|2019-02-22 12:35:01|Fashion/Shoes|user1\nBoots|Brown|90|2019-02-22 12:35:02|Fashion/Shoes/Boots|user1\nBlack shoes|Worn twice|150|2019-02-22 12:34:57|Fashion|user1\n"},
+        {"GET_CATEGORY user1 'Fashion/Shoes' sort_price dsc\n", "Boots|Brown|90|2019-02-22 12:35:02|Fashion/Shoes/Boots|user1\nSneakers|White|60|2019-02-22 12:35:01|Fashion/Shoes|user1\n"},
+        {"GET_TOP_CATEGORY user1\n", "Fashion\n"},
+        {"GET_TOP_CATEGORY user1 'Fashion'\n", "Fashion/Shoes\n"},
+        {"GET_TOP_CATEGORY user1 'Fashion/Shoes'\n", "Fashion/Shoes/Boots\n"},
+        {"GET_TOP_CATEGORY user1 'Electronics'\n", "Error - no category found\n"},
+        // ranked categories keep the metrics of the categories whose listings were all deleted
+        {"GET_TOP_CATEGORIES user1\n", "Fashion|3\nSports|0\nElectronics|0\n"},
+        {"GET_TOP_CATEGORIES user1 2\n", "Fashion|3\nSports|0\n"},
+        {"GET_TOP_CATEGORIES user1 --exclude-empty\n", "Fashion|3\n"},
+        {"GET_TOP_CATEGORIES user1 --parent fashion --exclude-empty\n", "Fashion/Shoes|2\n"},
+        {"GET_TOP_CATEGORIES user1 --parent Electronics\n", "Error - no category found\n"},
+        {"GET_TOP_CATEGORIES user1 0\n", "Error - invalid input\n"},
+        {"GET_TOP_CATEGORIES user1 101\n", "Error - invalid input\n"},
+        {"GET_TOP_CATEGORIES\n", "Error - invalid number of arguments\n"},
+
+        // category catalog
+        {"CREATE_CATEGORY {admin}\n", "Error - invalid number of arguments\n"},
+        {"CREATE_CATEGORY {user1} 'Toys'\n", "Error - permission denied\n"},
+        {"CREATE_CATEGORY {admin} 'Fashion'\n", "Error - category already existing\n"},
+        {"CREATE_CATEGORY {admin} 'Toys/Cars'\n", "Error - category does not exist\n"},
+        {"CREATE_LISTING {user1} 'Car' 'Red' 60 'Toys'\n", "Error - category does not exist\n"},
+        // renames and merges move the subcategories and their listings
+        {"RENAME_CATEGORY {admin} 'Fashion/Shoes' 'Fashion/Footwear'\n", "Success\n"},
+        {"GET_CATEGORY user1 'Fashion/Footwear' sort_price asc\n", "Sneakers|White|60|2019-02-22 12:35:01|Fashion/Footwear|user1\nBoots|Brown|90|2019-02-22 12:35:02|Fashion/Footwear/Boots|user1\n"},
+        {"RENAME_CATEGORY {admin} 'Fashion' 'Fashion/Old'\n", "Error - invalid input\n"},
+        {"RENAME_CATEGORY {admin} 'Fashion/Footwear' 'Sports'\n", "Error - category already existing\n"},
+        {"MERGE_CATEGORY {admin} 'Fashion/Footwear' 'Toys'\n", "Error - category does not exist\n"},
+        {"MERGE_CATEGORY {user1} 'Fashion/Footwear' 'Sports'\n", "Error - permission denied\n"},
+        {"MERGE_CATEGORY {admin} 'Fashion/Footwear' 'Sports'\n", "Success\n"},
+        {"GET_TOP_CATEGORY user1 'Sports'\n", "Sports/Boots\n"},
+        {"GET_TOP_CATEGORY user1\n", "Sports\n"},
+        // retired categories and their subcategories take no new listings
+        {"RETIRE_CATEGORY {user1} 'Sports'\n", "Error - permission denied\n"},
+        {"RETIRE_CATEGORY {admin} 'Toys'\n", "Error - category does not exist\n"},
+        {"RETIRE_CATEGORY {admin} 'Sports'\n", "Success\n"},
+        {"CREATE_LISTING {user1} 'Ball' 'Red' 60 'Sports/Boots'\n", "Error - category is retired\n"},
+        {"UPDATE_LISTING {user1} 100004 --category Sports\n", "Error - category is retired\n"},
+        {"GET_CATEGORY user1 'Sports' sort_price asc\n", "Sneakers|White|60|2019-02-22 12:35:01|Sports|user1\nBoots|Brown|90|2019-02-22 12:35:02|Sports/Boots|user1\n"},
+        {"GET_CATEGORIES user1\n", "Electronics|ACTIVE\nFashion|ACTIVE\nSports|RETIRED\nSports/Boots|ACTIVE\n"},
+
+        // usernames and category names are case-insensitive and keep their first spelling
+        {"REGISTER USER1 password1\n", "Error - user already existing\n"},
+        {"LOGIN ADMIN password3\n", "{token:ADMIN}\n"},
+        {"CREATE_CATEGORY {ADMIN} 'electronics'\n", "Error - category already existing\n"},
+        {"GET_CATEGORY User1 'SPORTS/boots' sort_price asc\n", "Boots|Brown|90|2019-02-22 12:35:02|Sports/Boots|user1\n"},
+        {"GET_TOP_CATEGORY USER1 'sports'\n", "Sports/Boots\n"},
+
+        // revoked sessions can no longer be used, while the other sessions of the user stay open
+        {"LOGOUT\n", "Error - invalid number of arguments\n"},
+        {"LOGIN user1 'password 1'\n", "{token:user1-second}\n"},
+        {"LOGOUT {user1}\n", "Success\n"},
+        {"CREATE_LISTING {user1} 'Ball' 'Red' 60 'Electronics'\n", "Error - invalid session\n"},
+        {"CREATE_LISTING {user1-second} 'Ball' 'Red' 60 'Electronics'\n", "100008\n"},
+
+        // moderators hide listings, and only admins grant roles and delete the listings of other users
+        {"GRANT_ROLE {admin} user2\n", "Error - invalid number of arguments\n"},
+        {"GRANT_ROLE {user1-second} user2 moderator\n", "Error - permission denied\n"},
+        {"GRANT_ROLE {admin} nobody moderator\n", "Error - unknown user\n"},
+        {"GRANT_ROLE {admin} user2 owner\n", "Error - invalid input\n"},
+        {"HIDE_LISTING {user2} 100008\n", "Error - permission denied\n"},
+        {"GRANT_ROLE {admin} USER2 moderator\n", "Success\n"},
+        {"HIDE_LISTING {user2} 100008\n", "Success\n"},
+        {"HIDE_LISTING {user2} 100008\n", "Error - cannot hide a hidden listing\n"},
+        {"RESERVE_LISTING {user2} 100008\n", "Error - cannot reserve a hidden listing\n"},
+        {"UNHIDE_LISTING {user2} 100008\n", "Success\n"},
+        {"DELETE_LISTING {user2} 100008\n", "Error - listing owner mismatch\n"},
+        {"DELETE_LISTING {admin} 100008\n", "Success\n"},
+        {"REVOKE_ROLE {user2} user2\n", "Error - permission denied\n"},
+        {"REVOKE_ROLE {admin} user2\n", "Success\n"},
+        {"HIDE_LISTING {user2} 100007\n", "Error - permission denied\n"},
+
+        // api keys act for their owner within their scope, captured as {user1-read} and their ID as {user1-read-id}
+        {"REGISTER mpk_user password1\n", "Error - invalid input\n"},
+        {"CREATE_API_KEY {user1-second}\n", "Error - invalid number of arguments\n"},
+        {"CREATE_API_KEY {user1-second} owner\n", "Error - invalid input\n"},
+        {"CREATE_API_KEY {user1-second} read-only soon\n", "Error - invalid input\n"},
+        {"CREATE_API_KEY {user1-second} read-only\n", "{key:user1-read}\n"},
+        {"CREATE_API_KEY {user1-second} LISTINGS:WRITE 1h\n", "{key:user1-write}\n"},
+        {"CREATE_API_KEY {user1-write} admin\n", "Error - invalid session\n"},
+        {"GET_API_KEYS {user2}\n", "Error - no api keys found\n"},
+        {"GET_TOP_CATEGORY {user1-read} 'sports'\n", "Sports/Boots\n"},
+        {"CREATE_LISTING {user1-read} 'Ball' 'Red' 60 'Electronics'\n", "Error - permission denied\n"},
+        {"CREATE_LISTING {user1-write} 'Ball' 'Red' 60 'Electronics'\n", "100009\n"},
+        {"CREATE_CATEGORY {user1-write} 'Toys'\n", "Error - permission denied\n"},
+        {"GET_TOP_CATEGORY mpk_0123456789abcdef_secret\n", "Error - invalid api key\n"},
+        {"REVOKE_API_KEY {user2} {user1-read-id}\n", "Error - api key does not exist\n"},
+        {"REVOKE_API_KEY {user1-second} {user1-read-id}\n", "Success\n"},
+        {"GET_TOP_CATEGORY {user1-read} 'sports'\n", "Error - invalid api key\n"},
+
+        // logins are limited to 5 attempts per username, failed or not
+        {"LOGIN USER1 wrong\n", "Error - invalid credentials\n"},
+        {"LOGIN user1 'password 1'\n", "Error - rate limited\n"},
+        {"LOGIN user2 password2\n", "{token:user2-second}\n"},
+
+        // commands with an idempotency key run once per key, and print the result of the first run on replay
+        {"CREATE_LISTING {user2-second} 'Kite' 'Blue' 30 'Electronics' --idempotency-key kite-1\n", "100010\n"},
+        {"CREATE_LISTING {user2-second} 'Kite' 'Blue' 30 'Electronics' --idempotency-key kite-1\n", "100010\n"},
+        {"CREATE_LISTING {user2-second} 'Kite' 'Red' 30 'Electronics' --idempotency-key kite-1\n", "Error - idempotency key reused\n"},
+        {"CREATE_LISTING {user2-second} 'Kite' 'Blue' 30 'Electronics' --idempotency-key\n", "Error - invalid input\n"},
+        {"DELETE_LISTING {user2-second} 100010 --idempotency-key delete-kite\n", "Success\n"},
+        {"DELETE_LISTING {user2-second} 100010 --idempotency-key delete-kite\n", "Success\n"},
+        {"DELETE_LISTING {user2-second} 100010\n", "Error - listing does not exist\n"},
     }
 
     // Create a buffer to hold the output
@@ -104,16 +361,52 @@ func TestIntegration(t *testing.T) {
     // Execute each test case
     fmt.Println("====Begin Test====")
 
+    reader := bufio.NewReader(stdout)
+    // the session tokens printed by LOGIN and the api keys printed by CREATE_API_KEY, keyed by the name of their
+    // placeholder
+    tokens := make(map[string]string)
     for _, tc := range testCases {
-        // Write the input to stdin
-        _, err = stdin.Write([]byte(tc.input + "\n"))
+        // Write the input to stdin, with the placeholders replaced by their token
+        input := tc.input
+        for name, token := range tokens {
+            input = strings.ReplaceAll(input, "{"+name+"}", token)
+        }
+        _, err = stdin.Write([]byte(input + "\n"))
         if err != nil {
             t.Fatalf("could not write to stdin: %v", err)
         }
 
+        // Capture the token of a successful login or a created api key, whose values are random
+        tokenName, isToken := strings.CutPrefix(tc.expected, "{token:")
+        keyName, isKey := strings.CutPrefix(tc.expected, "{key:")
+        if isToken || isKey {
+            line, err := reader.ReadString('\n')
+            if err != nil {
+                t.Fatalf("could not read from stdout: %v", err)
+            }
+            token := strings.TrimSuffix(line, "\n")
+            if isToken {
+                if len(token) != sessionTokenLength || strings.Contains(token, " ") {
+                    t.Fatalf("input %q: expected a session token, got %q", tc.input, line)
+                }
+                tokens[strings.TrimSuffix(tokenName, "}\n")] = token
+            } else {
+                keyId, _, ok := model.ParseApiKey(token)
+                if !ok {
+                    t.Fatalf("input %q: expected an api key, got %q", tc.input, line)
+                }
+                name := strings.TrimSuffix(keyName, "}\n")
+                tokens[name] = token
+                tokens[name+"-id"] = keyId
+            }
+            fmt.Println("\n====Test case passed: ")
+            fmt.Println("Input: ", tc.input)
+            continue
+        }
+
         // Read the outputBuffer from stdout
         buf := make([]byte, len(tc.expected))
-        _, err = stdout.Read(buf)
+        _, err = io.ReadFull(reader, buf)
         if err != nil {
             t.Fatalf("could not read from stdout: %v", err)
         }
@@ -162,8 +455,132 @@ func TestIntegration(t *testing.T) {
     }
 }
 
+func TestCommandTimeout(t *testing.T) {
+    // GET_LISTING runs out of time before the store answers, which only a backend waiting on a database notices
+    cmd := exec.Command("./main")
+    cmd.Env = append(os.Environ(),
+        constant.StorageBackendEnvKey+"="+constant.StorageBackendSqlite,
+        constant.SqlitePathEnvKey+"="+filepath.Join(t.TempDir(), "marketplace.db"),
+        constant.CommandTimeoutsEnvKey+"=GET_LISTING=1ns")
+    cmd.Stdin = strings.NewReader("REGISTER user1 password1\nGET_LISTING user1 100001\n")
+    output, err := cmd.Output()
+    if err != nil {
+        t.Fatalf("process did not exit cleanly: %v", err)
+    }
+
+    expected := "Success\nError - timeout\n"
+    if string(output) != expected {
+        t.Fatalf("expected %q, got %q", expected, output)
+    }
+}
+
+// storeEnv returns the environment of the explicitly configured storage backend, otherwise of a SQLite database in dir
+func storeEnv(dir string) []string {
+    if _, exists := os.LookupEnv(constant.StorageBackendEnvKey); exists {
+        return os.Environ()
+    }
+    return append(os.Environ(),
+        constant.StorageBackendEnvKey+"="+constant.StorageBackendSqlite,
+        constant.SqlitePathEnvKey+"="+filepath.Join(dir, "marketplace.db"))
+}
+
+// runSession runs the program with args over env, sending every command once the previous one was answered, with
+// "{token}" replaced by the token printed by the last LOGIN
+// Returns the first line answered to every command
+func runSession(t *testing.T, env []string, args []string, commands []string) []string {
+    t.Helper()
+    cmd := exec.Command("./main", args...)
+    cmd.Env = append(env, constant.AdminUsersEnvKey+"=admin")
+    stdin, err := cmd.StdinPipe()
+    if err != nil {
+        t.Fatalf("could not get stdin pipe: %v", err)
+    }
+    stdout, err := cmd.StdoutPipe()
+    if err != nil {
+        t.Fatalf("could not get stdout pipe: %v", err)
+    }
+    err = cmd.Start()
+    if err != nil {
+        t.Fatalf("could not start command: %v", err)
+    }
+
+    reader := bufio.NewReader(stdout)
+    var outputs []string
+    token := ""
+    for _, command := range commands {
+        _, err = io.WriteString(stdin, strings.ReplaceAll(command, "{token}", token)+"\n")
+        if err != nil {
+            t.Fatalf("could not write to stdin: %v", err)
+        }
+        output, err := reader.ReadString('\n')
+        if err != nil {
+            t.Fatalf("could not read from stdout: %v", err)
+        }
+        output = strings.TrimSuffix(output, "\n")
+        if strings.HasPrefix(command, "LOGIN ") {
+            token = output
+        }
+        outputs = append(outputs, output)
+    }
+
+    _ = stdin.Close()
+    err = cmd.Wait()
+    if err != nil {
+        t.Fatalf("process did not exit cleanly: %v", err)
+    }
+    return outputs
+}
+
+func TestRestartKeepsListings(t *testing.T) {
+    env := storeEnv(t.TempDir())
+
+    outputs := runSession(t, env, []string{"-reset"}, []string{
+        "REGISTER admin password1",
+        "LOGIN admin password1",
+        "CREATE_CATEGORY {token} 'Electronics'",
+        "CREATE_LISTING {token} 'Phone' 'Black' 1000 'Electronics'",
+        "CREATE_LISTING {token} 'Tablet' 'White' 2000 'Electronics'",
+    })
+    if outputs[3] != "100001" || outputs[4] != "100002" {
+        t.Fatalf("expected listings 100001 and 100002, got %q", outputs)
+    }
+
+    // the listings of the first run are kept, and new listings do not reuse their IDs
+    outputs = runSession(t, env, nil, []string{
+        "LOGIN admin password1",
+        "CREATE_LISTING {token} 'Laptop' 'Grey' 3000 'Electronics'",
+        "GET_LISTING admin 100002",
+    })
+    if outputs[1] != "100003" || !strings.HasPrefix(outputs[2], "Tablet|White|2000|") {
+        t.Fatalf("expected listing 100003 after the kept listings, got %q", outputs)
+    }
+}
+
+func TestRedactArgs(t *testing.T) {
+    testCases := []struct {
+        cmd      string
+        args     []string
+        expected string
+    }{
+        {"REGISTER", []string{"user1", "password 1"}, "user1, [REDACTED]"},
+        {"LOGIN", []string{"user1"}, "user1"},
+        {"CREATE_LISTING", []string{"token", "Phone", "Black", "100", "Electronics"}, "[REDACTED], Phone, Black, 100, Electronics"},
+        {"GET_LISTING", []string{"mpk_0123456789abcdef_secret", "100001"}, "[REDACTED], 100001"},
+        {"GET_LISTING", []string{"user1", "100001"}, "user1, 100001"},
+    }
+    for _, tc := range testCases {
+        redacted := strings.Join(redactArgs(tc.cmd, tc.args), ", ")
+        if redacted != tc.expected {
+            t.Fatalf("%s: expected %q, got %q", tc.cmd, tc.expected, redacted)
+        }
+    }
+}
+
+// sessionTokenLength is the length of the tokens printed by LOGIN
+const sessionTokenLength = 43
+
 func isTimestampField(index int) bool {
-    return index == 3 || index == 8
+    return index%5 == 3
 }
 
 func isMultilineListings(fieldCount int) bool {
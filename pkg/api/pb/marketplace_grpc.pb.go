@@ -0,0 +1,1371 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: marketplace.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MarketplaceService_Register_FullMethodName         = "/marketplace.v1.MarketplaceService/Register"
+	MarketplaceService_Login_FullMethodName            = "/marketplace.v1.MarketplaceService/Login"
+	MarketplaceService_Logout_FullMethodName           = "/marketplace.v1.MarketplaceService/Logout"
+	MarketplaceService_CreateApiKey_FullMethodName     = "/marketplace.v1.MarketplaceService/CreateApiKey"
+	MarketplaceService_GetApiKeys_FullMethodName       = "/marketplace.v1.MarketplaceService/GetApiKeys"
+	MarketplaceService_RevokeApiKey_FullMethodName     = "/marketplace.v1.MarketplaceService/RevokeApiKey"
+	MarketplaceService_CreateListing_FullMethodName    = "/marketplace.v1.MarketplaceService/CreateListing"
+	MarketplaceService_GetListing_FullMethodName       = "/marketplace.v1.MarketplaceService/GetListing"
+	MarketplaceService_GetCategory_FullMethodName      = "/marketplace.v1.MarketplaceService/GetCategory"
+	MarketplaceService_SearchListings_FullMethodName   = "/marketplace.v1.MarketplaceService/SearchListings"
+	MarketplaceService_GetTopCategory_FullMethodName   = "/marketplace.v1.MarketplaceService/GetTopCategory"
+	MarketplaceService_GetTopCategories_FullMethodName = "/marketplace.v1.MarketplaceService/GetTopCategories"
+	MarketplaceService_UpdateListing_FullMethodName    = "/marketplace.v1.MarketplaceService/UpdateListing"
+	MarketplaceService_DeleteListing_FullMethodName    = "/marketplace.v1.MarketplaceService/DeleteListing"
+	MarketplaceService_PublishListing_FullMethodName   = "/marketplace.v1.MarketplaceService/PublishListing"
+	MarketplaceService_ReserveListing_FullMethodName   = "/marketplace.v1.MarketplaceService/ReserveListing"
+	MarketplaceService_UnreserveListing_FullMethodName = "/marketplace.v1.MarketplaceService/UnreserveListing"
+	MarketplaceService_WithdrawListing_FullMethodName  = "/marketplace.v1.MarketplaceService/WithdrawListing"
+	MarketplaceService_HideListing_FullMethodName      = "/marketplace.v1.MarketplaceService/HideListing"
+	MarketplaceService_UnhideListing_FullMethodName    = "/marketplace.v1.MarketplaceService/UnhideListing"
+	MarketplaceService_BuyListing_FullMethodName       = "/marketplace.v1.MarketplaceService/BuyListing"
+	MarketplaceService_GetOrders_FullMethodName        = "/marketplace.v1.MarketplaceService/GetOrders"
+	MarketplaceService_CreateCategory_FullMethodName   = "/marketplace.v1.MarketplaceService/CreateCategory"
+	MarketplaceService_GetCategories_FullMethodName    = "/marketplace.v1.MarketplaceService/GetCategories"
+	MarketplaceService_RenameCategory_FullMethodName   = "/marketplace.v1.MarketplaceService/RenameCategory"
+	MarketplaceService_MergeCategory_FullMethodName    = "/marketplace.v1.MarketplaceService/MergeCategory"
+	MarketplaceService_RetireCategory_FullMethodName   = "/marketplace.v1.MarketplaceService/RetireCategory"
+	MarketplaceService_GrantRole_FullMethodName        = "/marketplace.v1.MarketplaceService/GrantRole"
+	MarketplaceService_RevokeRole_FullMethodName       = "/marketplace.v1.MarketplaceService/RevokeRole"
+)
+
+// MarketplaceServiceClient is the client API for MarketplaceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MarketplaceServiceClient interface {
+	// Register registers a new user with a password (REGISTER)
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*User, error)
+	// Login opens a session of a user, returning the token to pass to the mutating RPCs (LOGIN)
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*Session, error)
+	// Logout revokes the session of a token (LOGOUT)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// CreateApiKey creates an API key of the calling user, returned only once (CREATE_API_KEY)
+	CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*ApiKey, error)
+	// GetApiKeys streams the API keys of the calling user without the keys themselves, oldest first (GET_API_KEYS)
+	GetApiKeys(ctx context.Context, in *GetApiKeysRequest, opts ...grpc.CallOption) (MarketplaceService_GetApiKeysClient, error)
+	// RevokeApiKey revokes an API key of the calling user (REVOKE_API_KEY)
+	RevokeApiKey(ctx context.Context, in *RevokeApiKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// CreateListing creates a listing owned by the calling user (CREATE_LISTING)
+	CreateListing(ctx context.Context, in *CreateListingRequest, opts ...grpc.CallOption) (*Listing, error)
+	// GetListing retrieves a listing by ID (GET_LISTING)
+	GetListing(ctx context.Context, in *GetListingRequest, opts ...grpc.CallOption) (*Listing, error)
+	// GetCategory streams a page of the listings of a category and its subcategories in the requested order (GET_CATEGORY)
+	// The token of the next page is returned in the "next-page-token" trailer, which is absent on the last page.
+	GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (MarketplaceService_GetCategoryClient, error)
+	// SearchListings streams the active listings matching a full-text query, most relevant first (SEARCH)
+	SearchListings(ctx context.Context, in *SearchListingsRequest, opts ...grpc.CallOption) (MarketplaceService_SearchListingsClient, error)
+	// GetTopCategory retrieves the top-level category, or subcategory of a parent, with the most listings (GET_TOP_CATEGORY)
+	GetTopCategory(ctx context.Context, in *GetTopCategoryRequest, opts ...grpc.CallOption) (*CategoryMetric, error)
+	// GetTopCategories streams the top-level categories, or subcategories of a parent, ranked by their number of
+	// listings with ties broken by descending name (GET_TOP_CATEGORIES)
+	GetTopCategories(ctx context.Context, in *GetTopCategoriesRequest, opts ...grpc.CallOption) (MarketplaceService_GetTopCategoriesClient, error)
+	// UpdateListing changes the fields that are set on a listing owned by the calling user (UPDATE_LISTING)
+	UpdateListing(ctx context.Context, in *UpdateListingRequest, opts ...grpc.CallOption) (*Listing, error)
+	// DeleteListing deletes a listing owned by the calling user, or any listing for admins (DELETE_LISTING)
+	DeleteListing(ctx context.Context, in *DeleteListingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// PublishListing publishes a draft or expired listing owned by the calling user (PUBLISH_LISTING)
+	PublishListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error)
+	// ReserveListing reserves an active listing for the calling user (RESERVE_LISTING)
+	ReserveListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error)
+	// UnreserveListing releases a reservation held by or on a listing of the calling user (UNRESERVE_LISTING)
+	UnreserveListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error)
+	// WithdrawListing withdraws a listing owned by the calling user for good (WITHDRAW_LISTING)
+	WithdrawListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error)
+	// HideListing hides an active or reserved listing, for moderators and admins only (HIDE_LISTING)
+	HideListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error)
+	// UnhideListing makes a hidden listing active again, for moderators and admins only (UNHIDE_LISTING)
+	UnhideListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error)
+	// BuyListing buys a listing on behalf of the calling user and returns the order (BUY)
+	BuyListing(ctx context.Context, in *BuyListingRequest, opts ...grpc.CallOption) (*Order, error)
+	// GetOrders streams the orders of the calling user as buyer or seller, newest first (GET_ORDERS)
+	GetOrders(ctx context.Context, in *GetOrdersRequest, opts ...grpc.CallOption) (MarketplaceService_GetOrdersClient, error)
+	// CreateCategory adds a category to the catalog, for admins only (CREATE_CATEGORY)
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	// GetCategories streams the category catalog sorted by name (GET_CATEGORIES)
+	GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (MarketplaceService_GetCategoriesClient, error)
+	// RenameCategory renames a category and its subcategories, moving their listings, for admins only (RENAME_CATEGORY)
+	RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// MergeCategory merges a category and its subcategories into another category, for admins only (MERGE_CATEGORY)
+	MergeCategory(ctx context.Context, in *MergeCategoryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RetireCategory closes a category and its subcategories to new listings, for admins only (RETIRE_CATEGORY)
+	RetireCategory(ctx context.Context, in *RetireCategoryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GrantRole grants the MODERATOR or ADMIN role to a user, for admins only (GRANT_ROLE)
+	GrantRole(ctx context.Context, in *GrantRoleRequest, opts ...grpc.CallOption) (*User, error)
+	// RevokeRole makes a user a regular user again, for admins only (REVOKE_ROLE)
+	RevokeRole(ctx context.Context, in *RevokeRoleRequest, opts ...grpc.CallOption) (*User, error)
+}
+
+type marketplaceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMarketplaceServiceClient(cc grpc.ClientConnInterface) MarketplaceServiceClient {
+	return &marketplaceServiceClient{cc}
+}
+
+func (c *marketplaceServiceClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, MarketplaceService_Register_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, MarketplaceService_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MarketplaceService_Logout_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*ApiKey, error) {
+	out := new(ApiKey)
+	err := c.cc.Invoke(ctx, MarketplaceService_CreateApiKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GetApiKeys(ctx context.Context, in *GetApiKeysRequest, opts ...grpc.CallOption) (MarketplaceService_GetApiKeysClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[0], MarketplaceService_GetApiKeys_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceGetApiKeysClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_GetApiKeysClient interface {
+	Recv() (*ApiKey, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceGetApiKeysClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceGetApiKeysClient) Recv() (*ApiKey, error) {
+	m := new(ApiKey)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketplaceServiceClient) RevokeApiKey(ctx context.Context, in *RevokeApiKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MarketplaceService_RevokeApiKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) CreateListing(ctx context.Context, in *CreateListingRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_CreateListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GetListing(ctx context.Context, in *GetListingRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_GetListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (MarketplaceService_GetCategoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[1], MarketplaceService_GetCategory_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceGetCategoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_GetCategoryClient interface {
+	Recv() (*Listing, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceGetCategoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceGetCategoryClient) Recv() (*Listing, error) {
+	m := new(Listing)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketplaceServiceClient) SearchListings(ctx context.Context, in *SearchListingsRequest, opts ...grpc.CallOption) (MarketplaceService_SearchListingsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[2], MarketplaceService_SearchListings_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceSearchListingsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_SearchListingsClient interface {
+	Recv() (*Listing, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceSearchListingsClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceSearchListingsClient) Recv() (*Listing, error) {
+	m := new(Listing)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketplaceServiceClient) GetTopCategory(ctx context.Context, in *GetTopCategoryRequest, opts ...grpc.CallOption) (*CategoryMetric, error) {
+	out := new(CategoryMetric)
+	err := c.cc.Invoke(ctx, MarketplaceService_GetTopCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GetTopCategories(ctx context.Context, in *GetTopCategoriesRequest, opts ...grpc.CallOption) (MarketplaceService_GetTopCategoriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[3], MarketplaceService_GetTopCategories_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceGetTopCategoriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_GetTopCategoriesClient interface {
+	Recv() (*CategoryMetric, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceGetTopCategoriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceGetTopCategoriesClient) Recv() (*CategoryMetric, error) {
+	m := new(CategoryMetric)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketplaceServiceClient) UpdateListing(ctx context.Context, in *UpdateListingRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_UpdateListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) DeleteListing(ctx context.Context, in *DeleteListingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MarketplaceService_DeleteListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) PublishListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_PublishListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) ReserveListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_ReserveListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) UnreserveListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_UnreserveListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) WithdrawListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_WithdrawListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) HideListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_HideListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) UnhideListing(ctx context.Context, in *ListingTransitionRequest, opts ...grpc.CallOption) (*Listing, error) {
+	out := new(Listing)
+	err := c.cc.Invoke(ctx, MarketplaceService_UnhideListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) BuyListing(ctx context.Context, in *BuyListingRequest, opts ...grpc.CallOption) (*Order, error) {
+	out := new(Order)
+	err := c.cc.Invoke(ctx, MarketplaceService_BuyListing_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GetOrders(ctx context.Context, in *GetOrdersRequest, opts ...grpc.CallOption) (MarketplaceService_GetOrdersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[4], MarketplaceService_GetOrders_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceGetOrdersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_GetOrdersClient interface {
+	Recv() (*Order, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceGetOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceGetOrdersClient) Recv() (*Order, error) {
+	m := new(Order)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketplaceServiceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	out := new(Category)
+	err := c.cc.Invoke(ctx, MarketplaceService_CreateCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (MarketplaceService_GetCategoriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MarketplaceService_ServiceDesc.Streams[5], MarketplaceService_GetCategories_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &marketplaceServiceGetCategoriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MarketplaceService_GetCategoriesClient interface {
+	Recv() (*Category, error)
+	grpc.ClientStream
+}
+
+type marketplaceServiceGetCategoriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *marketplaceServiceGetCategoriesClient) Recv() (*Category, error) {
+	m := new(Category)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *marketplaceServiceClient) RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MarketplaceService_RenameCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) MergeCategory(ctx context.Context, in *MergeCategoryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MarketplaceService_MergeCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) RetireCategory(ctx context.Context, in *RetireCategoryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MarketplaceService_RetireCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) GrantRole(ctx context.Context, in *GrantRoleRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, MarketplaceService_GrantRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketplaceServiceClient) RevokeRole(ctx context.Context, in *RevokeRoleRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, MarketplaceService_RevokeRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MarketplaceServiceServer is the server API for MarketplaceService service.
+// All implementations must embed UnimplementedMarketplaceServiceServer
+// for forward compatibility
+type MarketplaceServiceServer interface {
+	// Register registers a new user with a password (REGISTER)
+	Register(context.Context, *RegisterRequest) (*User, error)
+	// Login opens a session of a user, returning the token to pass to the mutating RPCs (LOGIN)
+	Login(context.Context, *LoginRequest) (*Session, error)
+	// Logout revokes the session of a token (LOGOUT)
+	Logout(context.Context, *LogoutRequest) (*emptypb.Empty, error)
+	// CreateApiKey creates an API key of the calling user, returned only once (CREATE_API_KEY)
+	CreateApiKey(context.Context, *CreateApiKeyRequest) (*ApiKey, error)
+	// GetApiKeys streams the API keys of the calling user without the keys themselves, oldest first (GET_API_KEYS)
+	GetApiKeys(*GetApiKeysRequest, MarketplaceService_GetApiKeysServer) error
+	// RevokeApiKey revokes an API key of the calling user (REVOKE_API_KEY)
+	RevokeApiKey(context.Context, *RevokeApiKeyRequest) (*emptypb.Empty, error)
+	// CreateListing creates a listing owned by the calling user (CREATE_LISTING)
+	CreateListing(context.Context, *CreateListingRequest) (*Listing, error)
+	// GetListing retrieves a listing by ID (GET_LISTING)
+	GetListing(context.Context, *GetListingRequest) (*Listing, error)
+	// GetCategory streams a page of the listings of a category and its subcategories in the requested order (GET_CATEGORY)
+	// The token of the next page is returned in the "next-page-token" trailer, which is absent on the last page.
+	GetCategory(*GetCategoryRequest, MarketplaceService_GetCategoryServer) error
+	// SearchListings streams the active listings matching a full-text query, most relevant first (SEARCH)
+	SearchListings(*SearchListingsRequest, MarketplaceService_SearchListingsServer) error
+	// GetTopCategory retrieves the top-level category, or subcategory of a parent, with the most listings (GET_TOP_CATEGORY)
+	GetTopCategory(context.Context, *GetTopCategoryRequest) (*CategoryMetric, error)
+	// GetTopCategories streams the top-level categories, or subcategories of a parent, ranked by their number of
+	// listings with ties broken by descending name (GET_TOP_CATEGORIES)
+	GetTopCategories(*GetTopCategoriesRequest, MarketplaceService_GetTopCategoriesServer) error
+	// UpdateListing changes the fields that are set on a listing owned by the calling user (UPDATE_LISTING)
+	UpdateListing(context.Context, *UpdateListingRequest) (*Listing, error)
+	// DeleteListing deletes a listing owned by the calling user, or any listing for admins (DELETE_LISTING)
+	DeleteListing(context.Context, *DeleteListingRequest) (*emptypb.Empty, error)
+	// PublishListing publishes a draft or expired listing owned by the calling user (PUBLISH_LISTING)
+	PublishListing(context.Context, *ListingTransitionRequest) (*Listing, error)
+	// ReserveListing reserves an active listing for the calling user (RESERVE_LISTING)
+	ReserveListing(context.Context, *ListingTransitionRequest) (*Listing, error)
+	// UnreserveListing releases a reservation held by or on a listing of the calling user (UNRESERVE_LISTING)
+	UnreserveListing(context.Context, *ListingTransitionRequest) (*Listing, error)
+	// WithdrawListing withdraws a listing owned by the calling user for good (WITHDRAW_LISTING)
+	WithdrawListing(context.Context, *ListingTransitionRequest) (*Listing, error)
+	// HideListing hides an active or reserved listing, for moderators and admins only (HIDE_LISTING)
+	HideListing(context.Context, *ListingTransitionRequest) (*Listing, error)
+	// UnhideListing makes a hidden listing active again, for moderators and admins only (UNHIDE_LISTING)
+	UnhideListing(context.Context, *ListingTransitionRequest) (*Listing, error)
+	// BuyListing buys a listing on behalf of the calling user and returns the order (BUY)
+	BuyListing(context.Context, *BuyListingRequest) (*Order, error)
+	// GetOrders streams the orders of the calling user as buyer or seller, newest first (GET_ORDERS)
+	GetOrders(*GetOrdersRequest, MarketplaceService_GetOrdersServer) error
+	// CreateCategory adds a category to the catalog, for admins only (CREATE_CATEGORY)
+	CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error)
+	// GetCategories streams the category catalog sorted by name (GET_CATEGORIES)
+	GetCategories(*GetCategoriesRequest, MarketplaceService_GetCategoriesServer) error
+	// RenameCategory renames a category and its subcategories, moving their listings, for admins only (RENAME_CATEGORY)
+	RenameCategory(context.Context, *RenameCategoryRequest) (*emptypb.Empty, error)
+	// MergeCategory merges a category and its subcategories into another category, for admins only (MERGE_CATEGORY)
+	MergeCategory(context.Context, *MergeCategoryRequest) (*emptypb.Empty, error)
+	// RetireCategory closes a category and its subcategories to new listings, for admins only (RETIRE_CATEGORY)
+	RetireCategory(context.Context, *RetireCategoryRequest) (*emptypb.Empty, error)
+	// GrantRole grants the MODERATOR or ADMIN role to a user, for admins only (GRANT_ROLE)
+	GrantRole(context.Context, *GrantRoleRequest) (*User, error)
+	// RevokeRole makes a user a regular user again, for admins only (REVOKE_ROLE)
+	RevokeRole(context.Context, *RevokeRoleRequest) (*User, error)
+	mustEmbedUnimplementedMarketplaceServiceServer()
+}
+
+// UnimplementedMarketplaceServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMarketplaceServiceServer struct {
+}
+
+func (UnimplementedMarketplaceServiceServer) Register(context.Context, *RegisterRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) Login(context.Context, *LoginRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) Logout(context.Context, *LogoutRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) CreateApiKey(context.Context, *CreateApiKeyRequest) (*ApiKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetApiKeys(*GetApiKeysRequest, MarketplaceService_GetApiKeysServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetApiKeys not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) RevokeApiKey(context.Context, *RevokeApiKeyRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeApiKey not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) CreateListing(context.Context, *CreateListingRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetListing(context.Context, *GetListingRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetCategory(*GetCategoryRequest, MarketplaceService_GetCategoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetCategory not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) SearchListings(*SearchListingsRequest, MarketplaceService_SearchListingsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchListings not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetTopCategory(context.Context, *GetTopCategoryRequest) (*CategoryMetric, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopCategory not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetTopCategories(*GetTopCategoriesRequest, MarketplaceService_GetTopCategoriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetTopCategories not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) UpdateListing(context.Context, *UpdateListingRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) DeleteListing(context.Context, *DeleteListingRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) PublishListing(context.Context, *ListingTransitionRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) ReserveListing(context.Context, *ListingTransitionRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) UnreserveListing(context.Context, *ListingTransitionRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnreserveListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) WithdrawListing(context.Context, *ListingTransitionRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithdrawListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) HideListing(context.Context, *ListingTransitionRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HideListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) UnhideListing(context.Context, *ListingTransitionRequest) (*Listing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnhideListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) BuyListing(context.Context, *BuyListingRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuyListing not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetOrders(*GetOrdersRequest, MarketplaceService_GetOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetOrders not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GetCategories(*GetCategoriesRequest, MarketplaceService_GetCategoriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetCategories not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) RenameCategory(context.Context, *RenameCategoryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameCategory not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) MergeCategory(context.Context, *MergeCategoryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCategory not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) RetireCategory(context.Context, *RetireCategoryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetireCategory not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) GrantRole(context.Context, *GrantRoleRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantRole not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) RevokeRole(context.Context, *RevokeRoleRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRole not implemented")
+}
+func (UnimplementedMarketplaceServiceServer) mustEmbedUnimplementedMarketplaceServiceServer() {}
+
+// UnsafeMarketplaceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MarketplaceServiceServer will
+// result in compilation errors.
+type UnsafeMarketplaceServiceServer interface {
+	mustEmbedUnimplementedMarketplaceServiceServer()
+}
+
+func RegisterMarketplaceServiceServer(s grpc.ServiceRegistrar, srv MarketplaceServiceServer) {
+	s.RegisterService(&MarketplaceService_ServiceDesc, srv)
+}
+
+func _MarketplaceService_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).CreateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_CreateApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).CreateApiKey(ctx, req.(*CreateApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GetApiKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetApiKeysRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).GetApiKeys(m, &marketplaceServiceGetApiKeysServer{stream})
+}
+
+type MarketplaceService_GetApiKeysServer interface {
+	Send(*ApiKey) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceGetApiKeysServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceGetApiKeysServer) Send(m *ApiKey) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketplaceService_RevokeApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).RevokeApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_RevokeApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).RevokeApiKey(ctx, req.(*RevokeApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_CreateListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).CreateListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_CreateListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).CreateListing(ctx, req.(*CreateListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GetListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).GetListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_GetListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).GetListing(ctx, req.(*GetListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GetCategory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetCategoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).GetCategory(m, &marketplaceServiceGetCategoryServer{stream})
+}
+
+type MarketplaceService_GetCategoryServer interface {
+	Send(*Listing) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceGetCategoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceGetCategoryServer) Send(m *Listing) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketplaceService_SearchListings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchListingsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).SearchListings(m, &marketplaceServiceSearchListingsServer{stream})
+}
+
+type MarketplaceService_SearchListingsServer interface {
+	Send(*Listing) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceSearchListingsServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceSearchListingsServer) Send(m *Listing) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketplaceService_GetTopCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).GetTopCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_GetTopCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).GetTopCategory(ctx, req.(*GetTopCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GetTopCategories_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTopCategoriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).GetTopCategories(m, &marketplaceServiceGetTopCategoriesServer{stream})
+}
+
+type MarketplaceService_GetTopCategoriesServer interface {
+	Send(*CategoryMetric) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceGetTopCategoriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceGetTopCategoriesServer) Send(m *CategoryMetric) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketplaceService_UpdateListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).UpdateListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_UpdateListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).UpdateListing(ctx, req.(*UpdateListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_DeleteListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).DeleteListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_DeleteListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).DeleteListing(ctx, req.(*DeleteListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_PublishListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).PublishListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_PublishListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).PublishListing(ctx, req.(*ListingTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_ReserveListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).ReserveListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_ReserveListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).ReserveListing(ctx, req.(*ListingTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_UnreserveListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).UnreserveListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_UnreserveListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).UnreserveListing(ctx, req.(*ListingTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_WithdrawListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).WithdrawListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_WithdrawListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).WithdrawListing(ctx, req.(*ListingTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_HideListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).HideListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_HideListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).HideListing(ctx, req.(*ListingTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_UnhideListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListingTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).UnhideListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_UnhideListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).UnhideListing(ctx, req.(*ListingTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_BuyListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuyListingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).BuyListing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_BuyListing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).BuyListing(ctx, req.(*BuyListingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GetOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).GetOrders(m, &marketplaceServiceGetOrdersServer{stream})
+}
+
+type MarketplaceService_GetOrdersServer interface {
+	Send(*Order) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceGetOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceGetOrdersServer) Send(m *Order) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketplaceService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GetCategories_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetCategoriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketplaceServiceServer).GetCategories(m, &marketplaceServiceGetCategoriesServer{stream})
+}
+
+type MarketplaceService_GetCategoriesServer interface {
+	Send(*Category) error
+	grpc.ServerStream
+}
+
+type marketplaceServiceGetCategoriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *marketplaceServiceGetCategoriesServer) Send(m *Category) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MarketplaceService_RenameCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).RenameCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_RenameCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).RenameCategory(ctx, req.(*RenameCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_MergeCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).MergeCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_MergeCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).MergeCategory(ctx, req.(*MergeCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_RetireCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetireCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).RetireCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_RetireCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).RetireCategory(ctx, req.(*RetireCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_GrantRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).GrantRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_GrantRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).GrantRole(ctx, req.(*GrantRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketplaceService_RevokeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketplaceServiceServer).RevokeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketplaceService_RevokeRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketplaceServiceServer).RevokeRole(ctx, req.(*RevokeRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MarketplaceService_ServiceDesc is the grpc.ServiceDesc for MarketplaceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MarketplaceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "marketplace.v1.MarketplaceService",
+	HandlerType: (*MarketplaceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _MarketplaceService_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _MarketplaceService_Login_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _MarketplaceService_Logout_Handler,
+		},
+		{
+			MethodName: "CreateApiKey",
+			Handler:    _MarketplaceService_CreateApiKey_Handler,
+		},
+		{
+			MethodName: "RevokeApiKey",
+			Handler:    _MarketplaceService_RevokeApiKey_Handler,
+		},
+		{
+			MethodName: "CreateListing",
+			Handler:    _MarketplaceService_CreateListing_Handler,
+		},
+		{
+			MethodName: "GetListing",
+			Handler:    _MarketplaceService_GetListing_Handler,
+		},
+		{
+			MethodName: "GetTopCategory",
+			Handler:    _MarketplaceService_GetTopCategory_Handler,
+		},
+		{
+			MethodName: "UpdateListing",
+			Handler:    _MarketplaceService_UpdateListing_Handler,
+		},
+		{
+			MethodName: "DeleteListing",
+			Handler:    _MarketplaceService_DeleteListing_Handler,
+		},
+		{
+			MethodName: "PublishListing",
+			Handler:    _MarketplaceService_PublishListing_Handler,
+		},
+		{
+			MethodName: "ReserveListing",
+			Handler:    _MarketplaceService_ReserveListing_Handler,
+		},
+		{
+			MethodName: "UnreserveListing",
+			Handler:    _MarketplaceService_UnreserveListing_Handler,
+		},
+		{
+			MethodName: "WithdrawListing",
+			Handler:    _MarketplaceService_WithdrawListing_Handler,
+		},
+		{
+			MethodName: "HideListing",
+			Handler:    _MarketplaceService_HideListing_Handler,
+		},
+		{
+			MethodName: "UnhideListing",
+			Handler:    _MarketplaceService_UnhideListing_Handler,
+		},
+		{
+			MethodName: "BuyListing",
+			Handler:    _MarketplaceService_BuyListing_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _MarketplaceService_CreateCategory_Handler,
+		},
+		{
+			MethodName: "RenameCategory",
+			Handler:    _MarketplaceService_RenameCategory_Handler,
+		},
+		{
+			MethodName: "MergeCategory",
+			Handler:    _MarketplaceService_MergeCategory_Handler,
+		},
+		{
+			MethodName: "RetireCategory",
+			Handler:    _MarketplaceService_RetireCategory_Handler,
+		},
+		{
+			MethodName: "GrantRole",
+			Handler:    _MarketplaceService_GrantRole_Handler,
+		},
+		{
+			MethodName: "RevokeRole",
+			Handler:    _MarketplaceService_RevokeRole_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetApiKeys",
+			Handler:       _MarketplaceService_GetApiKeys_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetCategory",
+			Handler:       _MarketplaceService_GetCategory_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchListings",
+			Handler:       _MarketplaceService_SearchListings_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetTopCategories",
+			Handler:       _MarketplaceService_GetTopCategories_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetOrders",
+			Handler:       _MarketplaceService_GetOrders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetCategories",
+			Handler:       _MarketplaceService_GetCategories_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "marketplace.proto",
+}
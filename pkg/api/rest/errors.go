@@ -0,0 +1,41 @@
+package rest
+
+import (
+    "marketplace-platform/pkg/exception"
+    "net/http"
+)
+
+// statusOfKind maps the kinds of marketplace errors to HTTP status codes, internal errors to 500
+var statusOfKind = map[exception.Kind]int{
+    exception.KindTimeout:                 http.StatusGatewayTimeout,
+    exception.KindCancelled:               http.StatusServiceUnavailable,
+    exception.KindUnknownUser:             http.StatusUnauthorized,
+    exception.KindInvalidCredentials:      http.StatusUnauthorized,
+    exception.KindInvalidSession:          http.StatusUnauthorized,
+    exception.KindInvalidApiKey:           http.StatusUnauthorized,
+    exception.KindApiKeyDoesNotExist:      http.StatusNotFound,
+    exception.KindRateLimited:             http.StatusTooManyRequests,
+    exception.KindIdempotencyKeyReused:    http.StatusUnprocessableEntity,
+    exception.KindRequestInProgress:       http.StatusConflict,
+    exception.KindOwnershipMismatch:       http.StatusForbidden,
+    exception.KindListingDoesNotExist:     http.StatusNotFound,
+    exception.KindStaleListing:            http.StatusConflict,
+    exception.KindListingSold:             http.StatusConflict,
+    exception.KindSelfPurchase:            http.StatusForbidden,
+    exception.KindInvalidStatusTransition: http.StatusConflict,
+    exception.KindPermissionDenied:        http.StatusForbidden,
+    exception.KindCategoryDoesNotExist:    http.StatusNotFound,
+    exception.KindCategoryAlreadyExist:    http.StatusConflict,
+    exception.KindCategoryRetired:         http.StatusConflict,
+    exception.KindInvalidInput:            http.StatusBadRequest,
+}
+
+// statusOf maps a marketplace error to an HTTP status code and the message of the matching CLI error
+func statusOf(err error) (int, string) {
+    kind, message := exception.Classify(err)
+    status, exists := statusOfKind[kind]
+    if !exists {
+        return http.StatusInternalServerError, message
+    }
+    return status, message
+}
@@ -0,0 +1,795 @@
+package rest
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "expvar"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/service"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// UsernameHeader identifies the calling user on every reading request
+const UsernameHeader = "X-Username"
+
+// ApiKeyHeader carries an API key, used in place of both the username and the session token when present
+const ApiKeyHeader = "X-Api-Key"
+
+// AuthorizationHeader carries the token of a session opened with POST /sessions, as "Bearer <token>", on every
+// mutating request
+const AuthorizationHeader = "Authorization"
+
+const bearerPrefix = "Bearer "
+
+// IdempotencyKeyHeader carries an optional key of a mutating request, which runs once per key and is answered with the
+// response of the first run on replay
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxRequestBodySize bounds the size of request bodies in bytes, larger bodies are rejected with 413
+const MaxRequestBodySize = 1 << 20
+
+// NextCursorHeader carries the cursor of the next page of a paginated response, and is absent on the last page
+const NextCursorHeader = "X-Next-Cursor"
+
+// Server exposes the marketplace operations as REST resources with JSON bodies:
+//
+//  POST   /users                                        register a user with a password
+//  POST   /sessions                                     log in, returning the token of a new session
+//  DELETE /sessions                                     log out, revoking the session of the token
+//  POST   /api-keys                                     create an API key of the calling user
+//  GET    /api-keys                                     get the API keys of the calling user
+//  DELETE /api-keys/{id}                                revoke an API key of the calling user
+//  POST   /listings                                     create a listing
+//  GET    /listings/{id}                                get a listing
+//  PATCH  /listings/{id}                                update the given fields of a listing
+//  DELETE /listings/{id}                                delete a listing, of any user for admins
+//  POST   /listings/{id}/publish                        publish a draft or expired listing
+//  POST   /listings/{id}/reserve                        reserve a listing
+//  POST   /listings/{id}/unreserve                      release a reservation
+//  POST   /listings/{id}/withdraw                       withdraw a listing
+//  POST   /listings/{id}/hide                           hide a listing (moderators and admins only)
+//  POST   /listings/{id}/unhide                         show a hidden listing again (moderators and admins only)
+//  POST   /listings/{id}/purchase                       buy a listing
+//  GET    /orders                                       get the orders of the calling user as buyer or seller
+//  GET    /categories/{name}/listings?sort=price&order=asc&limit=20&cursor=X  get a page of the listings of a category
+//                                                       and its subcategories, named by paths such as Electronics/Phones
+//         &minPrice=5000&maxPrice=20000&createdAfter=2019-02-22T00:00:00Z&createdBefore=...&title=Phone  filtered
+//  GET    /categories/top?parent=Electronics            get the top-level category, or subcategory of parent, with the
+//                                                       most listings
+//  GET    /categories/ranking?parent=Electronics&limit=10&excludeEmpty=true  rank categories by their number of listings
+//  GET    /search?q=phone&category=X&minPrice=5000&maxPrice=20000&limit=20  search listings, most relevant first
+//  GET    /categories                                   get the category catalog
+//  POST   /categories                                   add a category to the catalog (admins only)
+//  POST   /categories/{name}/rename                     rename a category and its subcategories (admins only)
+//  POST   /categories/{name}/merge                      merge a category into another one (admins only)
+//  POST   /categories/{name}/retire                     close a category to new listings (admins only)
+//  PUT    /users/{name}/role                            grant the MODERATOR or ADMIN role to a user (admins only)
+//  DELETE /users/{name}/role                            make a user a regular user again (admins only)
+//  GET    /debug/vars                                   get the expvar metrics, such as the DynamoDB request counts
+type Server struct {
+    marketplace *service.Marketplace
+    log         *zap.SugaredLogger
+}
+
+func NewServer(marketplace *service.Marketplace, log *zap.SugaredLogger) *Server {
+    return &Server{
+        marketplace: marketplace,
+        log:         log,
+    }
+}
+
+// credentialsRequest is the body of both registration and login
+type credentialsRequest struct {
+    Username string `json:"username"`
+    Password string `json:"password"`
+}
+
+type sessionResponse struct {
+    Token     string    `json:"token"`
+    Username  string    `json:"username"`
+    ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type createApiKeyRequest struct {
+    Scope enum.ApiKeyScope `json:"scope"`
+    Ttl   string           `json:"ttl"` // a duration such as "720h", 90 days if empty
+}
+
+// apiKeyResponse carries the API key itself only when it is created
+type apiKeyResponse struct {
+    Key string `json:"key"`
+    model.ApiKey
+}
+
+type createListingRequest struct {
+    Title       string `json:"title"`
+    Description string `json:"description"`
+    Price       int    `json:"price"` // in cents
+    Category    string `json:"category"`
+    Draft       bool   `json:"draft"` // create as a draft to be published later
+}
+
+type categoryRequest struct {
+    Name string `json:"name"`
+}
+
+type mergeCategoryRequest struct {
+    Into string `json:"into"`
+}
+
+type roleRequest struct {
+    Role enum.Role `json:"role"`
+}
+
+// listingTransitionActions maps the listing lifecycle actions to their transition
+var listingTransitionActions = map[string]enum.ListingTransition{
+    "publish":   enum.ListingTransitionPublish,
+    "reserve":   enum.ListingTransitionReserve,
+    "unreserve": enum.ListingTransitionUnreserve,
+    "withdraw":  enum.ListingTransitionWithdraw,
+    "hide":      enum.ListingTransitionHide,
+    "unhide":    enum.ListingTransitionUnhide,
+}
+
+type errorResponse struct {
+    Error string `json:"error"`
+}
+
+// ServeHTTP dispatches a request to its handler within the command timeout, with the idempotency key of its
+// Idempotency-Key header. The context of the request is also cancelled when the client goes away or the server shuts
+// down.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    s.log.Infof("Received request: %s %s", r.Method, r.URL.Path)
+    ctx, cancel := context.WithTimeout(r.Context(), s.marketplace.Timeout(""))
+    defer cancel()
+    r = r.WithContext(service.WithIdempotencyKey(ctx, r.Header.Get(IdempotencyKeyHeader)))
+
+    path := strings.Trim(r.URL.Path, "/")
+    segments := strings.Split(path, "/")
+
+    switch {
+    case path == "users":
+        s.allow(w, r, http.MethodPost, s.register)
+    case len(segments) == 3 && segments[0] == "users" && segments[2] == "role":
+        switch r.Method {
+        case http.MethodPut:
+            s.grantRole(w, r, segments[1])
+        case http.MethodDelete:
+            s.revokeRole(w, r, segments[1])
+        default:
+            writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+        }
+    case path == "sessions":
+        switch r.Method {
+        case http.MethodPost:
+            s.login(w, r)
+        case http.MethodDelete:
+            s.logout(w, r)
+        default:
+            writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+        }
+    case path == "api-keys":
+        switch r.Method {
+        case http.MethodPost:
+            s.createApiKey(w, r)
+        case http.MethodGet:
+            s.getApiKeys(w, r)
+        default:
+            writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+        }
+    case len(segments) == 2 && segments[0] == "api-keys":
+        s.allow(w, r, http.MethodDelete, func(w http.ResponseWriter, r *http.Request) {
+            s.revokeApiKey(w, r, segments[1])
+        })
+    case path == "listings":
+        s.allow(w, r, http.MethodPost, s.createListing)
+    case len(segments) == 2 && segments[0] == "listings":
+        listingId, err := strconv.Atoi(segments[1])
+        if err != nil {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid listing id"})
+            return
+        }
+        switch r.Method {
+        case http.MethodGet:
+            s.getListing(w, r, listingId)
+        case http.MethodPatch:
+            s.updateListing(w, r, listingId)
+        case http.MethodDelete:
+            s.deleteListing(w, r, listingId)
+        default:
+            writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+        }
+    case len(segments) == 3 && segments[0] == "listings" && segments[2] == "purchase":
+        listingId, err := strconv.Atoi(segments[1])
+        if err != nil {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid listing id"})
+            return
+        }
+        s.allow(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+            s.buyListing(w, r, listingId)
+        })
+    case len(segments) == 3 && segments[0] == "listings" && isTransitionAction(segments[2]):
+        listingId, err := strconv.Atoi(segments[1])
+        if err != nil {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid listing id"})
+            return
+        }
+        s.allow(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+            s.transitionListing(w, r, listingId, listingTransitionActions[segments[2]])
+        })
+    case path == "orders":
+        s.allow(w, r, http.MethodGet, s.getOrders)
+    case path == "search":
+        s.allow(w, r, http.MethodGet, s.search)
+    case path == "debug/vars":
+        s.allow(w, r, http.MethodGet, expvar.Handler().ServeHTTP)
+    case path == "categories/top":
+        s.allow(w, r, http.MethodGet, s.getTopCategory)
+    case path == "categories/ranking":
+        s.allow(w, r, http.MethodGet, s.getTopCategories)
+    case path == "categories":
+        switch r.Method {
+        case http.MethodGet:
+            s.getCategories(w, r)
+        case http.MethodPost:
+            s.createCategory(w, r)
+        default:
+            writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+        }
+    case len(segments) >= 3 && segments[0] == "categories" && isCategoryAction(segments[len(segments)-1]):
+        category := strings.Join(segments[1:len(segments)-1], model.CategorySeparator)
+        action := segments[len(segments)-1]
+        s.allow(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+            s.manageCategory(w, r, category, action)
+        })
+    case len(segments) >= 3 && segments[0] == "categories" && segments[len(segments)-1] == "listings":
+        // subcategory paths span several segments
+        category := strings.Join(segments[1:len(segments)-1], model.CategorySeparator)
+        s.allow(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+            s.getCategory(w, r, category)
+        })
+    default:
+        writeJson(w, http.StatusNotFound, errorResponse{Error: "resource not found"})
+    }
+}
+
+func isTransitionAction(action string) bool {
+    _, exists := listingTransitionActions[action]
+    return exists
+}
+
+func isCategoryAction(action string) bool {
+    return action == "rename" || action == "merge" || action == "retire"
+}
+
+// allow dispatches to handler if the request uses method
+func (s *Server) allow(w http.ResponseWriter, r *http.Request, method string, handler http.HandlerFunc) {
+    if r.Method != method {
+        writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+        return
+    }
+    handler(w, r)
+}
+
+func (s *Server) register(w http.ResponseWriter, r *http.Request) {
+    var request credentialsRequest
+    if !s.decode(w, r, &request) {
+        return
+    }
+
+    user, err := s.marketplace.Register(r.Context(), request.Username, request.Password)
+    if err != nil {
+        s.log.Errorf("Error registering user '%s': %v", request.Username, err)
+        s.writeError(w, err)
+        return
+    }
+    if user == nil {
+        writeJson(w, http.StatusConflict, errorResponse{Error: "user already existing"})
+        return
+    }
+
+    writeJson(w, http.StatusCreated, user)
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+    var request credentialsRequest
+    if !s.decode(w, r, &request) {
+        return
+    }
+
+    token, session, err := s.marketplace.Login(r.Context(), request.Username, request.Password)
+    if err != nil {
+        s.log.Errorf("Error logging in user '%s': %v", request.Username, err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusCreated, sessionResponse{Token: token, Username: session.Username, ExpiresAt: session.ExpiresAt})
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+    err := s.marketplace.Logout(r.Context(), sessionToken(r))
+    if err != nil {
+        s.log.Errorf("Error logging out: %v", err)
+        s.writeError(w, err)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createListing(w http.ResponseWriter, r *http.Request) {
+    var request createListingRequest
+    if !s.decode(w, r, &request) {
+        return
+    }
+
+    listing, err := s.marketplace.CreateListing(r.Context(), sessionToken(r), request.Title, request.Description, request.Price, request.Category, request.Draft)
+    if err != nil {
+        s.log.Errorf("Error creating listing: %v", err)
+        s.writeError(w, err)
+        return
+    }
+    if listing == nil {
+        writeJson(w, http.StatusConflict, errorResponse{Error: "listing already existing"})
+        return
+    }
+
+    w.Header().Set("Location", "/listings/"+strconv.Itoa(listing.ListingId))
+    writeJson(w, http.StatusCreated, listing)
+}
+
+func (s *Server) getListing(w http.ResponseWriter, r *http.Request, listingId int) {
+    listing, err := s.marketplace.GetListing(r.Context(), caller(r), listingId)
+    if err != nil {
+        s.log.Errorf("Error getting listing '%d': %v", listingId, err)
+        s.writeError(w, err)
+        return
+    }
+    if listing == nil {
+        writeJson(w, http.StatusNotFound, errorResponse{Error: "not found"})
+        return
+    }
+
+    writeJson(w, http.StatusOK, listing)
+}
+
+func (s *Server) updateListing(w http.ResponseWriter, r *http.Request, listingId int) {
+    var update model.ListingUpdate
+    if !s.decode(w, r, &update) {
+        return
+    }
+
+    listing, err := s.marketplace.UpdateListing(r.Context(), sessionToken(r), listingId, update)
+    if err != nil {
+        s.log.Errorf("Error updating listing '%d': %v", listingId, err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusOK, listing)
+}
+
+func (s *Server) deleteListing(w http.ResponseWriter, r *http.Request, listingId int) {
+    err := s.marketplace.DeleteListing(r.Context(), sessionToken(r), listingId)
+    if err != nil {
+        s.log.Errorf("Error deleting listing '%d': %v", listingId, err)
+        s.writeError(w, err)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) transitionListing(w http.ResponseWriter, r *http.Request, listingId int, transition enum.ListingTransition) {
+    listing, err := s.marketplace.TransitionListing(r.Context(), sessionToken(r), listingId, transition)
+    if err != nil {
+        s.log.Errorf("Error trying to %s listing '%d': %v", transition, listingId, err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusOK, listing)
+}
+
+func (s *Server) buyListing(w http.ResponseWriter, r *http.Request, listingId int) {
+    order, err := s.marketplace.BuyListing(r.Context(), sessionToken(r), listingId)
+    if err != nil {
+        s.log.Errorf("Error buying listing '%d': %v", listingId, err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusCreated, order)
+}
+
+func (s *Server) getOrders(w http.ResponseWriter, r *http.Request) {
+    username := caller(r)
+    orders, err := s.marketplace.GetOrders(r.Context(), username)
+    if err != nil {
+        s.log.Errorf("Error getting orders of '%s': %v", username, err)
+        s.writeError(w, err)
+        return
+    }
+    if len(orders) == 0 {
+        writeJson(w, http.StatusNotFound, errorResponse{Error: "no orders found"})
+        return
+    }
+
+    writeJson(w, http.StatusOK, orders)
+}
+
+func (s *Server) getCategory(w http.ResponseWriter, r *http.Request, category string) {
+    query := r.URL.Query()
+
+    // default sort by descending created time
+    sortBy := enum.SortBy(enum.SortByCreatedAt)
+    switch query.Get("sort") {
+    case "", "time":
+    case "price":
+        sortBy = enum.SortByPrice
+    default:
+        writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid sort key"})
+        return
+    }
+    orderBy := enum.OrderBy(enum.OrderByDescending)
+    switch query.Get("order") {
+    case "", "desc", "dsc":
+    case "asc":
+        orderBy = enum.OrderByAscending
+    default:
+        writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid sort order"})
+        return
+    }
+
+    limit := constant.DefaultCategoryPageSize
+    if query.Has("limit") {
+        var err error
+        limit, err = strconv.Atoi(query.Get("limit"))
+        if err != nil || limit < 1 {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid limit"})
+            return
+        }
+    }
+
+    categoryQuery := model.CategoryQuery{
+        Category: category,
+        SortBy:   sortBy,
+        OrderBy:  orderBy,
+        Limit:    limit,
+        Cursor:   query.Get("cursor"),
+
+        TitleContains: query.Get("title"),
+    }
+    var minPriceErr, maxPriceErr, createdAfterErr, createdBeforeErr error
+    categoryQuery.MinPrice, minPriceErr = parsePriceParam(query, "minPrice")
+    categoryQuery.MaxPrice, maxPriceErr = parsePriceParam(query, "maxPrice")
+    if minPriceErr != nil || maxPriceErr != nil {
+        writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid price"})
+        return
+    }
+    categoryQuery.CreatedAfter, createdAfterErr = parseTimeParam(query, "createdAfter")
+    categoryQuery.CreatedBefore, createdBeforeErr = parseTimeParam(query, "createdBefore")
+    if createdAfterErr != nil || createdBeforeErr != nil {
+        writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid time"})
+        return
+    }
+
+    page, err := s.marketplace.GetCategory(r.Context(), caller(r), categoryQuery)
+    if err != nil {
+        s.log.Errorf("Error getting category '%s': %v", category, err)
+        s.writeError(w, err)
+        return
+    }
+    // an empty page after a cursor or with filters is a valid result, not a missing category
+    if len(page.Listings) == 0 && !query.Has("cursor") && !categoryQuery.HasFilters() {
+        writeJson(w, http.StatusNotFound, errorResponse{Error: "category not found"})
+        return
+    }
+
+    if page.Cursor != "" {
+        w.Header().Set(NextCursorHeader, page.Cursor)
+    }
+    listings := page.Listings
+    if listings == nil {
+        listings = []model.Listing{}
+    }
+    writeJson(w, http.StatusOK, listings)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query()
+
+    limit := constant.DefaultSearchLimit
+    if query.Has("limit") {
+        var err error
+        limit, err = strconv.Atoi(query.Get("limit"))
+        if err != nil || limit < 1 {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid limit"})
+            return
+        }
+    }
+
+    searchQuery := model.SearchQuery{
+        Text:     query.Get("q"),
+        Category: query.Get("category"),
+        Limit:    limit,
+    }
+    var minPriceErr, maxPriceErr error
+    searchQuery.MinPrice, minPriceErr = parsePriceParam(query, "minPrice")
+    searchQuery.MaxPrice, maxPriceErr = parsePriceParam(query, "maxPrice")
+    if minPriceErr != nil || maxPriceErr != nil {
+        writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid price"})
+        return
+    }
+
+    listings, err := s.marketplace.Search(r.Context(), caller(r), searchQuery)
+    if err != nil {
+        s.log.Errorf("Error searching '%s': %v", searchQuery.Text, err)
+        s.writeError(w, err)
+        return
+    }
+    if listings == nil {
+        listings = []model.Listing{}
+    }
+    writeJson(w, http.StatusOK, listings)
+}
+
+func (s *Server) getTopCategory(w http.ResponseWriter, r *http.Request) {
+    categoryMetric, err := s.marketplace.GetTopCategory(r.Context(), caller(r), r.URL.Query().Get("parent"))
+    if err != nil {
+        s.log.Errorf("Error getting top category: %v", err)
+        s.writeError(w, err)
+        return
+    }
+    if categoryMetric == nil {
+        writeJson(w, http.StatusNotFound, errorResponse{Error: "no category found"})
+        return
+    }
+
+    writeJson(w, http.StatusOK, categoryMetric)
+}
+
+func (s *Server) getTopCategories(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query()
+
+    topQuery := model.TopCategoriesQuery{
+        Parent:       query.Get("parent"),
+        ExcludeEmpty: query.Get("excludeEmpty") == "true",
+    }
+    if query.Has("limit") {
+        var err error
+        topQuery.Limit, err = strconv.Atoi(query.Get("limit"))
+        if err != nil || topQuery.Limit < 1 {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid limit"})
+            return
+        }
+    }
+
+    categoryMetrics, err := s.marketplace.GetTopCategories(r.Context(), caller(r), topQuery)
+    if err != nil {
+        s.log.Errorf("Error getting top categories: %v", err)
+        s.writeError(w, err)
+        return
+    }
+    if categoryMetrics == nil {
+        categoryMetrics = []model.CategoryMetric{}
+    }
+    writeJson(w, http.StatusOK, categoryMetrics)
+}
+
+func (s *Server) getCategories(w http.ResponseWriter, r *http.Request) {
+    categories, err := s.marketplace.GetCategories(r.Context(), caller(r))
+    if err != nil {
+        s.log.Errorf("Error getting categories: %v", err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusOK, categories)
+}
+
+func (s *Server) createCategory(w http.ResponseWriter, r *http.Request) {
+    var request categoryRequest
+    if !s.decode(w, r, &request) {
+        return
+    }
+
+    category, err := s.marketplace.CreateCategory(r.Context(), sessionToken(r), request.Name)
+    if err != nil {
+        s.log.Errorf("Error creating category '%s': %v", request.Name, err)
+        s.writeError(w, err)
+        return
+    }
+    if category == nil {
+        writeJson(w, http.StatusConflict, errorResponse{Error: "category already existing"})
+        return
+    }
+
+    writeJson(w, http.StatusCreated, category)
+}
+
+// manageCategory renames, merges or retires a category
+func (s *Server) manageCategory(w http.ResponseWriter, r *http.Request, category string, action string) {
+    token := sessionToken(r)
+    var err error
+    switch action {
+    case "rename":
+        var request categoryRequest
+        if !s.decode(w, r, &request) {
+            return
+        }
+        err = s.marketplace.RenameCategory(r.Context(), token, category, request.Name)
+    case "merge":
+        var request mergeCategoryRequest
+        if !s.decode(w, r, &request) {
+            return
+        }
+        err = s.marketplace.MergeCategory(r.Context(), token, category, request.Into)
+    default:
+        err = s.marketplace.RetireCategory(r.Context(), token, category)
+    }
+    if err != nil {
+        s.log.Errorf("Error trying to %s category '%s': %v", action, category, err)
+        s.writeError(w, err)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) grantRole(w http.ResponseWriter, r *http.Request, username string) {
+    var request roleRequest
+    if !s.decode(w, r, &request) {
+        return
+    }
+
+    user, err := s.marketplace.GrantRole(r.Context(), sessionToken(r), username, request.Role)
+    if err != nil {
+        s.log.Errorf("Error granting role %s to '%s': %v", request.Role, username, err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusOK, user)
+}
+
+func (s *Server) revokeRole(w http.ResponseWriter, r *http.Request, username string) {
+    user, err := s.marketplace.RevokeRole(r.Context(), sessionToken(r), username)
+    if err != nil {
+        s.log.Errorf("Error revoking role of '%s': %v", username, err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusOK, user)
+}
+
+func (s *Server) createApiKey(w http.ResponseWriter, r *http.Request) {
+    var request createApiKeyRequest
+    if !s.decode(w, r, &request) {
+        return
+    }
+    ttl := constant.DefaultApiKeyTtl
+    if request.Ttl != "" {
+        var err error
+        ttl, err = time.ParseDuration(request.Ttl)
+        if err != nil {
+            writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid input"})
+            return
+        }
+    }
+
+    key, apiKey, err := s.marketplace.CreateApiKey(r.Context(), sessionToken(r), request.Scope, ttl)
+    if err != nil {
+        s.log.Errorf("Error creating api key: %v", err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusCreated, apiKeyResponse{Key: key, ApiKey: *apiKey})
+}
+
+func (s *Server) getApiKeys(w http.ResponseWriter, r *http.Request) {
+    keys, err := s.marketplace.GetApiKeys(r.Context(), sessionToken(r))
+    if err != nil {
+        s.log.Errorf("Error getting api keys: %v", err)
+        s.writeError(w, err)
+        return
+    }
+
+    writeJson(w, http.StatusOK, keys)
+}
+
+func (s *Server) revokeApiKey(w http.ResponseWriter, r *http.Request, keyId string) {
+    err := s.marketplace.RevokeApiKey(r.Context(), sessionToken(r), keyId)
+    if err != nil {
+        s.log.Errorf("Error revoking api key %s: %v", keyId, err)
+        s.writeError(w, err)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// caller returns the API key of a reading request, or the username it identifies the caller by
+func caller(r *http.Request) string {
+    if key := r.Header.Get(ApiKeyHeader); key != "" {
+        return key
+    }
+    return r.Header.Get(UsernameHeader)
+}
+
+// sessionToken returns the API key of a mutating request, or the session token of the Authorization header, or an
+// empty token that matches no session
+func sessionToken(r *http.Request) string {
+    if key := r.Header.Get(ApiKeyHeader); key != "" {
+        return key
+    }
+    token, _ := strings.CutPrefix(r.Header.Get(AuthorizationHeader), bearerPrefix)
+    return token
+}
+
+// decode reads the JSON request body into v, writing a 400 response if it is malformed and a 413 response if it is
+// larger than MaxRequestBodySize
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+    decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
+    decoder.DisallowUnknownFields()
+    err := decoder.Decode(v)
+    var tooLargeErr *http.MaxBytesError
+    if errors.As(err, &tooLargeErr) {
+        s.log.Errorf("Request body is larger than %d bytes", tooLargeErr.Limit)
+        writeJson(w, http.StatusRequestEntityTooLarge, errorResponse{Error: "request body too large"})
+        return false
+    }
+    if err != nil {
+        s.log.Errorf("Error decoding request body: %v", err)
+        writeJson(w, http.StatusBadRequest, errorResponse{Error: "invalid input"})
+        return false
+    }
+    return true
+}
+
+// writeError maps err to its HTTP status code and writes it as a JSON error body
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+    status, message := statusOf(err)
+    writeJson(w, status, errorResponse{Error: message})
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    _ = json.NewEncoder(w).Encode(v)
+}
+
+// parsePriceParam parses a price in cents from the query. Returns nil if the parameter is not set.
+func parsePriceParam(query url.Values, name string) (*int, error) {
+    if !query.Has(name) {
+        return nil, nil
+    }
+    price, err := strconv.Atoi(query.Get(name))
+    if err != nil {
+        return nil, err
+    }
+    return &price, nil
+}
+
+// parseTimeParam parses an RFC 3339 time from the query. Returns nil if the parameter is not set.
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+    if !query.Has(name) {
+        return nil, nil
+    }
+    t, err := time.Parse(time.RFC3339, query.Get(name))
+    if err != nil {
+        return nil, err
+    }
+    return &t, nil
+}
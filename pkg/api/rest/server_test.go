@@ -0,0 +1,613 @@
+package rest
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "go.uber.org/zap"
+    "io"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/memory"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/service"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestServer(t *testing.T) {
+    t.Setenv(constant.AdminUsersEnvKey, "admin")
+    log := zap.NewNop().Sugar()
+    server := httptest.NewServer(NewServer(service.NewMarketplace(memory.NewMemoryDataAccess(log), log), log))
+    defer server.Close()
+
+    testCases := []struct {
+        method         string
+        path           string
+        username       string
+        body           string
+        expectedStatus int
+        expectedBody   string
+    }{
+        // authentication errors
+        {"POST", "/listings", "user1", `{"title":"Phone model 8","description":"Black color","price":100000,"category":"Electronics"}`, 401, `{"error":"invalid session"}`},
+        {"GET", "/categories/top", "", "", 401, `{"error":"unknown user"}`},
+        {"POST", "/sessions", "", `{"username":"user1","password":"password1"}`, 401, `{"error":"invalid credentials"}`},
+
+        // input validation errors
+        {"POST", "/users", "", `{"username":"","password":"password1"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/users", "", `{"username":"user1","password":"short"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/users", "", `{"name":"user1"}`, 400, `{"error":"invalid input"}`},
+        {"GET", "/listings/100xxx", "user1", "", 400, `{"error":"invalid listing id"}`},
+        {"PUT", "/listings/100001", "user1", "", 405, `{"error":"method not allowed"}`},
+        {"GET", "/unknown", "user1", "", 404, `{"error":"resource not found"}`},
+
+        // happy path
+        {"POST", "/users", "", `{"username":"user1","password":"password1"}`, 201, `{"username":"user1"}`},
+        {"POST", "/users", "", `{"username":"user1","password":"password1"}`, 409, `{"error":"user already existing"}`},
+        {"POST", "/users", "", `{"username":"user2","password":"password2"}`, 201, `{"username":"user2"}`},
+        {"POST", "/users", "", `{"username":"admin","password":"password3"}`, 201, `{"username":"admin"}`},
+        {"POST", "/sessions", "", `{"username":"user1","password":"password2"}`, 401, `{"error":"invalid credentials"}`},
+        {"PUT", "/sessions", "", "", 405, `{"error":"method not allowed"}`},
+        {"POST", "/sessions", "", `{"username":"user1","password":"password1"}`, 201, `"username":"user1"`},
+        {"POST", "/sessions", "", `{"username":"USER2","password":"password2"}`, 201, `"username":"user2"`},
+        {"POST", "/sessions", "", `{"username":"admin","password":"password3"}`, 201, `"username":"admin"`},
+        {"GET", "/categories/top", "user1", "", 404, `{"error":"no category found"}`},
+        {"GET", "/categories/ranking", "user1", "", 200, `[]`},
+        {"POST", "/categories", "admin", `{"name":"Electronics"}`, 201, `{"name":"Electronics","status":"ACTIVE"}`},
+        {"POST", "/categories", "admin", `{"name":"Sports"}`, 201, `{"name":"Sports","status":"ACTIVE"}`},
+        {"POST", "/categories", "admin", `{"name":"Fashion"}`, 201, `{"name":"Fashion","status":"ACTIVE"}`},
+        {"POST", "/listings", "user1", `{"title":"","description":"Black color","price":100000,"category":"Electronics"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/listings", "user1", `{"title":"Phone model 8","description":"Black color","price":100000,"category":"Electronics"}`, 201, `"listingId":100001`},
+        {"POST", "/listings", "user1", `{"title":"Black shoes","description":"Training shoes","price":10000,"category":"Sports"}`, 201, `"listingId":100002`},
+        {"POST", "/listings", "user2", `{"title":"T-shirt","description":"White color","price":2000,"category":"Sports"}`, 201, `"listingId":100003`},
+        {"GET", "/listings/100001", "user2", "", 200, `"title":"Phone model 8"`},
+        {"GET", "/listings/900001", "user2", "", 404, `{"error":"not found"}`},
+        {"GET", "/categories/Sports/listings?sort=price&order=asc", "user1", "", 200, `"listingId":100003`},
+        {"GET", "/categories/Sports/listings?sort=size", "user1", "", 400, `{"error":"invalid sort key"}`},
+        {"GET", "/categories/Sports/listings?limit=0", "user1", "", 400, `{"error":"invalid limit"}`},
+        {"GET", "/categories/Sports/listings?limit=101", "user1", "", 400, `{"error":"invalid input"}`},
+        {"GET", "/categories/Sports/listings?cursor=xyz", "user1", "", 400, `{"error":"invalid input"}`},
+        {"GET", "/categories/Sports/listings?minPrice=abc", "user1", "", 400, `{"error":"invalid price"}`},
+        {"GET", "/categories/Sports/listings?createdAfter=2019-02-22", "user1", "", 400, `{"error":"invalid time"}`},
+        {"GET", "/categories/Sports/listings?minPrice=2000&maxPrice=1000", "user1", "", 400, `{"error":"invalid input"}`},
+        {"GET", "/categories/Sports/listings?sort=price&minPrice=2500&createdAfter=2000-01-01T00:00:00Z", "user1", "", 200, `"listingId":100002`},
+        {"GET", "/categories/Sports/listings?title=Unknown", "user1", "", 200, `[]`},
+        {"GET", "/categories/Fashion/listings", "user1", "", 404, `{"error":"category not found"}`},
+        {"GET", "/categories/top", "user1", "", 200, `{"category":"Sports","categoryCount":2}`},
+        {"PATCH", "/listings/100003", "user1", `{"price":2500}`, 403, `{"error":"listing owner mismatch"}`},
+        {"PATCH", "/listings/100003", "user2", `{}`, 400, `{"error":"invalid input"}`},
+        {"PATCH", "/listings/100003", "user2", `{"price":2500,"category":"Fashion"}`, 200, `"version":2`},
+        {"GET", "/categories/top", "user1", "", 200, `{"category":"Sports","categoryCount":1}`},
+        {"DELETE", "/listings/100003", "user1", "", 403, `{"error":"listing owner mismatch"}`},
+        {"DELETE", "/listings/100003", "user2", "", 204, ""},
+        {"DELETE", "/listings/100003", "user2", "", 404, `{"error":"listing does not exist"}`},
+        {"GET", "/orders", "user2", "", 404, `{"error":"no orders found"}`},
+        {"GET", "/listings/100001/purchase", "user2", "", 405, `{"error":"method not allowed"}`},
+        {"POST", "/listings/100001/purchase", "user1", "", 403, `{"error":"cannot buy or reserve own listing"}`},
+        {"POST", "/listings/900001/purchase", "user2", "", 404, `{"error":"listing does not exist"}`},
+        {"POST", "/listings/100001/purchase", "user2", "", 201, `"price":100000,"seller":"user1","buyer":"user2"`},
+        {"POST", "/listings/100001/purchase", "user2", "", 409, `{"error":"listing already sold"}`},
+        {"GET", "/orders", "user1", "", 200, `"listingId":100001,"title":"Phone model 8"`},
+        {"GET", "/categories/Electronics/listings", "user1", "", 404, `{"error":"category not found"}`},
+        {"GET", "/search?q=training+shoe", "user1", "", 200, `"listingId":100002`},
+        {"GET", "/search?q=phone", "user1", "", 200, `[]`},
+        {"GET", "/search?q=shoes&category=Fashion", "user1", "", 200, `[]`},
+        {"GET", "/search?q=", "user1", "", 400, `{"error":"invalid input"}`},
+        {"GET", "/search?q=shoes&limit=0", "user1", "", 400, `{"error":"invalid limit"}`},
+        {"GET", "/search?q=shoes&maxPrice=abc", "user1", "", 400, `{"error":"invalid price"}`},
+        {"POST", "/search?q=shoes", "user1", "", 405, `{"error":"method not allowed"}`},
+
+        // listing lifecycle
+        {"POST", "/listings", "user2", `{"title":"Jacket","description":"Rain jacket","price":8000,"category":"Fashion","draft":true}`, 201, `"status":"DRAFT"`},
+        {"GET", "/categories/Fashion/listings", "user1", "", 404, `{"error":"category not found"}`},
+        {"POST", "/listings/100004/reserve", "user1", "", 409, `{"error":"cannot reserve a draft listing"}`},
+        {"GET", "/listings/100004/publish", "user2", "", 405, `{"error":"method not allowed"}`},
+        {"POST", "/listings/100004/publish", "user2", "", 200, `"status":"ACTIVE"`},
+        {"POST", "/listings/100004/reserve", "user1", "", 200, `"status":"RESERVED","reservedBy":"user1"`},
+        {"POST", "/listings/100004/purchase", "user2", "", 403, `{"error":"cannot buy or reserve own listing"}`},
+        {"POST", "/listings/100004/unreserve", "user1", "", 200, `"status":"ACTIVE"`},
+        {"POST", "/listings/100004/withdraw", "user1", "", 403, `{"error":"listing owner mismatch"}`},
+        {"POST", "/listings/100004/withdraw", "user2", "", 200, `"status":"WITHDRAWN"`},
+        {"POST", "/listings/100004/publish", "user2", "", 409, `{"error":"cannot publish a withdrawn listing"}`},
+
+        // category hierarchy
+        {"POST", "/listings", "user1", `{"title":"Trail shoes","description":"Size 42","price":9000,"category":"Sports/Running/"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/categories", "admin", `{"name":"Sports/Running"}`, 201, `{"name":"Sports/Running","status":"ACTIVE"}`},
+        {"POST", "/listings", "user1", `{"title":"Trail shoes","description":"Size 42","price":9000,"category":"Sports/Running"}`, 201, `"listingId":100005`},
+        {"GET", "/categories/Sports/Running/listings", "user1", "", 200, `"listingId":100005`},
+        {"GET", "/categories/Sports%2FRunning/listings", "user1", "", 200, `"listingId":100005`},
+        {"GET", "/categories/Sports/listings?sort=price&order=asc&limit=1", "user1", "", 200, `"listingId":100005`},
+        {"GET", "/categories/Sports/listings?sort=price&order=desc&limit=1", "user1", "", 200, `"listingId":100002`},
+        {"GET", "/categories/top?parent=Sports", "user1", "", 200, `{"category":"Sports/Running","categoryCount":1}`},
+        {"GET", "/categories/top?parent=Sports/Running", "user1", "", 404, `{"error":"no category found"}`},
+        {"GET", "/categories/ranking", "user1", "", 200, `[{"category":"Sports","categoryCount":2},{"category":"Fashion","categoryCount":0},{"category":"Electronics","categoryCount":0}]`},
+        {"GET", "/categories/ranking?excludeEmpty=true", "user1", "", 200, `[{"category":"Sports","categoryCount":2}]`},
+        {"GET", "/categories/ranking?parent=sports&limit=1", "user1", "", 200, `[{"category":"Sports/Running","categoryCount":1}]`},
+        {"GET", "/categories/ranking?limit=0", "user1", "", 400, `{"error":"invalid limit"}`},
+        {"GET", "/categories/ranking?limit=101", "user1", "", 400, `{"error":"invalid input"}`},
+
+        // category catalog
+        {"POST", "/categories", "user1", `{"name":"Toys"}`, 403, `{"error":"permission denied"}`},
+        {"POST", "/categories", "admin", `{"name":"Sports"}`, 409, `{"error":"category already existing"}`},
+        {"POST", "/categories", "admin", `{"name":"Toys/Cars"}`, 404, `{"error":"category does not exist"}`},
+        {"POST", "/listings", "user1", `{"title":"Car","description":"Red car","price":500,"category":"Toys"}`, 404, `{"error":"category does not exist"}`},
+        {"POST", "/categories/Sports/Running/rename", "admin", `{"name":"Sports/Trail"}`, 204, ""},
+        {"GET", "/categories/Sports/Trail/listings", "user1", "", 200, `"listingId":100005`},
+        {"POST", "/categories/Sports/Trail/merge", "admin", `{"into":"Sports/Trail/Old"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/categories/Fashion/merge", "admin", `{"into":"Toys"}`, 404, `{"error":"category does not exist"}`},
+        {"POST", "/categories/Fashion/merge", "admin", `{"into":"Sports"}`, 204, ""},
+        {"POST", "/categories/Sports/retire", "user1", "", 403, `{"error":"permission denied"}`},
+        {"POST", "/categories/Sports/retire", "admin", "", 204, ""},
+        {"POST", "/listings", "user1", `{"title":"Ball","description":"Football","price":500,"category":"Sports/Trail"}`, 409, `{"error":"category is retired"}`},
+        {"GET", "/categories", "user1", "", 200, `[{"name":"Electronics","status":"ACTIVE"},{"name":"Sports","status":"RETIRED"},{"name":"Sports/Trail","status":"ACTIVE"}]`},
+        {"DELETE", "/categories", "admin", "", 405, `{"error":"method not allowed"}`},
+
+        // roles
+        {"PUT", "/users/user2/role", "user1", `{"role":"MODERATOR"}`, 403, `{"error":"permission denied"}`},
+        {"PUT", "/users/user2/role", "admin", `{"role":"OWNER"}`, 400, `{"error":"invalid input"}`},
+        {"PUT", "/users/nobody/role", "admin", `{"role":"MODERATOR"}`, 401, `{"error":"unknown user"}`},
+        {"GET", "/users/user2/role", "admin", "", 405, `{"error":"method not allowed"}`},
+        {"POST", "/listings/100002/hide", "user1", "", 403, `{"error":"permission denied"}`},
+        {"PUT", "/users/User2/role", "admin", `{"role":"MODERATOR"}`, 200, `{"username":"user2","role":"MODERATOR"}`},
+        {"POST", "/listings/100002/hide", "user2", "", 200, `"status":"HIDDEN"`},
+        {"GET", "/search?q=training", "user1", "", 200, `[]`},
+        {"POST", "/listings/100002/hide", "user2", "", 409, `{"error":"cannot hide a hidden listing"}`},
+        {"POST", "/listings/100002/unhide", "user2", "", 200, `"status":"ACTIVE"`},
+        {"DELETE", "/listings/100002", "user2", "", 403, `{"error":"listing owner mismatch"}`},
+        {"DELETE", "/listings/100002", "admin", "", 204, ""},
+        {"DELETE", "/users/user2/role", "admin", "", 200, `{"username":"user2","role":"USER"}`},
+        {"POST", "/listings/100005/hide", "user2", "", 403, `{"error":"permission denied"}`},
+
+        // api keys act for their owner within their scope, named key:<scope> and {<scope>} in the paths
+        {"POST", "/users", "", `{"username":"mpk_user","password":"password1"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/api-keys", "user1", `{"scope":"owner"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/api-keys", "user1", `{"scope":"read-only","ttl":"soon"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/api-keys", "user1", `{"scope":"read-only","ttl":"10000h"}`, 400, `{"error":"invalid input"}`},
+        {"POST", "/api-keys", "user1", `{"scope":"read-only"}`, 201, `"username":"user1","scope":"read-only"`},
+        {"POST", "/api-keys", "user1", `{"scope":"listings:write","ttl":"1h"}`, 201, `"scope":"listings:write"`},
+        {"GET", "/api-keys", "user1", "", 200, `"scope":"listings:write"`},
+        {"GET", "/api-keys", "user2", "", 200, `[]`},
+        {"PUT", "/api-keys", "user1", "", 405, `{"error":"method not allowed"}`},
+        {"GET", "/categories", "key:read-only", "", 200, `[{"name":"Electronics","status":"ACTIVE"}`},
+        {"GET", "/orders", "key:read-only", "", 200, `"listingId":100001`},
+        {"POST", "/listings", "key:read-only", `{"title":"Ball","description":"Football","price":500,"category":"Electronics"}`, 403, `{"error":"permission denied"}`},
+        {"POST", "/listings", "key:listings:write", `{"title":"Ball","description":"Football","price":500,"category":"Electronics"}`, 201, `"username":"user1"`},
+        {"POST", "/categories", "key:listings:write", `{"name":"Toys"}`, 403, `{"error":"permission denied"}`},
+        {"POST", "/api-keys", "key:listings:write", `{"scope":"admin"}`, 401, `{"error":"invalid session"}`},
+        {"GET", "/orders", "key:mpk_0123456789abcdef_secret", "", 401, `{"error":"invalid api key"}`},
+        {"DELETE", "/api-keys/{read-only}", "user2", "", 404, `{"error":"api key does not exist"}`},
+        {"DELETE", "/api-keys/{read-only}", "user1", "", 204, ""},
+        {"GET", "/categories", "key:read-only", "", 401, `{"error":"invalid api key"}`},
+
+        // a revoked session can no longer be used
+        {"DELETE", "/sessions", "user1", "", 204, ""},
+        {"POST", "/listings", "user1", `{"title":"Ball","description":"Football","price":500,"category":"Electronics"}`, 401, `{"error":"invalid session"}`},
+    }
+
+    // the session tokens of the users logged in, sent on the requests of the user
+    tokens := make(map[string]string)
+    // the api keys created, by scope
+    apiKeys := make(map[string]apiKeyResponse)
+    for _, tc := range testCases {
+        path := tc.path
+        for scope, apiKey := range apiKeys {
+            path = strings.ReplaceAll(path, "{"+scope+"}", apiKey.KeyId)
+        }
+        request, err := http.NewRequest(tc.method, server.URL+path, strings.NewReader(tc.body))
+        if err != nil {
+            t.Fatalf("could not create request: %v", err)
+        }
+        if name, found := strings.CutPrefix(tc.username, "key:"); found {
+            apiKey, exists := apiKeys[name]
+            if !exists {
+                apiKey.Key = name
+            }
+            request.Header.Set(ApiKeyHeader, apiKey.Key)
+        } else if tc.username != "" {
+            request.Header.Set(UsernameHeader, tc.username)
+            token, exists := tokens[tc.username]
+            if !exists {
+                token = tc.username
+            }
+            request.Header.Set(AuthorizationHeader, "Bearer "+token)
+        }
+
+        response, err := http.DefaultClient.Do(request)
+        if err != nil {
+            t.Fatalf("%s %s: request failed: %v", tc.method, tc.path, err)
+        }
+        body := new(strings.Builder)
+        _, _ = io.Copy(body, response.Body)
+        _ = response.Body.Close()
+        if tc.path == "/sessions" && response.StatusCode == http.StatusCreated {
+            var session sessionResponse
+            err = json.Unmarshal([]byte(body.String()), &session)
+            if err != nil || session.Token == "" {
+                t.Fatalf("expected a session token, got %s", body)
+            }
+            tokens[session.Username] = session.Token
+        }
+        if tc.path == "/api-keys" && response.StatusCode == http.StatusCreated {
+            var apiKey apiKeyResponse
+            err = json.Unmarshal([]byte(body.String()), &apiKey)
+            if err != nil || !strings.HasPrefix(apiKey.Key, "mpk_") {
+                t.Fatalf("expected an api key, got %s", body)
+            }
+            apiKeys[apiKey.Scope.String()] = apiKey
+        }
+
+        if response.StatusCode != tc.expectedStatus {
+            t.Fatalf("%s %s: expected status %d, got %d with body %s", tc.method, tc.path, tc.expectedStatus, response.StatusCode, body)
+        }
+        if !strings.Contains(body.String(), tc.expectedBody) {
+            t.Fatalf("%s %s: expected body containing %s, got %s", tc.method, tc.path, tc.expectedBody, body)
+        }
+    }
+}
+
+func TestCategoryPagination(t *testing.T) {
+    log := zap.NewNop().Sugar()
+    store := memory.NewMemoryDataAccess(log)
+    _, err := store.PutCategory(context.Background(), "Sports")
+    if err != nil {
+        t.Fatalf("could not put category: %v", err)
+    }
+    server := httptest.NewServer(NewServer(service.NewMarketplace(store, log), log))
+    defer server.Close()
+
+    var token string
+    send := func(method string, path string, body string) *http.Response {
+        request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+        if err != nil {
+            t.Fatalf("could not create request: %v", err)
+        }
+        request.Header.Set(UsernameHeader, "user1")
+        request.Header.Set(AuthorizationHeader, "Bearer "+token)
+        response, err := http.DefaultClient.Do(request)
+        if err != nil {
+            t.Fatalf("%s %s: request failed: %v", method, path, err)
+        }
+        return response
+    }
+    _ = send("POST", "/users", `{"username":"user1","password":"password1"}`).Body.Close()
+    response := send("POST", "/sessions", `{"username":"user1","password":"password1"}`)
+    var session sessionResponse
+    err = json.NewDecoder(response.Body).Decode(&session)
+    _ = response.Body.Close()
+    if err != nil {
+        t.Fatalf("could not log in: %v", err)
+    }
+    token = session.Token
+    for _, price := range []string{"300", "100", "200"} {
+        _ = send("POST", "/listings", `{"title":"Ball","description":"Football","price":`+price+`,"category":"Sports"}`).Body.Close()
+    }
+
+    var pages []string
+    path := "/categories/Sports/listings?sort=price&order=asc&limit=2"
+    for path != "" {
+        response := send("GET", path, "")
+        body := new(strings.Builder)
+        _, _ = io.Copy(body, response.Body)
+        _ = response.Body.Close()
+        if response.StatusCode != http.StatusOK {
+            t.Fatalf("GET %s: expected status 200, got %d with body %s", path, response.StatusCode, body)
+        }
+        pages = append(pages, body.String())
+
+        path = ""
+        if cursor := response.Header.Get(NextCursorHeader); cursor != "" {
+            path = "/categories/Sports/listings?sort=price&order=asc&limit=2&cursor=" + cursor
+        }
+    }
+
+    if len(pages) != 2 || strings.Count(pages[0], "listingId") != 2 || !strings.Contains(pages[1], `"listingId":100001`) {
+        t.Fatalf("expected pages of 2 and 1 listings ending with the most expensive, got %v", pages)
+    }
+}
+
+func TestRateLimit(t *testing.T) {
+    t.Setenv(constant.RateLimitWriteEnvKey, "2/1h")
+    t.Setenv(constant.RateLimitLoginEnvKey, "2/1h")
+    log := zap.NewNop().Sugar()
+    store := memory.NewMemoryDataAccess(log)
+    _, err := store.PutCategory(context.Background(), "Sports")
+    if err != nil {
+        t.Fatalf("could not put category: %v", err)
+    }
+    server := httptest.NewServer(NewServer(service.NewMarketplace(store, log), log))
+    defer server.Close()
+
+    tokens := map[string]string{}
+    send := func(method string, path string, username string, body string) (int, string) {
+        request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+        if err != nil {
+            t.Fatalf("could not create request: %v", err)
+        }
+        request.Header.Set(UsernameHeader, username)
+        request.Header.Set(AuthorizationHeader, "Bearer "+tokens[username])
+        response, err := http.DefaultClient.Do(request)
+        if err != nil {
+            t.Fatalf("%s %s: request failed: %v", method, path, err)
+        }
+        defer response.Body.Close()
+        if path == "/sessions" && response.StatusCode == http.StatusCreated {
+            var session sessionResponse
+            _ = json.NewDecoder(response.Body).Decode(&session)
+            tokens[username] = session.Token
+            return response.StatusCode, ""
+        }
+        responseBody := new(strings.Builder)
+        _, _ = io.Copy(responseBody, response.Body)
+        return response.StatusCode, responseBody.String()
+    }
+
+    testCases := []struct {
+        method         string
+        path           string
+        username       string
+        body           string
+        expectedStatus int
+    }{
+        {"POST", "/users", "", `{"username":"user1","password":"password1"}`, 201},
+        {"POST", "/users", "", `{"username":"user2","password":"password2"}`, 201},
+        // failed logins count against the username
+        {"POST", "/sessions", "", `{"username":"user1","password":"wrong"}`, 401},
+        {"POST", "/sessions", "user1", `{"username":"user1","password":"password1"}`, 201},
+        {"POST", "/sessions", "", `{"username":"USER1","password":"password1"}`, 429},
+        {"POST", "/sessions", "user2", `{"username":"user2","password":"password2"}`, 201},
+        {"POST", "/listings", "user1", `{"title":"Ball","description":"Football","price":100,"category":"Sports"}`, 201},
+        {"POST", "/listings", "user1", `{"title":"Ball","description":"Football","price":200,"category":"Sports"}`, 201},
+        {"POST", "/listings", "user1", `{"title":"Ball","description":"Football","price":300,"category":"Sports"}`, 429},
+        {"DELETE", "/listings/100001", "user1", "", 429},
+        // reads and the other users have their own buckets
+        {"GET", "/listings/100001", "user1", "", 200},
+        {"POST", "/listings", "user2", `{"title":"Ball","description":"Football","price":300,"category":"Sports"}`, 201},
+    }
+
+    for _, tc := range testCases {
+        status, body := send(tc.method, tc.path, tc.username, tc.body)
+        if status != tc.expectedStatus {
+            t.Fatalf("%s %s as %s: expected status %d, got %d with body %s", tc.method, tc.path, tc.username, tc.expectedStatus, status, body)
+        }
+        if status == http.StatusTooManyRequests && strings.TrimSpace(body) != `{"error":"rate limited"}` {
+            t.Fatalf("%s %s: expected rate limited error, got %s", tc.method, tc.path, body)
+        }
+    }
+}
+
+func TestIdempotencyKey(t *testing.T) {
+    log := zap.NewNop().Sugar()
+    store := memory.NewMemoryDataAccess(log)
+    _, err := store.PutCategory(context.Background(), "Sports")
+    if err != nil {
+        t.Fatalf("could not put category: %v", err)
+    }
+    server := httptest.NewServer(NewServer(service.NewMarketplace(store, log), log))
+    defer server.Close()
+
+    tokens := map[string]string{}
+    send := func(method string, path string, username string, key string, body string) (int, string) {
+        request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+        if err != nil {
+            t.Fatalf("could not create request: %v", err)
+        }
+        request.Header.Set(UsernameHeader, username)
+        request.Header.Set(AuthorizationHeader, "Bearer "+tokens[username])
+        if key != "" {
+            request.Header.Set(IdempotencyKeyHeader, key)
+        }
+        response, err := http.DefaultClient.Do(request)
+        if err != nil {
+            t.Fatalf("%s %s: request failed: %v", method, path, err)
+        }
+        defer response.Body.Close()
+        if path == "/sessions" && response.StatusCode == http.StatusCreated {
+            var session sessionResponse
+            _ = json.NewDecoder(response.Body).Decode(&session)
+            tokens[username] = session.Token
+            return response.StatusCode, ""
+        }
+        responseBody := new(strings.Builder)
+        _, _ = io.Copy(responseBody, response.Body)
+        return response.StatusCode, strings.TrimSpace(responseBody.String())
+    }
+
+    testCases := []struct {
+        method         string
+        path           string
+        username       string
+        key            string
+        body           string
+        expectedStatus int
+        // the response to compare with, the previous one of the same key if empty
+        expectedBody string
+    }{
+        {"POST", "/users", "", "", `{"username":"user1","password":"password1"}`, 201, "-"},
+        {"POST", "/users", "", "", `{"username":"user2","password":"password2"}`, 201, "-"},
+        {"POST", "/sessions", "user1", "", `{"username":"user1","password":"password1"}`, 201, "-"},
+        {"POST", "/sessions", "user2", "", `{"username":"user2","password":"password2"}`, 201, "-"},
+        {"POST", "/listings", "user1", "key-1", `{"title":"Ball","description":"Football","price":100,"category":"Sports"}`, 201, "-"},
+        // a replay returns the listing of the first request instead of creating another one
+        {"POST", "/listings", "user1", "key-1", `{"title":"Ball","description":"Football","price":100,"category":"Sports"}`, 201, ""},
+        {"POST", "/listings", "user1", "key-1", `{"title":"Ball","description":"Football","price":200,"category":"Sports"}`, 422, `{"error":"idempotency key reused"}`},
+        {"DELETE", "/listings/100001", "user1", "key-1", "", 422, `{"error":"idempotency key reused"}`},
+        // keys are per user
+        {"POST", "/listings", "user2", "key-1", `{"title":"Shoes","description":"Running shoes","price":300,"category":"Sports"}`, 201, "-"},
+        {"GET", "/listings/100002", "user1", "", "", 200, "-"},
+        {"GET", "/listings/100003", "user1", "", "", 404, "-"},
+        // a failed request releases its key
+        {"POST", "/listings/100001/purchase", "user1", "key-2", "", 403, `{"error":"cannot buy or reserve own listing"}`},
+        {"POST", "/listings/100002/purchase", "user1", "key-2", "", 201, "-"},
+        {"POST", "/listings/100002/purchase", "user1", "key-2", "", 201, ""},
+        {"POST", "/listings/100002/purchase", "user1", "", "", 409, `{"error":"listing already sold"}`},
+    }
+
+    responses := map[string]string{}
+    for _, tc := range testCases {
+        status, body := send(tc.method, tc.path, tc.username, tc.key, tc.body)
+        if status != tc.expectedStatus {
+            t.Fatalf("%s %s as %s: expected status %d, got %d with body %s", tc.method, tc.path, tc.username, tc.expectedStatus, status, body)
+        }
+        expectedBody := tc.expectedBody
+        if expectedBody == "" {
+            expectedBody = responses[tc.username+"#"+tc.key]
+        }
+        if expectedBody != "-" && body != expectedBody {
+            t.Fatalf("%s %s as %s: expected body %s, got %s", tc.method, tc.path, tc.username, expectedBody, body)
+        }
+        if status < 300 && tc.key != "" {
+            responses[tc.username+"#"+tc.key] = body
+        }
+    }
+}
+
+func TestRequestBodyTooLarge(t *testing.T) {
+    log := zap.NewNop().Sugar()
+    server := httptest.NewServer(NewServer(service.NewMarketplace(memory.NewMemoryDataAccess(log), log), log))
+    defer server.Close()
+
+    password := strings.Repeat("p", MaxRequestBodySize)
+    response, err := http.Post(server.URL+"/users", "application/json",
+        strings.NewReader(`{"username":"user1","password":"`+password+`"}`))
+    if err != nil {
+        t.Fatalf("request failed: %v", err)
+    }
+    body := new(strings.Builder)
+    _, _ = io.Copy(body, response.Body)
+    _ = response.Body.Close()
+    if response.StatusCode != http.StatusRequestEntityTooLarge || strings.TrimSpace(body.String()) != `{"error":"request body too large"}` {
+        t.Fatalf("expected status 413 with a request body too large error, got %d with body %s", response.StatusCode, body)
+    }
+}
+
+// unsettledStore stands for a database whose listing writes stall while stalled is set, and which cannot store the
+// response of a command while failResponses is set
+type unsettledStore struct {
+    *memory.MemoryDataAccess
+    stalled       *atomic.Bool
+    failResponses *atomic.Bool
+}
+
+func (s unsettledStore) PutListing(ctx context.Context, username string, title string, description string, price int, category string, status enum.ListingStatus) (*model.Listing, error) {
+    if s.stalled.Load() {
+        <-ctx.Done()
+        return nil, ctx.Err()
+    }
+    return s.MemoryDataAccess.PutListing(ctx, username, title, description, price, category, status)
+}
+
+func (s unsettledStore) CompleteIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) error {
+    if record.Response != nil && s.failResponses.Load() {
+        return errors.New("connection reset")
+    }
+    return s.MemoryDataAccess.CompleteIdempotencyRecord(ctx, record)
+}
+
+func TestIdempotencyKeyUnsettled(t *testing.T) {
+    t.Setenv(constant.CommandTimeoutEnvKey, "100ms")
+    log := zap.NewNop().Sugar()
+    store := unsettledStore{memory.NewMemoryDataAccess(log), &atomic.Bool{}, &atomic.Bool{}}
+    _, err := store.PutCategory(context.Background(), "Sports")
+    if err != nil {
+        t.Fatalf("could not put category: %v", err)
+    }
+    server := httptest.NewServer(NewServer(service.NewMarketplace(store, log), log))
+    defer server.Close()
+
+    token := ""
+    send := func(method string, path string, key string, body string) (int, string) {
+        request, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+        if err != nil {
+            t.Fatalf("could not create request: %v", err)
+        }
+        request.Header.Set(UsernameHeader, "user1")
+        request.Header.Set(AuthorizationHeader, "Bearer "+token)
+        if key != "" {
+            request.Header.Set(IdempotencyKeyHeader, key)
+        }
+        response, err := http.DefaultClient.Do(request)
+        if err != nil {
+            t.Fatalf("%s %s: request failed: %v", method, path, err)
+        }
+        defer response.Body.Close()
+        responseBody := new(strings.Builder)
+        _, _ = io.Copy(responseBody, response.Body)
+        return response.StatusCode, strings.TrimSpace(responseBody.String())
+    }
+    send("POST", "/users", "", `{"username":"user1","password":"password1"}`)
+    _, body := send("POST", "/sessions", "", `{"username":"user1","password":"password1"}`)
+    var session sessionResponse
+    _ = json.Unmarshal([]byte(body), &session)
+    token = session.Token
+    listing := `{"title":"Ball","description":"Football","price":100,"category":"Sports"}`
+
+    // a command that timed out holds its key for another command timeout only, and then runs again
+    store.stalled.Store(true)
+    if status, body := send("POST", "/listings", "key-1", listing); status != http.StatusGatewayTimeout {
+        t.Fatalf("expected the stalled command to time out, got %d with body %s", status, body)
+    }
+    store.stalled.Store(false)
+    if status, body := send("POST", "/listings", "key-1", listing); status != http.StatusConflict {
+        t.Fatalf("expected the key to be held after the timeout, got %d with body %s", status, body)
+    }
+    time.Sleep(150 * time.Millisecond)
+    if status, body := send("POST", "/listings", "key-1", listing); status != http.StatusCreated {
+        t.Fatalf("expected the command to run again once the hold is over, got %d with body %s", status, body)
+    }
+
+    // a command whose response cannot be stored holds its key, so that a replay does not run it twice
+    store.failResponses.Store(true)
+    if status, body := send("POST", "/listings", "key-2", listing); status != http.StatusCreated {
+        t.Fatalf("expected the command to succeed, got %d with body %s", status, body)
+    }
+    time.Sleep(150 * time.Millisecond)
+    if status, body := send("POST", "/listings", "key-2", listing); status != http.StatusConflict {
+        t.Fatalf("expected the key to stay held, got %d with body %s", status, body)
+    }
+    if status, _ := send("GET", "/listings/100003", "", ""); status != http.StatusNotFound {
+        t.Fatalf("expected no listing to be created by the replay, got %d", status)
+    }
+}
+
+// stalledStore stands for a database that stops answering: its listings never arrive
+type stalledStore struct {
+    *memory.MemoryDataAccess
+}
+
+func (s stalledStore) GetListing(ctx context.Context, _ int) (*model.Listing, error) {
+    <-ctx.Done()
+    return nil, ctx.Err()
+}
+
+func TestTimeout(t *testing.T) {
+    t.Setenv(constant.CommandTimeoutEnvKey, "50ms")
+    log := zap.NewNop().Sugar()
+    store := stalledStore{memory.NewMemoryDataAccess(log)}
+    _, err := store.PutUser(context.Background(), "user1", "$2a$10$hash")
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    server := httptest.NewServer(NewServer(service.NewMarketplace(store, log), log))
+    defer server.Close()
+
+    request, err := http.NewRequest("GET", server.URL+"/listings/100001", nil)
+    if err != nil {
+        t.Fatalf("could not create request: %v", err)
+    }
+    request.Header.Set(UsernameHeader, "user1")
+    response, err := http.DefaultClient.Do(request)
+    if err != nil {
+        t.Fatalf("request failed: %v", err)
+    }
+    body := new(strings.Builder)
+    _, _ = io.Copy(body, response.Body)
+    _ = response.Body.Close()
+    if response.StatusCode != http.StatusGatewayTimeout || strings.TrimSpace(body.String()) != `{"error":"timeout"}` {
+        t.Fatalf("expected status 504 with a timeout error, got %d with body %s", response.StatusCode, body)
+    }
+}
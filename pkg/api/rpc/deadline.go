@@ -0,0 +1,30 @@
+package rpc
+
+import (
+    "context"
+    "google.golang.org/grpc"
+)
+
+// UnaryDeadline bounds every unary call by the command timeout, on top of the deadline set by the client
+func (s *Server) UnaryDeadline(ctx context.Context, request any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+    ctx, cancel := context.WithTimeout(ctx, s.marketplace.Timeout(""))
+    defer cancel()
+    return handler(ctx, request)
+}
+
+// StreamDeadline bounds every streaming call by the command timeout, on top of the deadline set by the client
+func (s *Server) StreamDeadline(server any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    ctx, cancel := context.WithTimeout(stream.Context(), s.marketplace.Timeout(""))
+    defer cancel()
+    return handler(server, deadlineStream{ServerStream: stream, ctx: ctx})
+}
+
+// deadlineStream is a server stream with the context of StreamDeadline
+type deadlineStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (d deadlineStream) Context() context.Context {
+    return d.ctx
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "marketplace-platform/pkg/exception"
+)
+
+// codeOfKind maps the kinds of marketplace errors to gRPC codes, internal errors to codes.Internal
+var codeOfKind = map[exception.Kind]codes.Code{
+    exception.KindTimeout:                 codes.DeadlineExceeded,
+    exception.KindCancelled:               codes.Canceled,
+    exception.KindUnknownUser:             codes.Unauthenticated,
+    exception.KindInvalidCredentials:      codes.Unauthenticated,
+    exception.KindInvalidSession:          codes.Unauthenticated,
+    exception.KindInvalidApiKey:           codes.Unauthenticated,
+    exception.KindApiKeyDoesNotExist:      codes.NotFound,
+    exception.KindRateLimited:             codes.ResourceExhausted,
+    exception.KindIdempotencyKeyReused:    codes.InvalidArgument,
+    exception.KindRequestInProgress:       codes.Aborted,
+    exception.KindOwnershipMismatch:       codes.PermissionDenied,
+    exception.KindListingDoesNotExist:     codes.NotFound,
+    exception.KindStaleListing:            codes.Aborted,
+    exception.KindListingSold:             codes.FailedPrecondition,
+    exception.KindSelfPurchase:            codes.PermissionDenied,
+    exception.KindInvalidStatusTransition: codes.FailedPrecondition,
+    exception.KindPermissionDenied:        codes.PermissionDenied,
+    exception.KindCategoryDoesNotExist:    codes.NotFound,
+    exception.KindCategoryAlreadyExist:    codes.AlreadyExists,
+    exception.KindCategoryRetired:         codes.FailedPrecondition,
+    exception.KindInvalidInput:            codes.InvalidArgument,
+}
+
+// statusOf maps a marketplace error to a gRPC status with the message of the matching CLI error
+func statusOf(err error) error {
+    kind, message := exception.Classify(err)
+    code, exists := codeOfKind[kind]
+    if !exists {
+        code = codes.Internal
+    }
+    return status.Error(code, message)
+}
@@ -0,0 +1,20 @@
+package rpc
+
+import (
+    "context"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/metadata"
+    "marketplace-platform/pkg/service"
+)
+
+// IdempotencyKeyMetadata carries an optional key of a mutating call, which runs once per key and is answered with the
+// response of the first run on replay
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// UnaryIdempotencyKey runs every unary call with the idempotency key of its metadata
+func (s *Server) UnaryIdempotencyKey(ctx context.Context, request any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+    if values := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyMetadata); len(values) > 0 {
+        ctx = service.WithIdempotencyKey(ctx, values[0])
+    }
+    return handler(ctx, request)
+}
@@ -0,0 +1,508 @@
+package rpc
+
+import (
+    "context"
+    "go.uber.org/zap"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/emptypb"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "marketplace-platform/pkg/api/pb"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/service"
+)
+
+// NextPageTokenTrailer is the trailer carrying the token of the next page of a streamed page
+const NextPageTokenTrailer = "next-page-token"
+
+// Server implements pb.MarketplaceServiceServer on top of service.Marketplace
+type Server struct {
+    pb.UnimplementedMarketplaceServiceServer
+    marketplace *service.Marketplace
+    log         *zap.SugaredLogger
+}
+
+func NewServer(marketplace *service.Marketplace, log *zap.SugaredLogger) *Server {
+    return &Server{
+        marketplace: marketplace,
+        log:         log,
+    }
+}
+
+func (s *Server) Register(ctx context.Context, request *pb.RegisterRequest) (*pb.User, error) {
+    user, err := s.marketplace.Register(ctx, request.Username, request.Password)
+    if err != nil {
+        s.log.Errorf("Error registering user '%s': %v", request.Username, err)
+        return nil, statusOf(err)
+    }
+    if user == nil {
+        return nil, status.Error(codes.AlreadyExists, "user already existing")
+    }
+
+    return toUserMessage(*user), nil
+}
+
+func (s *Server) Login(ctx context.Context, request *pb.LoginRequest) (*pb.Session, error) {
+    token, session, err := s.marketplace.Login(ctx, request.Username, request.Password)
+    if err != nil {
+        s.log.Errorf("Error logging in user '%s': %v", request.Username, err)
+        return nil, statusOf(err)
+    }
+
+    return &pb.Session{Token: token, Username: session.Username, ExpiresAt: timestamppb.New(session.ExpiresAt)}, nil
+}
+
+func (s *Server) Logout(ctx context.Context, request *pb.LogoutRequest) (*emptypb.Empty, error) {
+    err := s.marketplace.Logout(ctx, request.Token)
+    if err != nil {
+        s.log.Errorf("Error logging out: %v", err)
+        return nil, statusOf(err)
+    }
+
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) CreateApiKey(ctx context.Context, request *pb.CreateApiKeyRequest) (*pb.ApiKey, error) {
+    ttl := constant.DefaultApiKeyTtl
+    if request.Ttl != nil {
+        ttl = request.Ttl.AsDuration()
+    }
+
+    key, apiKey, err := s.marketplace.CreateApiKey(ctx, request.Token, enum.ApiKeyScope(request.Scope), ttl)
+    if err != nil {
+        s.log.Errorf("Error creating api key: %v", err)
+        return nil, statusOf(err)
+    }
+
+    message := toApiKeyMessage(*apiKey)
+    message.Key = key
+    return message, nil
+}
+
+func (s *Server) GetApiKeys(request *pb.GetApiKeysRequest, stream pb.MarketplaceService_GetApiKeysServer) error {
+    keys, err := s.marketplace.GetApiKeys(stream.Context(), request.Token)
+    if err != nil {
+        s.log.Errorf("Error getting api keys: %v", err)
+        return statusOf(err)
+    }
+
+    for _, key := range keys {
+        err = stream.Send(toApiKeyMessage(key))
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *Server) RevokeApiKey(ctx context.Context, request *pb.RevokeApiKeyRequest) (*emptypb.Empty, error) {
+    err := s.marketplace.RevokeApiKey(ctx, request.Token, request.KeyId)
+    if err != nil {
+        s.log.Errorf("Error revoking api key %s: %v", request.KeyId, err)
+        return nil, statusOf(err)
+    }
+
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) CreateListing(ctx context.Context, request *pb.CreateListingRequest) (*pb.Listing, error) {
+    listing, err := s.marketplace.CreateListing(ctx, request.Token, request.Title, request.Description, int(request.Price), request.Category, request.Draft)
+    if err != nil {
+        s.log.Errorf("Error creating listing: %v", err)
+        return nil, statusOf(err)
+    }
+    if listing == nil {
+        return nil, status.Error(codes.AlreadyExists, "listing already existing")
+    }
+
+    return toListingMessage(*listing), nil
+}
+
+func (s *Server) GetListing(ctx context.Context, request *pb.GetListingRequest) (*pb.Listing, error) {
+    listing, err := s.marketplace.GetListing(ctx, request.Username, int(request.ListingId))
+    if err != nil {
+        s.log.Errorf("Error getting listing '%d': %v", request.ListingId, err)
+        return nil, statusOf(err)
+    }
+    if listing == nil {
+        return nil, status.Error(codes.NotFound, "not found")
+    }
+
+    return toListingMessage(*listing), nil
+}
+
+func (s *Server) GetCategory(request *pb.GetCategoryRequest, stream pb.MarketplaceService_GetCategoryServer) error {
+    sortBy := enum.SortBy(enum.SortByCreatedAt)
+    switch request.SortBy {
+    case pb.SortBy_SORT_BY_UNSPECIFIED, pb.SortBy_SORT_BY_CREATED_AT:
+    case pb.SortBy_SORT_BY_PRICE:
+        sortBy = enum.SortByPrice
+    default:
+        return status.Error(codes.InvalidArgument, "invalid sort key")
+    }
+    orderBy := enum.OrderBy(enum.OrderByDescending)
+    switch request.OrderBy {
+    case pb.OrderBy_ORDER_BY_UNSPECIFIED, pb.OrderBy_ORDER_BY_DESCENDING:
+    case pb.OrderBy_ORDER_BY_ASCENDING:
+        orderBy = enum.OrderByAscending
+    default:
+        return status.Error(codes.InvalidArgument, "invalid sort order")
+    }
+
+    limit := int(request.PageSize)
+    if limit == 0 {
+        limit = constant.DefaultCategoryPageSize
+    } else if limit < 0 {
+        return status.Error(codes.InvalidArgument, "invalid page size")
+    }
+
+    query := model.CategoryQuery{
+        Category: request.Category,
+        SortBy:   sortBy,
+        OrderBy:  orderBy,
+        Limit:    limit,
+        Cursor:   request.PageToken,
+
+        TitleContains: request.TitleContains,
+    }
+    if request.MinPrice != nil {
+        minPrice := int(*request.MinPrice)
+        query.MinPrice = &minPrice
+    }
+    if request.MaxPrice != nil {
+        maxPrice := int(*request.MaxPrice)
+        query.MaxPrice = &maxPrice
+    }
+    if request.CreatedAfter != nil {
+        createdAfter := request.CreatedAfter.AsTime()
+        query.CreatedAfter = &createdAfter
+    }
+    if request.CreatedBefore != nil {
+        createdBefore := request.CreatedBefore.AsTime()
+        query.CreatedBefore = &createdBefore
+    }
+
+    page, err := s.marketplace.GetCategory(stream.Context(), request.Username, query)
+    if err != nil {
+        s.log.Errorf("Error getting category '%s': %v", request.Category, err)
+        return statusOf(err)
+    }
+    // an empty page after a page token or with filters is a valid result, not a missing category
+    if len(page.Listings) == 0 && request.PageToken == "" && !query.HasFilters() {
+        return status.Error(codes.NotFound, "category not found")
+    }
+
+    if page.Cursor != "" {
+        stream.SetTrailer(metadata.Pairs(NextPageTokenTrailer, page.Cursor))
+    }
+    for _, listing := range page.Listings {
+        err = stream.Send(toListingMessage(listing))
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *Server) SearchListings(request *pb.SearchListingsRequest, stream pb.MarketplaceService_SearchListingsServer) error {
+    limit := int(request.Limit)
+    if limit == 0 {
+        limit = constant.DefaultSearchLimit
+    } else if limit < 0 {
+        return status.Error(codes.InvalidArgument, "invalid limit")
+    }
+
+    query := model.SearchQuery{
+        Text:     request.Query,
+        Category: request.Category,
+        Limit:    limit,
+    }
+    if request.MinPrice != nil {
+        minPrice := int(*request.MinPrice)
+        query.MinPrice = &minPrice
+    }
+    if request.MaxPrice != nil {
+        maxPrice := int(*request.MaxPrice)
+        query.MaxPrice = &maxPrice
+    }
+
+    listings, err := s.marketplace.Search(stream.Context(), request.Username, query)
+    if err != nil {
+        s.log.Errorf("Error searching '%s': %v", request.Query, err)
+        return statusOf(err)
+    }
+
+    for _, listing := range listings {
+        err = stream.Send(toListingMessage(listing))
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *Server) GetTopCategory(ctx context.Context, request *pb.GetTopCategoryRequest) (*pb.CategoryMetric, error) {
+    categoryMetric, err := s.marketplace.GetTopCategory(ctx, request.Username, request.Parent)
+    if err != nil {
+        s.log.Errorf("Error getting top category: %v", err)
+        return nil, statusOf(err)
+    }
+    if categoryMetric == nil {
+        return nil, status.Error(codes.NotFound, "no category found")
+    }
+
+    return &pb.CategoryMetric{
+        Category:      categoryMetric.Category,
+        CategoryCount: int64(categoryMetric.CategoryCount),
+    }, nil
+}
+
+func (s *Server) GetTopCategories(request *pb.GetTopCategoriesRequest, stream pb.MarketplaceService_GetTopCategoriesServer) error {
+    if request.Limit < 0 {
+        return status.Error(codes.InvalidArgument, "invalid limit")
+    }
+    query := model.TopCategoriesQuery{
+        Parent:       request.Parent,
+        Limit:        int(request.Limit),
+        ExcludeEmpty: request.ExcludeEmpty,
+    }
+
+    categoryMetrics, err := s.marketplace.GetTopCategories(stream.Context(), request.Username, query)
+    if err != nil {
+        s.log.Errorf("Error getting top categories: %v", err)
+        return statusOf(err)
+    }
+
+    for _, categoryMetric := range categoryMetrics {
+        err = stream.Send(&pb.CategoryMetric{
+            Category:      categoryMetric.Category,
+            CategoryCount: int64(categoryMetric.CategoryCount),
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *Server) UpdateListing(ctx context.Context, request *pb.UpdateListingRequest) (*pb.Listing, error) {
+    update := model.ListingUpdate{
+        Title:       request.Title,
+        Description: request.Description,
+        Category:    request.Category,
+    }
+    if request.Price != nil {
+        price := int(*request.Price)
+        update.Price = &price
+    }
+
+    listing, err := s.marketplace.UpdateListing(ctx, request.Token, int(request.ListingId), update)
+    if err != nil {
+        s.log.Errorf("Error updating listing '%d': %v", request.ListingId, err)
+        return nil, statusOf(err)
+    }
+
+    return toListingMessage(*listing), nil
+}
+
+func (s *Server) DeleteListing(ctx context.Context, request *pb.DeleteListingRequest) (*emptypb.Empty, error) {
+    err := s.marketplace.DeleteListing(ctx, request.Token, int(request.ListingId))
+    if err != nil {
+        s.log.Errorf("Error deleting listing '%d': %v", request.ListingId, err)
+        return nil, statusOf(err)
+    }
+
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) PublishListing(ctx context.Context, request *pb.ListingTransitionRequest) (*pb.Listing, error) {
+    return s.transitionListing(ctx, request, enum.ListingTransitionPublish)
+}
+
+func (s *Server) ReserveListing(ctx context.Context, request *pb.ListingTransitionRequest) (*pb.Listing, error) {
+    return s.transitionListing(ctx, request, enum.ListingTransitionReserve)
+}
+
+func (s *Server) UnreserveListing(ctx context.Context, request *pb.ListingTransitionRequest) (*pb.Listing, error) {
+    return s.transitionListing(ctx, request, enum.ListingTransitionUnreserve)
+}
+
+func (s *Server) WithdrawListing(ctx context.Context, request *pb.ListingTransitionRequest) (*pb.Listing, error) {
+    return s.transitionListing(ctx, request, enum.ListingTransitionWithdraw)
+}
+
+func (s *Server) HideListing(ctx context.Context, request *pb.ListingTransitionRequest) (*pb.Listing, error) {
+    return s.transitionListing(ctx, request, enum.ListingTransitionHide)
+}
+
+func (s *Server) UnhideListing(ctx context.Context, request *pb.ListingTransitionRequest) (*pb.Listing, error) {
+    return s.transitionListing(ctx, request, enum.ListingTransitionUnhide)
+}
+
+func (s *Server) transitionListing(ctx context.Context, request *pb.ListingTransitionRequest, transition enum.ListingTransition) (*pb.Listing, error) {
+    listing, err := s.marketplace.TransitionListing(ctx, request.Token, int(request.ListingId), transition)
+    if err != nil {
+        s.log.Errorf("Error trying to %s listing '%d': %v", transition, request.ListingId, err)
+        return nil, statusOf(err)
+    }
+
+    return toListingMessage(*listing), nil
+}
+
+func (s *Server) BuyListing(ctx context.Context, request *pb.BuyListingRequest) (*pb.Order, error) {
+    order, err := s.marketplace.BuyListing(ctx, request.Token, int(request.ListingId))
+    if err != nil {
+        s.log.Errorf("Error buying listing '%d': %v", request.ListingId, err)
+        return nil, statusOf(err)
+    }
+
+    return toOrderMessage(*order), nil
+}
+
+func (s *Server) GetOrders(request *pb.GetOrdersRequest, stream pb.MarketplaceService_GetOrdersServer) error {
+    orders, err := s.marketplace.GetOrders(stream.Context(), request.Username)
+    if err != nil {
+        s.log.Errorf("Error getting orders of '%s': %v", request.Username, err)
+        return statusOf(err)
+    }
+    if len(orders) == 0 {
+        return status.Error(codes.NotFound, "no orders found")
+    }
+
+    for _, order := range orders {
+        err = stream.Send(toOrderMessage(order))
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *Server) CreateCategory(ctx context.Context, request *pb.CreateCategoryRequest) (*pb.Category, error) {
+    category, err := s.marketplace.CreateCategory(ctx, request.Token, request.Name)
+    if err != nil {
+        s.log.Errorf("Error creating category '%s': %v", request.Name, err)
+        return nil, statusOf(err)
+    }
+    if category == nil {
+        return nil, status.Error(codes.AlreadyExists, "category already existing")
+    }
+
+    return toCategoryMessage(*category), nil
+}
+
+func (s *Server) GetCategories(request *pb.GetCategoriesRequest, stream pb.MarketplaceService_GetCategoriesServer) error {
+    categories, err := s.marketplace.GetCategories(stream.Context(), request.Username)
+    if err != nil {
+        s.log.Errorf("Error getting categories: %v", err)
+        return statusOf(err)
+    }
+
+    for _, category := range categories {
+        err = stream.Send(toCategoryMessage(category))
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *Server) RenameCategory(ctx context.Context, request *pb.RenameCategoryRequest) (*emptypb.Empty, error) {
+    err := s.marketplace.RenameCategory(ctx, request.Token, request.Category, request.NewName)
+    if err != nil {
+        s.log.Errorf("Error renaming category '%s': %v", request.Category, err)
+        return nil, statusOf(err)
+    }
+
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) MergeCategory(ctx context.Context, request *pb.MergeCategoryRequest) (*emptypb.Empty, error) {
+    err := s.marketplace.MergeCategory(ctx, request.Token, request.Category, request.Into)
+    if err != nil {
+        s.log.Errorf("Error merging category '%s': %v", request.Category, err)
+        return nil, statusOf(err)
+    }
+
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) RetireCategory(ctx context.Context, request *pb.RetireCategoryRequest) (*emptypb.Empty, error) {
+    err := s.marketplace.RetireCategory(ctx, request.Token, request.Category)
+    if err != nil {
+        s.log.Errorf("Error retiring category '%s': %v", request.Category, err)
+        return nil, statusOf(err)
+    }
+
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) GrantRole(ctx context.Context, request *pb.GrantRoleRequest) (*pb.User, error) {
+    user, err := s.marketplace.GrantRole(ctx, request.Token, request.Username, enum.Role(request.Role))
+    if err != nil {
+        s.log.Errorf("Error granting role %s to '%s': %v", request.Role, request.Username, err)
+        return nil, statusOf(err)
+    }
+
+    return toUserMessage(*user), nil
+}
+
+func (s *Server) RevokeRole(ctx context.Context, request *pb.RevokeRoleRequest) (*pb.User, error) {
+    user, err := s.marketplace.RevokeRole(ctx, request.Token, request.Username)
+    if err != nil {
+        s.log.Errorf("Error revoking role of '%s': %v", request.Username, err)
+        return nil, statusOf(err)
+    }
+
+    return toUserMessage(*user), nil
+}
+
+func toApiKeyMessage(key model.ApiKey) *pb.ApiKey {
+    return &pb.ApiKey{
+        KeyId:     key.KeyId,
+        Username:  key.Username,
+        Scope:     key.Scope.String(),
+        CreatedAt: timestamppb.New(key.CreatedAt),
+        ExpiresAt: timestamppb.New(key.ExpiresAt),
+    }
+}
+
+func toUserMessage(user model.User) *pb.User {
+    return &pb.User{Username: user.Username, Role: string(user.Role)}
+}
+
+func toListingMessage(listing model.Listing) *pb.Listing {
+    return &pb.Listing{
+        ListingId:   int64(listing.ListingId),
+        Username:    listing.Username,
+        Title:       listing.Title,
+        Description: listing.Description,
+        Price:       int64(listing.Price),
+        Category:    listing.Category,
+        CreatedAt:   timestamppb.New(listing.CreatedAt),
+        Version:     int64(listing.Version),
+        Status:      listing.CurrentStatus().String(),
+        ReservedBy:  listing.ReservedBy,
+    }
+}
+
+func toOrderMessage(order model.Order) *pb.Order {
+    return &pb.Order{
+        ListingId: int64(order.ListingId),
+        Title:     order.Title,
+        Price:     int64(order.Price),
+        Seller:    order.Seller,
+        Buyer:     order.Buyer,
+        CreatedAt: timestamppb.New(order.CreatedAt),
+    }
+}
+
+func toCategoryMessage(category model.Category) *pb.Category {
+    return &pb.Category{
+        Name:   category.Name,
+        Status: category.Status.String(),
+    }
+}
@@ -0,0 +1,628 @@
+package rpc
+
+import (
+    "context"
+    "errors"
+    "go.uber.org/zap"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/grpc/test/bufconn"
+    "google.golang.org/protobuf/types/known/durationpb"
+    "google.golang.org/protobuf/types/known/timestamppb"
+    "io"
+    "marketplace-platform/pkg/api/pb"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
+    "marketplace-platform/pkg/data/memory"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/service"
+    "net"
+    "testing"
+    "time"
+)
+
+// newTestClient serves a Server backed by the in-memory store over an in-process bufconn listener
+func newTestClient(t *testing.T) pb.MarketplaceServiceClient {
+    return newTestClientOf(t, memory.NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+// newTestClientOf serves the marketplace on top of store, with the deadlines of the servers
+func newTestClientOf(t *testing.T, store data.MarketplaceStore) pb.MarketplaceServiceClient {
+    log := zap.NewNop().Sugar()
+    listener := bufconn.Listen(1024 * 1024)
+
+    marketplaceServer := NewServer(service.NewMarketplace(store, log), log)
+    server := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(marketplaceServer.UnaryDeadline, marketplaceServer.UnaryIdempotencyKey),
+        grpc.StreamInterceptor(marketplaceServer.StreamDeadline))
+    pb.RegisterMarketplaceServiceServer(server, marketplaceServer)
+    go func() {
+        _ = server.Serve(listener)
+    }()
+    t.Cleanup(server.Stop)
+
+    conn, err := grpc.Dial("bufnet",
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+            return listener.DialContext(ctx)
+        }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()),
+    )
+    if err != nil {
+        t.Fatalf("could not dial bufconn: %v", err)
+    }
+    t.Cleanup(func() {
+        _ = conn.Close()
+    })
+
+    return pb.NewMarketplaceServiceClient(conn)
+}
+
+func assertCode(t *testing.T, err error, expected codes.Code) {
+    t.Helper()
+    if status.Code(err) != expected {
+        t.Fatalf("expected status %s, got %v", expected, err)
+    }
+}
+
+func TestServer(t *testing.T) {
+    t.Setenv(constant.AdminUsersEnvKey, "admin")
+    client := newTestClient(t)
+    ctx := context.Background()
+
+    // authentication and validation errors
+    _, err := client.CreateListing(ctx, &pb.CreateListingRequest{Token: "unknown-token", Title: "Phone model 8", Price: 100000, Category: "Electronics"})
+    assertCode(t, err, codes.Unauthenticated)
+    _, err = client.Register(ctx, &pb.RegisterRequest{Username: "", Password: "password1"})
+    assertCode(t, err, codes.InvalidArgument)
+    _, err = client.Register(ctx, &pb.RegisterRequest{Username: "user1", Password: "short"})
+    assertCode(t, err, codes.InvalidArgument)
+
+    // happy path
+    user, err := client.Register(ctx, &pb.RegisterRequest{Username: "user1", Password: "password1"})
+    if err != nil || user.Username != "user1" {
+        t.Fatalf("could not register user1: %v", err)
+    }
+    _, err = client.Register(ctx, &pb.RegisterRequest{Username: "user1", Password: "password1"})
+    assertCode(t, err, codes.AlreadyExists)
+    _, err = client.Login(ctx, &pb.LoginRequest{Username: "user1", Password: "password2"})
+    assertCode(t, err, codes.Unauthenticated)
+    _, err = client.Login(ctx, &pb.LoginRequest{Username: "user9", Password: "password1"})
+    assertCode(t, err, codes.Unauthenticated)
+
+    // mutating RPCs take the token of a session
+    tokens := make(map[string]string)
+    for _, username := range []string{"user1", "user2", "admin"} {
+        if username != "user1" {
+            _, err = client.Register(ctx, &pb.RegisterRequest{Username: username, Password: "password1"})
+            if err != nil {
+                t.Fatalf("could not register %s: %v", username, err)
+            }
+        }
+        session, err := client.Login(ctx, &pb.LoginRequest{Username: username, Password: "password1"})
+        if err != nil || session.Username != username || session.Token == "" || !session.ExpiresAt.AsTime().After(time.Now()) {
+            t.Fatalf("could not log in %s: %v, %v", username, session, err)
+        }
+        tokens[username] = session.Token
+    }
+    for _, name := range []string{"Electronics", "Sports", "Sports/Running", "Fashion"} {
+        category, err := client.CreateCategory(ctx, &pb.CreateCategoryRequest{Token: tokens["admin"], Name: name})
+        if err != nil || category.Name != name || category.Status != "ACTIVE" {
+            t.Fatalf("unexpected category %v: %v", category, err)
+        }
+    }
+
+    _, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: tokens["user1"], Title: "", Price: 100000, Category: "Electronics"})
+    assertCode(t, err, codes.InvalidArgument)
+    requests := []*pb.CreateListingRequest{
+        {Token: tokens["user1"], Title: "Phone model 8", Description: "Black color", Price: 100000, Category: "Electronics"},
+        {Token: tokens["user1"], Title: "Black shoes", Description: "Training shoes", Price: 10000, Category: "Sports"},
+        {Token: tokens["user2"], Title: "T-shirt", Description: "White color", Price: 2000, Category: "Sports"},
+    }
+    for i, request := range requests {
+        listing, err := client.CreateListing(ctx, request)
+        if err != nil {
+            t.Fatalf("could not create listing: %v", err)
+        }
+        if listing.ListingId != int64(100001+i) {
+            t.Fatalf("expected listing ID %d, got %d", 100001+i, listing.ListingId)
+        }
+    }
+
+    listing, err := client.GetListing(ctx, &pb.GetListingRequest{Username: "user2", ListingId: 100001})
+    if err != nil || listing.Title != "Phone model 8" || listing.CreatedAt.AsTime().IsZero() {
+        t.Fatalf("unexpected listing %v: %v", listing, err)
+    }
+    _, err = client.GetListing(ctx, &pb.GetListingRequest{Username: "user2", ListingId: 900001})
+    assertCode(t, err, codes.NotFound)
+
+    // category listings are streamed in the requested order
+    stream, err := client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Sports", SortBy: pb.SortBy_SORT_BY_PRICE, OrderBy: pb.OrderBy_ORDER_BY_ASCENDING})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    var listingIds []int64
+    for {
+        listing, err := stream.Recv()
+        if errors.Is(err, io.EOF) {
+            break
+        }
+        if err != nil {
+            t.Fatalf("could not receive listing: %v", err)
+        }
+        listingIds = append(listingIds, listing.ListingId)
+    }
+    if len(listingIds) != 2 || listingIds[0] != 100003 || listingIds[1] != 100002 {
+        t.Fatalf("expected listings [100003 100002], got %v", listingIds)
+    }
+
+    // the next page token is returned in the trailer of a full page
+    stream, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Sports", SortBy: pb.SortBy_SORT_BY_PRICE, OrderBy: pb.OrderBy_ORDER_BY_ASCENDING, PageSize: 1})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    listingIds = nil
+    for {
+        listing, err := stream.Recv()
+        if errors.Is(err, io.EOF) {
+            pageTokens := stream.Trailer().Get(NextPageTokenTrailer)
+            if len(pageTokens) == 0 {
+                break
+            }
+            stream, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Sports", SortBy: pb.SortBy_SORT_BY_PRICE, OrderBy: pb.OrderBy_ORDER_BY_ASCENDING, PageSize: 1, PageToken: pageTokens[0]})
+            if err != nil {
+                t.Fatalf("could not get category: %v", err)
+            }
+            continue
+        }
+        if err != nil {
+            t.Fatalf("could not receive listing: %v", err)
+        }
+        listingIds = append(listingIds, listing.ListingId)
+    }
+    if len(listingIds) != 2 || listingIds[0] != 100003 || listingIds[1] != 100002 {
+        t.Fatalf("expected paged listings [100003 100002], got %v", listingIds)
+    }
+
+    // filters narrow the page, and no match is an empty stream rather than a missing category
+    minPrice := int64(2500)
+    stream, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Sports", MinPrice: &minPrice, TitleContains: "shoes"})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    listing, err = stream.Recv()
+    if err != nil || listing.ListingId != 100002 {
+        t.Fatalf("expected filtered listing 100002, got %v: %v", listing, err)
+    }
+    _, err = stream.Recv()
+    if !errors.Is(err, io.EOF) {
+        t.Fatalf("expected a single filtered listing, got %v", err)
+    }
+    stream, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Sports", CreatedAfter: timestamppb.New(time.Now().Add(time.Hour))})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    _, err = stream.Recv()
+    if !errors.Is(err, io.EOF) {
+        t.Fatalf("expected no listing created in the future, got %v", err)
+    }
+
+    stream, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Sports", PageToken: "xyz"})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    _, err = stream.Recv()
+    assertCode(t, err, codes.InvalidArgument)
+
+    stream, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Username: "user1", Category: "Fashion"})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    _, err = stream.Recv()
+    assertCode(t, err, codes.NotFound)
+
+    // search results are streamed most relevant first, the shorter listing ranking first for the same term frequency
+    searchStream, err := client.SearchListings(ctx, &pb.SearchListingsRequest{Username: "user1", Query: "colors"})
+    if err != nil {
+        t.Fatalf("could not search: %v", err)
+    }
+    listingIds = nil
+    for {
+        listing, err := searchStream.Recv()
+        if errors.Is(err, io.EOF) {
+            break
+        }
+        if err != nil {
+            t.Fatalf("could not receive listing: %v", err)
+        }
+        listingIds = append(listingIds, listing.ListingId)
+    }
+    if len(listingIds) != 2 || listingIds[0] != 100003 || listingIds[1] != 100001 {
+        t.Fatalf("expected search results [100003 100001], got %v", listingIds)
+    }
+    maxPrice := int64(5000)
+    searchStream, err = client.SearchListings(ctx, &pb.SearchListingsRequest{Username: "user1", Query: "shoes", MaxPrice: &maxPrice})
+    if err != nil {
+        t.Fatalf("could not search: %v", err)
+    }
+    _, err = searchStream.Recv()
+    if !errors.Is(err, io.EOF) {
+        t.Fatalf("expected no search result under the price bound, got %v", err)
+    }
+    searchStream, err = client.SearchListings(ctx, &pb.SearchListingsRequest{Username: "user1", Query: " "})
+    if err != nil {
+        t.Fatalf("could not search: %v", err)
+    }
+    _, err = searchStream.Recv()
+    assertCode(t, err, codes.InvalidArgument)
+
+    categoryMetric, err := client.GetTopCategory(ctx, &pb.GetTopCategoryRequest{Username: "user1"})
+    if err != nil || categoryMetric.Category != "Sports" || categoryMetric.CategoryCount != 2 {
+        t.Fatalf("unexpected top category %v: %v", categoryMetric, err)
+    }
+
+    price := int64(2500)
+    _, err = client.UpdateListing(ctx, &pb.UpdateListingRequest{Token: tokens["user2"], ListingId: 100003})
+    assertCode(t, err, codes.InvalidArgument)
+    listing, err = client.UpdateListing(ctx, &pb.UpdateListingRequest{Token: tokens["user2"], ListingId: 100003, Price: &price})
+    if err != nil || listing.Price != price || listing.Version != 2 || listing.Title != "T-shirt" {
+        t.Fatalf("unexpected updated listing %v: %v", listing, err)
+    }
+
+    _, err = client.DeleteListing(ctx, &pb.DeleteListingRequest{Token: tokens["user1"], ListingId: 100003})
+    assertCode(t, err, codes.PermissionDenied)
+    _, err = client.DeleteListing(ctx, &pb.DeleteListingRequest{Token: tokens["user2"], ListingId: 100003})
+    if err != nil {
+        t.Fatalf("could not delete listing: %v", err)
+    }
+    _, err = client.DeleteListing(ctx, &pb.DeleteListingRequest{Token: tokens["user2"], ListingId: 100003})
+    assertCode(t, err, codes.NotFound)
+
+    // purchase
+    _, err = client.BuyListing(ctx, &pb.BuyListingRequest{Token: tokens["user1"], ListingId: 100001})
+    assertCode(t, err, codes.PermissionDenied)
+    order, err := client.BuyListing(ctx, &pb.BuyListingRequest{Token: tokens["user2"], ListingId: 100001})
+    if err != nil || order.Price != 100000 || order.Seller != "user1" || order.Buyer != "user2" {
+        t.Fatalf("unexpected order %v: %v", order, err)
+    }
+    _, err = client.BuyListing(ctx, &pb.BuyListingRequest{Token: tokens["user2"], ListingId: 100001})
+    assertCode(t, err, codes.FailedPrecondition)
+
+    orderStream, err := client.GetOrders(ctx, &pb.GetOrdersRequest{Username: "user1"})
+    if err != nil {
+        t.Fatalf("could not get orders: %v", err)
+    }
+    order, err = orderStream.Recv()
+    if err != nil || order.ListingId != 100001 {
+        t.Fatalf("unexpected order %v: %v", order, err)
+    }
+    _, err = orderStream.Recv()
+    if !errors.Is(err, io.EOF) {
+        t.Fatalf("expected a single order, got %v", err)
+    }
+
+    // listing lifecycle
+    listing, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: tokens["user2"], Title: "Jacket", Price: 8000, Category: "Fashion", Draft: true})
+    if err != nil || listing.Status != "DRAFT" {
+        t.Fatalf("unexpected draft listing %v: %v", listing, err)
+    }
+    _, err = client.ReserveListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user1"], ListingId: listing.ListingId})
+    assertCode(t, err, codes.FailedPrecondition)
+    _, err = client.PublishListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user1"], ListingId: listing.ListingId})
+    assertCode(t, err, codes.PermissionDenied)
+    listing, err = client.PublishListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user2"], ListingId: listing.ListingId})
+    if err != nil || listing.Status != "ACTIVE" {
+        t.Fatalf("unexpected published listing %v: %v", listing, err)
+    }
+    listing, err = client.ReserveListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user1"], ListingId: listing.ListingId})
+    if err != nil || listing.Status != "RESERVED" || listing.ReservedBy != "user1" {
+        t.Fatalf("unexpected reserved listing %v: %v", listing, err)
+    }
+    listing, err = client.UnreserveListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user2"], ListingId: listing.ListingId})
+    if err != nil || listing.Status != "ACTIVE" || listing.ReservedBy != "" {
+        t.Fatalf("unexpected unreserved listing %v: %v", listing, err)
+    }
+    listing, err = client.WithdrawListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user2"], ListingId: listing.ListingId})
+    if err != nil || listing.Status != "WITHDRAWN" {
+        t.Fatalf("unexpected withdrawn listing %v: %v", listing, err)
+    }
+    _, err = client.BuyListing(ctx, &pb.BuyListingRequest{Token: tokens["user1"], ListingId: listing.ListingId})
+    assertCode(t, err, codes.FailedPrecondition)
+
+    // category hierarchy
+    _, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: tokens["user1"], Title: "Trail shoes", Price: 9000, Category: "Sports/Running"})
+    if err != nil {
+        t.Fatalf("could not create listing in a subcategory: %v", err)
+    }
+    categoryMetric, err = client.GetTopCategory(ctx, &pb.GetTopCategoryRequest{Username: "user1", Parent: "Sports"})
+    if err != nil || categoryMetric.Category != "Sports/Running" || categoryMetric.CategoryCount != 1 {
+        t.Fatalf("unexpected top subcategory %v: %v", categoryMetric, err)
+    }
+    categoryMetric, err = client.GetTopCategory(ctx, &pb.GetTopCategoryRequest{Username: "user1"})
+    if err != nil || categoryMetric.Category != "Sports" || categoryMetric.CategoryCount != 2 {
+        t.Fatalf("unexpected top category %v: %v", categoryMetric, err)
+    }
+    _, err = client.GetTopCategory(ctx, &pb.GetTopCategoryRequest{Username: "user1", Parent: "Sports/Running"})
+    assertCode(t, err, codes.NotFound)
+
+    rankingStream, err := client.GetTopCategories(ctx, &pb.GetTopCategoriesRequest{Username: "user1", Limit: 2, ExcludeEmpty: true})
+    if err != nil {
+        t.Fatalf("could not get top categories: %v", err)
+    }
+    var ranking []string
+    for {
+        categoryMetric, err := rankingStream.Recv()
+        if errors.Is(err, io.EOF) {
+            break
+        }
+        if err != nil {
+            t.Fatalf("could not receive top category: %v", err)
+        }
+        ranking = append(ranking, categoryMetric.Category)
+        if categoryMetric.CategoryCount != 2 {
+            t.Fatalf("unexpected top category %v", categoryMetric)
+        }
+    }
+    if len(ranking) != 1 || ranking[0] != "Sports" {
+        t.Fatalf("unexpected top categories %v", ranking)
+    }
+    rankingStream, err = client.GetTopCategories(ctx, &pb.GetTopCategoriesRequest{Username: "user1", Limit: -1})
+    if err == nil {
+        _, err = rankingStream.Recv()
+    }
+    assertCode(t, err, codes.InvalidArgument)
+
+    // category catalog
+    _, err = client.CreateCategory(ctx, &pb.CreateCategoryRequest{Token: tokens["user1"], Name: "Toys"})
+    assertCode(t, err, codes.PermissionDenied)
+    _, err = client.CreateCategory(ctx, &pb.CreateCategoryRequest{Token: tokens["admin"], Name: "Sports"})
+    assertCode(t, err, codes.AlreadyExists)
+    _, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: tokens["user1"], Title: "Car", Price: 500, Category: "Toys"})
+    assertCode(t, err, codes.NotFound)
+    _, err = client.RenameCategory(ctx, &pb.RenameCategoryRequest{Token: tokens["admin"], Category: "Sports/Running", NewName: "Sports/Trail"})
+    if err != nil {
+        t.Fatalf("could not rename category: %v", err)
+    }
+    _, err = client.MergeCategory(ctx, &pb.MergeCategoryRequest{Token: tokens["admin"], Category: "Fashion", Into: "Toys"})
+    assertCode(t, err, codes.NotFound)
+    _, err = client.MergeCategory(ctx, &pb.MergeCategoryRequest{Token: tokens["admin"], Category: "Fashion", Into: "Sports"})
+    if err != nil {
+        t.Fatalf("could not merge category: %v", err)
+    }
+    _, err = client.RetireCategory(ctx, &pb.RetireCategoryRequest{Token: tokens["user1"], Category: "Sports"})
+    assertCode(t, err, codes.PermissionDenied)
+    _, err = client.RetireCategory(ctx, &pb.RetireCategoryRequest{Token: tokens["admin"], Category: "Sports"})
+    if err != nil {
+        t.Fatalf("could not retire category: %v", err)
+    }
+    _, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: tokens["user1"], Title: "Ball", Price: 500, Category: "Sports/Trail"})
+    assertCode(t, err, codes.FailedPrecondition)
+
+    categoryStream, err := client.GetCategories(ctx, &pb.GetCategoriesRequest{Username: "user1"})
+    if err != nil {
+        t.Fatalf("could not get categories: %v", err)
+    }
+    var categories []string
+    for {
+        category, err := categoryStream.Recv()
+        if errors.Is(err, io.EOF) {
+            break
+        }
+        if err != nil {
+            t.Fatalf("could not receive category: %v", err)
+        }
+        categories = append(categories, category.Name+"|"+category.Status)
+    }
+    if len(categories) != 3 || categories[0] != "Electronics|ACTIVE" || categories[1] != "Sports|RETIRED" || categories[2] != "Sports/Trail|ACTIVE" {
+        t.Fatalf("unexpected categories %v", categories)
+    }
+
+    // moderators hide listings and only admins delete the listings of other users
+    _, err = client.GrantRole(ctx, &pb.GrantRoleRequest{Token: tokens["user1"], Username: "user2", Role: "MODERATOR"})
+    assertCode(t, err, codes.PermissionDenied)
+    _, err = client.GrantRole(ctx, &pb.GrantRoleRequest{Token: tokens["admin"], Username: "user2", Role: "OWNER"})
+    assertCode(t, err, codes.InvalidArgument)
+    _, err = client.HideListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user2"], ListingId: 100002})
+    assertCode(t, err, codes.PermissionDenied)
+    user, err = client.GrantRole(ctx, &pb.GrantRoleRequest{Token: tokens["admin"], Username: "USER2", Role: "MODERATOR"})
+    if err != nil || user.Username != "user2" || user.Role != "MODERATOR" {
+        t.Fatalf("expected user2 to be a moderator, got %v: %v", user, err)
+    }
+    listing, err = client.HideListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user2"], ListingId: 100002})
+    if err != nil || listing.Status != "HIDDEN" {
+        t.Fatalf("expected hidden listing, got %v: %v", listing, err)
+    }
+    listing, err = client.UnhideListing(ctx, &pb.ListingTransitionRequest{Token: tokens["user2"], ListingId: 100002})
+    if err != nil || listing.Status != "ACTIVE" {
+        t.Fatalf("expected active listing, got %v: %v", listing, err)
+    }
+    _, err = client.DeleteListing(ctx, &pb.DeleteListingRequest{Token: tokens["user2"], ListingId: 100002})
+    assertCode(t, err, codes.PermissionDenied)
+    _, err = client.DeleteListing(ctx, &pb.DeleteListingRequest{Token: tokens["admin"], ListingId: 100002})
+    if err != nil {
+        t.Fatalf("could not delete listing as admin: %v", err)
+    }
+    user, err = client.RevokeRole(ctx, &pb.RevokeRoleRequest{Token: tokens["admin"], Username: "user2"})
+    if err != nil || user.Role != "USER" {
+        t.Fatalf("expected user2 to be a regular user, got %v: %v", user, err)
+    }
+    _, err = client.RevokeRole(ctx, &pb.RevokeRoleRequest{Token: tokens["admin"], Username: "nobody"})
+    assertCode(t, err, codes.Unauthenticated)
+
+    // api keys act for their owner within their scope, and are managed with a session
+    _, err = client.CreateApiKey(ctx, &pb.CreateApiKeyRequest{Token: tokens["user1"], Scope: "owner"})
+    assertCode(t, err, codes.InvalidArgument)
+    _, err = client.CreateApiKey(ctx, &pb.CreateApiKeyRequest{Token: tokens["user1"], Scope: "read-only", Ttl: durationpb.New(-time.Hour)})
+    assertCode(t, err, codes.InvalidArgument)
+    readKey, err := client.CreateApiKey(ctx, &pb.CreateApiKeyRequest{Token: tokens["user1"], Scope: "read-only"})
+    if err != nil || readKey.Username != "user1" || readKey.Scope != "read-only" ||
+        !readKey.ExpiresAt.AsTime().Equal(readKey.CreatedAt.AsTime().Add(constant.DefaultApiKeyTtl)) {
+        t.Fatalf("unexpected api key %v: %v", readKey, err)
+    }
+    writeKey, err := client.CreateApiKey(ctx, &pb.CreateApiKeyRequest{Token: tokens["user1"], Scope: "listings:write", Ttl: durationpb.New(time.Hour)})
+    if err != nil || writeKey.Scope != "listings:write" {
+        t.Fatalf("unexpected api key %v: %v", writeKey, err)
+    }
+    _, err = client.CreateApiKey(ctx, &pb.CreateApiKeyRequest{Token: writeKey.Key, Scope: "admin"})
+    assertCode(t, err, codes.Unauthenticated)
+
+    keyStream, err := client.GetApiKeys(ctx, &pb.GetApiKeysRequest{Token: tokens["user1"]})
+    if err != nil {
+        t.Fatalf("could not get api keys: %v", err)
+    }
+    var keyIds []string
+    for {
+        apiKey, err := keyStream.Recv()
+        if errors.Is(err, io.EOF) {
+            break
+        }
+        if err != nil {
+            t.Fatalf("could not receive api key: %v", err)
+        }
+        if apiKey.Key != "" {
+            t.Fatalf("expected listed api keys to leave the key out, got %v", apiKey)
+        }
+        keyIds = append(keyIds, apiKey.KeyId)
+    }
+    if len(keyIds) != 2 {
+        t.Fatalf("expected 2 api keys, got %v", keyIds)
+    }
+
+    categoryStream, err = client.GetCategories(ctx, &pb.GetCategoriesRequest{Username: readKey.Key})
+    if err != nil {
+        t.Fatalf("could not get categories: %v", err)
+    }
+    _, err = categoryStream.Recv()
+    if err != nil {
+        t.Fatalf("could not get categories with a read-only api key: %v", err)
+    }
+    _, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: readKey.Key, Title: "Ball", Price: 500, Category: "Electronics"})
+    assertCode(t, err, codes.PermissionDenied)
+    listing, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: writeKey.Key, Title: "Ball", Price: 500, Category: "Electronics"})
+    if err != nil || listing.Username != "user1" {
+        t.Fatalf("unexpected listing created with an api key %v: %v", listing, err)
+    }
+    _, err = client.RetireCategory(ctx, &pb.RetireCategoryRequest{Token: writeKey.Key, Category: "Electronics"})
+    assertCode(t, err, codes.PermissionDenied)
+
+    _, err = client.RevokeApiKey(ctx, &pb.RevokeApiKeyRequest{Token: tokens["user2"], KeyId: readKey.KeyId})
+    assertCode(t, err, codes.NotFound)
+    _, err = client.RevokeApiKey(ctx, &pb.RevokeApiKeyRequest{Token: tokens["user1"], KeyId: readKey.KeyId})
+    if err != nil {
+        t.Fatalf("could not revoke api key: %v", err)
+    }
+    _, err = client.GetListing(ctx, &pb.GetListingRequest{Username: readKey.Key, ListingId: listing.ListingId})
+    assertCode(t, err, codes.Unauthenticated)
+
+    // a revoked session can no longer be used
+    _, err = client.Logout(ctx, &pb.LogoutRequest{Token: tokens["user1"]})
+    if err != nil {
+        t.Fatalf("could not log out: %v", err)
+    }
+    _, err = client.CreateListing(ctx, &pb.CreateListingRequest{Token: tokens["user1"], Title: "Ball", Price: 500, Category: "Electronics"})
+    assertCode(t, err, codes.Unauthenticated)
+}
+
+func TestRateLimit(t *testing.T) {
+    t.Setenv(constant.RateLimitReadEnvKey, "2/1h")
+    client := newTestClient(t)
+    ctx := context.Background()
+
+    _, err := client.Register(ctx, &pb.RegisterRequest{Username: "user1", Password: "password1"})
+    if err != nil {
+        t.Fatalf("could not register user1: %v", err)
+    }
+    for i := 0; i < 2; i++ {
+        _, err = client.GetListing(ctx, &pb.GetListingRequest{Username: "user1", ListingId: 100001})
+        assertCode(t, err, codes.NotFound)
+    }
+    _, err = client.GetListing(ctx, &pb.GetListingRequest{Username: "User1", ListingId: 100001})
+    assertCode(t, err, codes.ResourceExhausted)
+    // unknown users are rejected before they are rate limited
+    _, err = client.GetListing(ctx, &pb.GetListingRequest{Username: "user9", ListingId: 100001})
+    assertCode(t, err, codes.Unauthenticated)
+}
+
+func TestIdempotencyKey(t *testing.T) {
+    t.Setenv(constant.AdminUsersEnvKey, "admin")
+    client := newTestClient(t)
+    ctx := context.Background()
+    withKey := func(key string) context.Context {
+        return metadata.AppendToOutgoingContext(ctx, IdempotencyKeyMetadata, key)
+    }
+
+    tokens := make(map[string]string)
+    for _, username := range []string{"user1", "admin"} {
+        _, err := client.Register(ctx, &pb.RegisterRequest{Username: username, Password: "password1"})
+        if err != nil {
+            t.Fatalf("could not register %s: %v", username, err)
+        }
+        session, err := client.Login(ctx, &pb.LoginRequest{Username: username, Password: "password1"})
+        if err != nil {
+            t.Fatalf("could not log in %s: %v", username, err)
+        }
+        tokens[username] = session.Token
+    }
+
+    // a replayed category creation succeeds, while creating the category again fails
+    for i := 0; i < 2; i++ {
+        category, err := client.CreateCategory(withKey("category-1"), &pb.CreateCategoryRequest{Token: tokens["admin"], Name: "Sports"})
+        if err != nil || category.Name != "Sports" {
+            t.Fatalf("unexpected category %v: %v", category, err)
+        }
+    }
+    _, err := client.CreateCategory(ctx, &pb.CreateCategoryRequest{Token: tokens["admin"], Name: "Sports"})
+    assertCode(t, err, codes.AlreadyExists)
+
+    request := &pb.CreateListingRequest{Token: tokens["user1"], Title: "Ball", Description: "Football", Price: 100, Category: "Sports"}
+    first, err := client.CreateListing(withKey("listing-1"), request)
+    if err != nil || first.ListingId != 100001 {
+        t.Fatalf("unexpected listing %v: %v", first, err)
+    }
+    replayed, err := client.CreateListing(withKey("listing-1"), request)
+    if err != nil || replayed.ListingId != first.ListingId || !replayed.CreatedAt.AsTime().Equal(first.CreatedAt.AsTime()) {
+        t.Fatalf("expected the first listing %v to be replayed, got %v: %v", first, replayed, err)
+    }
+    _, err = client.CreateListing(withKey("listing-1"), &pb.CreateListingRequest{Token: tokens["user1"], Title: "Ball", Price: 200, Category: "Sports"})
+    assertCode(t, err, codes.InvalidArgument)
+    listing, err := client.CreateListing(ctx, request)
+    if err != nil || listing.ListingId != 100002 {
+        t.Fatalf("expected a request without a key to create another listing, got %v: %v", listing, err)
+    }
+}
+
+// stalledStore stands for a database that stops answering: its listings never arrive
+type stalledStore struct {
+    *memory.MemoryDataAccess
+}
+
+func (s stalledStore) GetListing(ctx context.Context, _ int) (*model.Listing, error) {
+    <-ctx.Done()
+    return nil, ctx.Err()
+}
+
+func (s stalledStore) SearchListings(ctx context.Context, _ model.SearchQuery) ([]model.Listing, error) {
+    <-ctx.Done()
+    return nil, ctx.Err()
+}
+
+func TestTimeout(t *testing.T) {
+    t.Setenv(constant.CommandTimeoutEnvKey, "50ms")
+    store := stalledStore{memory.NewMemoryDataAccess(zap.NewNop().Sugar())}
+    client := newTestClientOf(t, store)
+    ctx := context.Background()
+
+    _, err := client.Register(ctx, &pb.RegisterRequest{Username: "user1", Password: "password1"})
+    if err != nil {
+        t.Fatalf("could not register user1: %v", err)
+    }
+    _, err = client.GetListing(ctx, &pb.GetListingRequest{Username: "user1", ListingId: 100001})
+    assertCode(t, err, codes.DeadlineExceeded)
+    stream, err := client.SearchListings(ctx, &pb.SearchListingsRequest{Username: "user1", Query: "phone"})
+    if err == nil {
+        _, err = stream.Recv()
+    }
+    assertCode(t, err, codes.DeadlineExceeded)
+}
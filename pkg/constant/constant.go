@@ -1,19 +1,102 @@
 package constant
 
+import (
+    "time"
+)
+
 var (
     TableName              = "Listing"
     CategoryPriceIndex     = "CategoryPriceIndex"
     CategoryCreatedAtIndex = "CategoryCreatedAtIndex"
     CategoryCountIndex     = "CategoryCountIndex"
-    ListingIdIndex         = "ListingIdIndex"
 
-    ListingTablePartitionKeyName = "ListingId"
-    ListingTableSortKeyName      = "Username"
-    UserRootRecordPartitionKey   = -1
+    ListingTablePartitionKeyName  = "ListingId"
+    ListingTableSortKeyName       = "Username"
+    UserRootRecordPartitionKey    = -1
+    UserDisplayNameAttributeName  = "DisplayName"
+    UserPasswordHashAttributeName = "PasswordHash"
+    UserRoleAttributeName         = "Role"
 
     CategoryMetricRecordPartitionKey = -2
+    CategoryCountAttributeName       = "CategoryCount"
+
+    ListingIdCounterRecordPartitionKey = -3
+    ListingIdCounterRecordSortKey      = "#LISTING_ID"
+    ListingIdCounterAttributeName      = "LastListingId"
+    FirstListingId                     = 100001
+
+    OrderRecordPartitionKey = -4
+
+    SearchRecordPartitionKey  = -5
+    SearchCorpusRecordSortKey = "#CORPUS"
+
+    CategoryRecordPartitionKey = -6
+
+    SessionRecordPartitionKey = -7
+
+    ApiKeyRecordPartitionKey = -8
+
+    RateLimitRecordPartitionKey = -9
+
+    IdempotencyRecordPartitionKey     = -10
+    IdempotencyExpiresAtAttributeName = "IdempotencyExpiresAt"
+
+    DefaultCategoryPageSize = 20
+    MaxCategoryPageSize     = 100
+
+    DefaultSearchLimit = 20
+    MaxSearchLimit     = 100
+
+    MaxTopCategoriesLimit = 100
+
+    DynamoDbEndpointEnvKey = "DDB_ENDPOINT"
+
+    StorageBackendEnvKey   = "STORAGE_BACKEND"
+    StorageBackendDynamoDb = "ddb"
+    StorageBackendMemory   = "memory"
+    StorageBackendSqlite   = "sqlite"
+
+    SqlitePathEnvKey = "SQLITE_PATH"
+
+    // AdminUsersEnvKey lists the comma separated usernames that are admins whatever their stored role
+    AdminUsersEnvKey = "ADMIN_USERS"
+
+    // SessionTtlEnvKey is the lifetime of a login session, as a duration such as "24h"
+    SessionTtlEnvKey  = "SESSION_TTL"
+    DefaultSessionTtl = 24 * time.Hour
+
+    // API keys last DefaultApiKeyTtl unless created with another lifetime, of at most MaxApiKeyTtl
+    DefaultApiKeyTtl = 90 * 24 * time.Hour
+    MaxApiKeyTtl     = 365 * 24 * time.Hour
+
+    // The rate limits of each command class, as "<burst>/<period>" such as "60/1m", or "off"
+    RateLimitReadEnvKey  = "RATE_LIMIT_READ"
+    RateLimitWriteEnvKey = "RATE_LIMIT_WRITE"
+    RateLimitAdminEnvKey = "RATE_LIMIT_ADMIN"
+    RateLimitLoginEnvKey = "RATE_LIMIT_LOGIN"
+    DefaultRateLimitRead  = "600/1m"
+    DefaultRateLimitWrite = "120/1m"
+    DefaultRateLimitAdmin = "120/1m"
+    DefaultRateLimitLogin = "10/1m"
+
+    // CommandTimeoutEnvKey is the deadline of every command and request, as a duration such as "10s"
+    CommandTimeoutEnvKey  = "COMMAND_TIMEOUT"
+    DefaultCommandTimeout = 10 * time.Second
+    // CommandTimeoutsEnvKey overrides the deadline of CLI commands, as comma separated pairs such as "SEARCH=30s"
+    CommandTimeoutsEnvKey = "COMMAND_TIMEOUTS"
+
+    // IdempotencyTtlEnvKey is how long the response of a command run with an idempotency key is replayed, as a
+    // duration such as "24h"
+    IdempotencyTtlEnvKey  = "IDEMPOTENCY_TTL"
+    DefaultIdempotencyTtl = 24 * time.Hour
+
+    // ShutdownTimeout bounds the time the servers wait for in-flight requests on shutdown before cancelling them
+    ShutdownTimeout = 30 * time.Second
 
-    ListingIdIndexPartitionKeyName = "ListingIdIndexAttribute"
-    ListingIdIndexPartitionKey     = 1
-    DynamoDbEndpointEnvKey         = "DDB_ENDPOINT"
+    // DynamoDB requests failing with throttling, a transaction conflict or a server error are tried up to
+    // DdbMaxAttempts times, waiting a random delay of up to DdbRetryBaseDelay doubled on every retry and capped at
+    // DdbRetryMaxDelay
+    DdbMaxAttempts    = 5
+    DdbRetryBaseDelay = 50 * time.Millisecond
+    DdbRetryMaxDelay  = 2 * time.Second
 )
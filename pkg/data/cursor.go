@@ -0,0 +1,63 @@
+package data
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+)
+
+// KeysetCursor is the position of the last listing of a category page in stores that paginate by key set: the sort
+// key value and listing ID of that listing. Category is the queried category, which may be an ancestor of the category
+// of the listing.
+type KeysetCursor struct {
+    Category  string      `json:"c"`
+    SortBy    enum.SortBy `json:"s"`
+    SortValue int64       `json:"v"`
+    ListingId int         `json:"id"`
+}
+
+// NewKeysetCursor returns the cursor of a query on category positioned at listing
+func NewKeysetCursor(category string, listing model.Listing, sortBy enum.SortBy) KeysetCursor {
+    sortValue := listing.CreatedAt.Unix()
+    if sortBy == enum.SortByPrice {
+        sortValue = int64(listing.Price)
+    }
+    return KeysetCursor{
+        Category:  category,
+        SortBy:    sortBy,
+        SortValue: sortValue,
+        ListingId: listing.ListingId,
+    }
+}
+
+// EncodeKeysetCursor encodes a cursor as an opaque URL-safe string
+func EncodeKeysetCursor(cursor KeysetCursor) string {
+    encoded, _ := json.Marshal(cursor)
+    return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// DecodeKeysetCursor decodes a cursor produced by EncodeKeysetCursor for the same category and sort key
+// Returns nil for an empty cursor, and exception.InvalidInputException if the cursor is malformed or belongs to
+// another query
+func DecodeKeysetCursor(cursor string, category string, sortBy enum.SortBy) (*KeysetCursor, error) {
+    if cursor == "" {
+        return nil, nil
+    }
+
+    decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+    if err != nil {
+        return nil, exception.NewInvalidInputException("invalid cursor", err)
+    }
+    var keysetCursor KeysetCursor
+    err = json.Unmarshal(decoded, &keysetCursor)
+    if err != nil {
+        return nil, exception.NewInvalidInputException("invalid cursor", err)
+    }
+    if keysetCursor.Category != category || keysetCursor.SortBy != sortBy {
+        return nil, exception.NewInvalidInputException("cursor belongs to another query", nil)
+    }
+
+    return &keysetCursor, nil
+}
@@ -1,8 +1,155 @@
 package data
 
-type DataAccess interface {
-    Save(item interface{}) error
-    Retrieve(id string) (interface{}, error)
-    Update(id string, item interface{}) error
-    Delete(id string) error
+import (
+    "context"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "time"
+)
+
+// MarketplaceStore is the storage backend behind the marketplace commands.
+// The CLI depends only on this interface so that backends can be swapped without touching the command layer.
+// Every method takes the context of the command it serves, and the backends that wait on a database give up with the
+// error of the context once it is cancelled or past its deadline.
+type MarketplaceStore interface {
+    // PutUser registers a new user with the spelling of username, unique by its canonical key, and the hash of its
+    // password. A user registered before passwords existed gets the password hash instead.
+    // Returns nil if a user with the same canonical key already exists with a password
+    PutUser(ctx context.Context, username string, passwordHash string) (*model.User, error)
+
+    // GetUser retrieves a user by the canonical key of username. The user carries the registered spelling.
+    // Returns nil if the user does not exist
+    GetUser(ctx context.Context, username string) (*model.User, error)
+
+    // SetUserRole sets the role of a user by the canonical key of username
+    // Returns nil if the user does not exist
+    SetUserRole(ctx context.Context, username string, role enum.Role) (*model.User, error)
+
+    // PutSession stores a session of a registered user
+    PutSession(ctx context.Context, session model.Session) error
+
+    // GetSession retrieves a session by the hash of its token, expired or not
+    // Returns nil if the session does not exist or was revoked
+    GetSession(ctx context.Context, tokenHash string) (*model.Session, error)
+
+    // DeleteSession revokes a session by the hash of its token. Revoking a missing session succeeds.
+    DeleteSession(ctx context.Context, tokenHash string) error
+
+    // PutApiKey stores an API key of a registered user
+    PutApiKey(ctx context.Context, key model.ApiKey) error
+
+    // GetApiKey retrieves an API key by its ID, expired or not
+    // Returns nil if the key does not exist or was revoked
+    GetApiKey(ctx context.Context, keyId string) (*model.ApiKey, error)
+
+    // GetApiKeys retrieves the API keys of a user, expired or not, oldest first
+    GetApiKeys(ctx context.Context, username string) ([]model.ApiKey, error)
+
+    // DeleteApiKey revokes an API key by its ID. Revoking a missing key succeeds.
+    DeleteApiKey(ctx context.Context, keyId string) error
+
+    // TakeRateLimitToken takes a token from the rate limit bucket under key, refilled as of now, creating a full bucket
+    // if there is none. Buckets are shared by every process using the store.
+    // Returns false if the bucket is empty
+    TakeRateLimitToken(ctx context.Context, key string, limit model.RateLimit, now time.Time) (bool, error)
+
+    // PutIdempotencyRecord stores a record without a response to hold its key while its command runs, unless a record
+    // under the same key has not expired by record.CreatedAt
+    // Returns the record holding the key, or nil if record was stored
+    PutIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+
+    // CompleteIdempotencyRecord stores the response and the new expiry of a record stored by PutIdempotencyRecord. A
+    // record stored without a response keeps holding its key until the new expiry.
+    CompleteIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) error
+
+    // DeleteIdempotencyRecord releases the key of a record whose command failed, so that it can be tried again.
+    // Records with a response are kept, and deleting a missing record succeeds.
+    DeleteIdempotencyRecord(ctx context.Context, key string) error
+
+    // PutListing creates a DRAFT or ACTIVE listing with the next sequential listing ID, incrementing the category count
+    // if it is active, and adds it to the search index
+    // Returns nil if the listing ID is already taken, and the errors of model.CheckCategoryOpen if the category is not
+    // open in the catalog. The listing is put in the category spelled as in the catalog.
+    PutListing(ctx context.Context, username string, title string, description string, price int, category string, status enum.ListingStatus) (*model.Listing, error)
+
+    // GetListing retrieves a listing by listingId
+    // Returns nil if the listing does not exist
+    GetListing(ctx context.Context, listingId int) (*model.Listing, error)
+
+    // GetCategory retrieves a page of the active listings of a category and its subcategories sorted by price or
+    // creation time and filtered
+    // by the bounds of the query, following the store pages until the query limit is reached. A query without a limit
+    // fetches every remaining listing.
+    // Returns exception.InvalidInputException if the cursor is malformed or belongs to another query
+    GetCategory(ctx context.Context, query model.CategoryQuery) (*model.ListingPage, error)
+
+    // GetTopCategory retrieves the direct subcategory of parent, or the top-level category if parent is empty, with the
+    // highest total number of listings. Category counts include the listings of subcategories. Ties are broken as in
+    // GetTopCategories.
+    // Returns nil if there is no such category, as in an empty marketplace. Categories whose listings were all deleted
+    // keep their metric with a count of zero.
+    GetTopCategory(ctx context.Context, parent string) (*model.CategoryMetric, error)
+
+    // GetTopCategories ranks the categories selected by query by descending total number of listings, breaking ties
+    // by descending category name, up to the query limit
+    GetTopCategories(ctx context.Context, query model.TopCategoriesQuery) ([]model.CategoryMetric, error)
+
+    // UpdateListing applies update to a listing owned by username, moving the category count if an active listing
+    // changes category. A new category must be open in the catalog.
+    // Returns exception.ListingDoesNotExistException or exception.OwnershipMismatchException on failed checks, and
+    // exception.StaleListingException if the listing was modified concurrently. Sold and withdrawn listings cannot be
+    // updated.
+    UpdateListing(ctx context.Context, username string, listingId int, update model.ListingUpdate) (*model.Listing, error)
+
+    // DeleteListing deletes a listing owned by username and decrements the category count if it is active
+    // Returns exception.ListingDoesNotExistException or exception.OwnershipMismatchException on failed checks.
+    // Sold listings cannot be deleted.
+    DeleteListing(ctx context.Context, username string, listingId int) error
+
+    // TransitionListing moves a listing to the next status of transition performed by username, adjusting the category
+    // count when the listing becomes or stops being active. Buying is done with BuyListing.
+    // Returns the errors of model.Listing.Transition on failed checks, exception.ListingDoesNotExistException, and
+    // exception.StaleListingException if the listing was modified concurrently
+    TransitionListing(ctx context.Context, username string, listingId int, transition enum.ListingTransition) (*model.Listing, error)
+
+    // BuyListing marks a listing as sold to buyer, records the order and decrements the category count atomically
+    // Returns the errors of model.Listing.Transition on failed checks, exception.ListingDoesNotExistException, and
+    // exception.StaleListingException if the listing was modified concurrently
+    BuyListing(ctx context.Context, buyer string, listingId int) (*model.Order, error)
+
+    // GetOrders retrieves the orders in which username is the buyer or the seller, newest first
+    GetOrders(ctx context.Context, username string) ([]model.Order, error)
+
+    // PutCategory adds an active category to the catalog, unique by its canonical key. Its parent category must be open,
+    // and keeps the spelling of the catalog in the name of the category.
+    // Returns nil if the category already exists
+    PutCategory(ctx context.Context, name string) (*model.Category, error)
+
+    // GetCategories retrieves the catalog sorted by category name
+    GetCategories(ctx context.Context) ([]model.Category, error)
+
+    // LookupCategory retrieves the catalog entry of a category by its canonical key
+    // Returns nil if the category is not in the catalog
+    LookupCategory(ctx context.Context, name string) (*model.Category, error)
+
+    // RetireCategory keeps new listings out of a category and its subcategories. Existing listings are kept.
+    // Returns exception.CategoryDoesNotExistException if the category is not in the catalog
+    RetireCategory(ctx context.Context, name string) error
+
+    // RenameCategory renames a category and its subcategories, moving their listings and counts
+    // Returns the errors of model.CheckCategoryMove on failed checks
+    RenameCategory(ctx context.Context, from string, to string) error
+
+    // MergeCategory moves the listings and counts of a category into another existing category, together with its
+    // subcategories, and removes the category from the catalog
+    // Returns the errors of model.CheckCategoryMove on failed checks
+    MergeCategory(ctx context.Context, from string, to string) error
+
+    // SearchListings retrieves the active listings whose title or description match the query text, ranked by BM25
+    // relevance. UpdateListing and DeleteListing keep the search index up to date.
+    SearchListings(ctx context.Context, query model.SearchQuery) ([]model.Listing, error)
+
+    // RebuildSearchIndex replaces the search index with one built from every listing in the store
+    // Returns the number of listings indexed
+    RebuildSearchIndex(ctx context.Context) (int, error)
 }
@@ -0,0 +1,138 @@
+package ddb
+
+import (
+    "context"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "strconv"
+    "time"
+)
+
+// apiKeyRecord is an API key, keyed by its ID under the API key partition. Like sessions, its attributes are named
+// apart from the listing attributes so that keys stay out of the listing indexes.
+type apiKeyRecord struct {
+    KeyId      string           `dynamodbav:"Username"` // sort key
+    SecretHash string           `dynamodbav:"ApiKeySecretHash"`
+    Username   string           `dynamodbav:"ApiKeyUsername"`
+    Scope      enum.ApiKeyScope `dynamodbav:"ApiKeyScope"`
+    CreatedAt  int64            `dynamodbav:"ApiKeyCreatedAt"`
+    ExpiresAt  int64            `dynamodbav:"ApiKeyExpiresAt"`
+}
+
+func (r apiKeyRecord) toApiKey() model.ApiKey {
+    return model.ApiKey{
+        KeyId:      r.KeyId,
+        SecretHash: r.SecretHash,
+        Username:   r.Username,
+        Scope:      r.Scope,
+        CreatedAt:  time.Unix(r.CreatedAt, 0),
+        ExpiresAt:  time.Unix(r.ExpiresAt, 0),
+    }
+}
+
+// PutApiKey stores an API key
+func (d DynamoDataAccess) PutApiKey(ctx context.Context, key model.ApiKey) error {
+    item, err := attributevalue.MarshalMap(apiKeyRecord{
+        KeyId:      key.KeyId,
+        SecretHash: key.SecretHash,
+        Username:   key.Username,
+        Scope:      key.Scope,
+        CreatedAt:  key.CreatedAt.Unix(),
+        ExpiresAt:  key.ExpiresAt.Unix(),
+    })
+    if err != nil {
+        return fmt.Errorf("failed to marshal api key: %w", err)
+    }
+    for name, value := range buildApiKeyKey(key.KeyId) {
+        item[name] = value
+    }
+
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+        Item:      item,
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to put api key: %v", err)
+        return err
+    }
+
+    return nil
+}
+
+// GetApiKey retrieves an API key by its ID
+// Returns nil if the key does not exist
+func (d DynamoDataAccess) GetApiKey(ctx context.Context, keyId string) (*model.ApiKey, error) {
+    output, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+        Key:       buildApiKeyKey(keyId),
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to get api key: %v", err)
+        return nil, err
+    }
+
+    if output.Item == nil {
+        return nil, nil
+    }
+
+    var record apiKeyRecord
+    err = attributevalue.UnmarshalMap(output.Item, &record)
+    if err != nil {
+        d.log.Errorf("failed to unmarshal api key: %v", err)
+        return nil, err
+    }
+
+    key := record.toApiKey()
+    return &key, nil
+}
+
+// GetApiKeys retrieves the API keys of a user, oldest first. Users hold a handful of keys, so the partition is read
+// whole and filtered.
+func (d DynamoDataAccess) GetApiKeys(ctx context.Context, username string) ([]model.ApiKey, error) {
+    keys := []model.ApiKey{}
+    err := d.queryPartition(ctx, constant.ApiKeyRecordPartitionKey, func(item map[string]types.AttributeValue) error {
+        var record apiKeyRecord
+        err := attributevalue.UnmarshalMap(item, &record)
+        if err != nil {
+            return err
+        }
+        if model.CanonicalKey(record.Username) == model.CanonicalKey(username) {
+            keys = append(keys, record.toApiKey())
+        }
+        return nil
+    })
+    if err != nil {
+        d.log.Errorf("failed to query api keys: %v", err)
+        return nil, err
+    }
+    model.SortApiKeys(keys)
+
+    return keys, nil
+}
+
+// DeleteApiKey revokes an API key by its ID
+func (d DynamoDataAccess) DeleteApiKey(ctx context.Context, keyId string) error {
+    _, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        Key:       buildApiKeyKey(keyId),
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to delete api key: %v", err)
+        return err
+    }
+
+    return nil
+}
+
+func buildApiKeyKey(keyId string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.ApiKeyRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: keyId},
+    }
+}
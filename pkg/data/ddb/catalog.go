@@ -0,0 +1,624 @@
+package ddb
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "marketplace-platform/pkg/util"
+    "sort"
+    "strconv"
+)
+
+// categoryRecord is the catalog entry of a category, keyed by the canonical category name under the catalog partition.
+// Entries written before keys were canonical have no CategoryName and are keyed by the name.
+type categoryRecord struct {
+    Key    string              `dynamodbav:"Username"` // sort key
+    Name   string              `dynamodbav:"CategoryName"`
+    Status enum.CategoryStatus `dynamodbav:"CategoryStatus"`
+}
+
+// maxMoveListingTries bounds the retries of moving a listing that is modified concurrently
+const maxMoveListingTries = 3
+
+// PutCategory adds an active category to the catalog, conditional on its parent being active
+// Returns nil if the category already exists
+func (d DynamoDataAccess) PutCategory(ctx context.Context, name string) (*model.Category, error) {
+    category, err := model.NewCategory(name)
+    if err != nil {
+        return nil, err
+    }
+
+    category.Name, err = model.NewCategoryPath(name, d.lookupCategory(ctx))
+    if err != nil {
+        return nil, err
+    }
+    parent := model.ParentCategory(category.Name)
+
+    item, err := buildCategoryItem(category)
+    if err != nil {
+        return nil, err
+    }
+    putExpr, err := expression.NewBuilder().WithCondition(
+        expression.Name(constant.ListingTablePartitionKeyName).AttributeNotExists()).Build()
+    if err != nil {
+        return nil, err
+    }
+    transactItems := []types.TransactWriteItem{
+        {
+            Put: &types.Put{
+                Item:                      item,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putExpr.Names(),
+                ExpressionAttributeValues: putExpr.Values(),
+                ConditionExpression:       putExpr.Condition(),
+            },
+        },
+    }
+    if parent != "" {
+        openItems, err := buildCategoryOpenItems(parent)
+        if err != nil {
+            return nil, err
+        }
+        transactItems = append(transactItems, openItems...)
+    }
+
+    _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+    if err != nil {
+        var txCanceledErr *types.TransactionCanceledException
+        if errors.As(err, &txCanceledErr) {
+            for idx, reason := range txCanceledErr.CancellationReasons {
+                if *reason.Code != "None" {
+                    d.log.Errorf("Transaction cancelled at index %d with reason: %v", idx, reason)
+                }
+                if idx == 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return nil, nil
+                }
+                if idx > 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return nil, d.categoryConflictError(ctx, parent, err)
+                }
+            }
+        }
+        d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
+        return nil, err
+    }
+
+    return &category, nil
+}
+
+// GetCategories retrieves the catalog sorted by category name
+func (d DynamoDataAccess) GetCategories(ctx context.Context) ([]model.Category, error) {
+    categories := []model.Category{}
+    err := d.queryPartition(ctx, constant.CategoryRecordPartitionKey, func(item map[string]types.AttributeValue) error {
+        var record categoryRecord
+        err := attributevalue.UnmarshalMap(item, &record)
+        if err != nil {
+            return err
+        }
+        categories = append(categories, model.Category{Name: record.Name, Status: record.Status})
+        return nil
+    })
+    sort.Slice(categories, func(i, j int) bool {
+        return categories[i].Name < categories[j].Name
+    })
+    if err != nil {
+        d.log.Errorf("failed to query categories: %v", err)
+        return nil, err
+    }
+
+    return categories, nil
+}
+
+// RetireCategory marks a category as retired, conditional on it being in the catalog
+func (d DynamoDataAccess) RetireCategory(ctx context.Context, name string) error {
+    err := d.setCategoryStatus(ctx, name, enum.CategoryStatusRetired)
+    var conditionCheckFailedErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionCheckFailedErr) {
+        return exception.NewCategoryDoesNotExistException(fmt.Sprintf("category '%s' does not exist", name), err)
+    }
+    return err
+}
+
+// RenameCategory renames a category and its subcategories, moving their listings and counts
+func (d DynamoDataAccess) RenameCategory(ctx context.Context, from string, to string) error {
+    return d.moveCategory(ctx, from, to, false)
+}
+
+// MergeCategory moves the subtree of a category into another category
+func (d DynamoDataAccess) MergeCategory(ctx context.Context, from string, to string) error {
+    return d.moveCategory(ctx, from, to, true)
+}
+
+// moveCategory moves the catalog entries, listings and counts of the subtree of from to to. Entries that already
+// exist under to keep their status.
+// The subtree is retired first so that no listing enters it meanwhile. Each listing then moves together with its
+// counts in a transaction of its own, as a subtree can hold more listings than a transaction. If the move fails
+// halfway, merging from into to again completes it.
+func (d DynamoDataAccess) moveCategory(ctx context.Context, from string, to string, merge bool) error {
+    entry, err := model.RequireCategory(from, d.lookupCategory(ctx))
+    if err != nil {
+        return err
+    }
+    from = entry.Name
+
+    subtree, err := d.getCategorySubtree(ctx, from)
+    if err != nil {
+        return err
+    }
+    to, err = model.CheckCategoryMove(from, to, merge, subtree, d.lookupCategory(ctx))
+    if err != nil {
+        return err
+    }
+
+    for _, category := range subtree {
+        err = d.setCategoryStatus(ctx, category.Name, enum.CategoryStatusRetired)
+        if err != nil {
+            return err
+        }
+    }
+    for _, category := range subtree {
+        category.Name = model.MoveCategoryPath(category.Name, from, to)
+        err = d.putCategoryIfNotExists(ctx, category)
+        if err != nil {
+            return err
+        }
+    }
+
+    for _, category := range subtree {
+        moved := model.MoveCategoryPath(category.Name, from, to)
+        // the category index is eventually consistent, so the category is read again until no listing is left to move
+        for {
+            count, err := d.moveCategoryListings(ctx, category.Name, moved)
+            if err != nil {
+                return err
+            }
+            if count == 0 {
+                break
+            }
+            d.log.Infof("Moved %d listings from category '%s' to '%s'", count, category.Name, moved)
+        }
+    }
+
+    for _, category := range subtree {
+        _, err = d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+            Key:       buildCategoryKey(category.Name),
+            TableName: aws.String(constant.TableName),
+        })
+        if err != nil {
+            return err
+        }
+        err = d.deleteCategoryMetric(ctx, category.Name)
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// moveCategoryListings moves the listings found on the category index under one category to another category
+// Returns the number of listings moved
+func (d DynamoDataAccess) moveCategoryListings(ctx context.Context, from string, to string) (int, error) {
+    expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("Category").Equal(expression.Value(from))).Build()
+    if err != nil {
+        return 0, err
+    }
+
+    var listings []model.Listing
+    paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+        TableName:                 aws.String(constant.TableName),
+        IndexName:                 aws.String(constant.CategoryCreatedAtIndex),
+        KeyConditionExpression:    expr.KeyCondition(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            return 0, err
+        }
+        var page []model.Listing
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &page)
+        if err != nil {
+            return 0, err
+        }
+        listings = append(listings, page...)
+    }
+
+    count := 0
+    for _, listing := range listings {
+        moved, err := d.moveListing(ctx, listing, from, to)
+        if err != nil {
+            return count, err
+        }
+        if moved {
+            count++
+        }
+    }
+    return count, nil
+}
+
+// moveListing moves a listing from one category to another, together with its category count if it is active,
+// conditional on the version that was read. A listing modified concurrently is read again, and skipped if it is gone
+// or no longer in the category.
+// Returns whether the listing was moved
+func (d DynamoDataAccess) moveListing(ctx context.Context, listing model.Listing, from string, to string) (bool, error) {
+    for try := 1; ; try++ {
+        moved := listing
+        moved.Category = to
+        moved.Version++
+        av, err := moved.DdbMarshalMap()
+        if err != nil {
+            d.log.Errorf("failed to marshal Listing struct %s to attribute value map: %v", util.AnyToJsonString(moved), err)
+            return false, err
+        }
+        putListingExpr, err := expression.NewBuilder().WithCondition(buildVersionCondition(listing)).Build()
+        if err != nil {
+            return false, err
+        }
+
+        transactItems := []types.TransactWriteItem{
+            {
+                Put: &types.Put{
+                    Item:                      av,
+                    TableName:                 aws.String(constant.TableName),
+                    ExpressionAttributeNames:  putListingExpr.Names(),
+                    ExpressionAttributeValues: putListingExpr.Values(),
+                    ConditionExpression:       putListingExpr.Condition(),
+                },
+            },
+        }
+        if listing.IsActive() {
+            moveItems, err := buildCategoryCountMoveItems(from, to)
+            if err != nil {
+                return false, err
+            }
+            transactItems = append(transactItems, moveItems...)
+        }
+
+        _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+        if err == nil {
+            return true, nil
+        }
+        var txCanceledErr *types.TransactionCanceledException
+        if !errors.As(err, &txCanceledErr) || len(txCanceledErr.CancellationReasons) == 0 ||
+            *txCanceledErr.CancellationReasons[0].Code != "ConditionalCheckFailed" {
+            d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
+            return false, err
+        }
+        if try == maxMoveListingTries {
+            return false, exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listing.ListingId), err)
+        }
+
+        current, err := d.GetListing(ctx, listing.ListingId)
+        if err != nil {
+            return false, err
+        }
+        if current == nil || current.Category != from {
+            return false, nil
+        }
+        listing = *current
+    }
+}
+
+// getCategorySubtree retrieves the catalog entries of a category and its subcategories
+func (d DynamoDataAccess) getCategorySubtree(ctx context.Context, category string) ([]model.Category, error) {
+    key := model.CanonicalKey(category)
+    expr, err := expression.NewBuilder().WithKeyCondition(
+        expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(constant.CategoryRecordPartitionKey)).And(
+            expression.Key(constant.ListingTableSortKeyName).BeginsWith(key))).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    var subtree []model.Category
+    paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+        TableName:                 aws.String(constant.TableName),
+        KeyConditionExpression:    expr.KeyCondition(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        ConsistentRead:            aws.Bool(true),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            return nil, err
+        }
+        var records []categoryRecord
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &records)
+        if err != nil {
+            return nil, err
+        }
+        // the prefix also matches sibling categories that continue the name
+        for _, record := range records {
+            if model.IsInCategory(record.Key, key) {
+                subtree = append(subtree, model.Category{Name: record.Name, Status: record.Status})
+            }
+        }
+    }
+    return subtree, nil
+}
+
+// LookupCategory retrieves the catalog entry of a category by its canonical key with a consistent read
+// Returns nil if the category is not in the catalog
+func (d DynamoDataAccess) LookupCategory(ctx context.Context, name string) (*model.Category, error) {
+    output, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+        Key:            buildCategoryKey(name),
+        TableName:      aws.String(constant.TableName),
+        ConsistentRead: aws.Bool(true),
+    })
+    if err != nil {
+        d.log.Errorf("failed to get category '%s': %v", name, err)
+        return nil, err
+    }
+    if output.Item == nil {
+        return nil, nil
+    }
+
+    var record categoryRecord
+    err = attributevalue.UnmarshalMap(output.Item, &record)
+    if err != nil {
+        return nil, err
+    }
+    return &model.Category{Name: record.Name, Status: record.Status}, nil
+}
+
+// lookupCategory retrieves catalog entries with LookupCategory within the context of a command
+func (d DynamoDataAccess) lookupCategory(ctx context.Context) model.CategoryLookup {
+    return func(name string) (*model.Category, error) {
+        return d.LookupCategory(ctx, name)
+    }
+}
+
+// setCategoryStatus updates the status of a catalog entry, conditional on it existing
+func (d DynamoDataAccess) setCategoryStatus(ctx context.Context, name string, status enum.CategoryStatus) error {
+    expr, err := expression.NewBuilder().
+        WithUpdate(expression.Set(expression.Name("CategoryStatus"), expression.Value(status))).
+        WithCondition(expression.Name(constant.ListingTablePartitionKeyName).AttributeExists()).
+        Build()
+    if err != nil {
+        return err
+    }
+
+    _, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+        Key:                       buildCategoryKey(name),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        UpdateExpression:          expr.Update(),
+        ConditionExpression:       expr.Condition(),
+        TableName:                 aws.String(constant.TableName),
+    })
+    return err
+}
+
+// putCategoryIfNotExists writes a catalog entry unless the category is already in the catalog
+func (d DynamoDataAccess) putCategoryIfNotExists(ctx context.Context, category model.Category) error {
+    item, err := buildCategoryItem(category)
+    if err != nil {
+        return err
+    }
+    expr, err := expression.NewBuilder().WithCondition(
+        expression.Name(constant.ListingTablePartitionKeyName).AttributeNotExists()).Build()
+    if err != nil {
+        return err
+    }
+
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+        Item:                      item,
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        ConditionExpression:       expr.Condition(),
+        TableName:                 aws.String(constant.TableName),
+    })
+    var conditionCheckFailedErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionCheckFailedErr) {
+        return nil
+    }
+    return err
+}
+
+// deleteCategoryMetric removes the category metric of a category left without listings
+func (d DynamoDataAccess) deleteCategoryMetric(ctx context.Context, name string) error {
+    expr, err := expression.NewBuilder().WithCondition(
+        expression.Name(constant.ListingTablePartitionKeyName).AttributeNotExists().Or(
+            expression.Name(constant.CategoryCountAttributeName).Equal(expression.Value(0)))).Build()
+    if err != nil {
+        return err
+    }
+    _, err = d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        Key:                       buildCategoryMetricKey(name),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        ConditionExpression:       expr.Condition(),
+        TableName:                 aws.String(constant.TableName),
+    })
+    var conditionCheckFailedErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionCheckFailedErr) {
+        d.log.Warnf("category metric of '%s' is not empty and is kept", name)
+        return nil
+    }
+    return err
+}
+
+// MigrateCategoryCatalog brings the catalog of a table written by an earlier version up to date. A table written
+// before the catalog existed gets the categories that have a category metric, and their ancestors. Entries keyed by
+// name are keyed by their canonical name instead, and categories spelled with differing cases are merged under the
+// spelling chosen by model.CategorySpellings, moving their listings and counts. Each merged entry keeps the status of
+// its first spelling in byte order.
+// Returns the number of catalog entries written
+func (d DynamoDataAccess) MigrateCategoryCatalog(ctx context.Context) (int, error) {
+    var legacy []categoryRecord
+    current := make(map[string]model.Category)
+    err := d.queryPartition(ctx, constant.CategoryRecordPartitionKey, func(item map[string]types.AttributeValue) error {
+        var record categoryRecord
+        err := attributevalue.UnmarshalMap(item, &record)
+        if err != nil {
+            return err
+        }
+        if record.Name == "" {
+            legacy = append(legacy, record)
+        } else {
+            current[record.Key] = model.Category{Name: record.Name, Status: record.Status}
+        }
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+
+    var names []string
+    respelled := false
+    err = d.queryPartition(ctx, constant.CategoryMetricRecordPartitionKey, func(item map[string]types.AttributeValue) error {
+        var metric categoryMetricRecord
+        err := attributevalue.UnmarshalMap(item, &metric)
+        if err != nil {
+            return err
+        }
+        names = append(names, metric.Category)
+        if entry, exists := current[model.CanonicalKey(metric.Category)]; !exists || entry.Name != metric.Category {
+            respelled = true
+        }
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    if len(legacy) == 0 && !respelled {
+        return 0, nil
+    }
+
+    // entries sort by name, so that the first spelling of each key gives its status
+    sort.Slice(legacy, func(i, j int) bool {
+        return legacy[i].Key < legacy[j].Key
+    })
+    for _, record := range legacy {
+        names = append(names, record.Key)
+    }
+    for _, entry := range current {
+        names = append(names, entry.Name)
+    }
+    spellings := model.CategorySpellings(names)
+
+    merged := make(map[string]model.Category)
+    for _, entry := range current {
+        merged[entry.Key()] = model.Category{Name: spellings[entry.Name], Status: entry.Status}
+    }
+    for _, record := range legacy {
+        key := model.CanonicalKey(record.Key)
+        if _, exists := merged[key]; !exists {
+            merged[key] = model.Category{Name: spellings[record.Key], Status: record.Status}
+        }
+    }
+    for _, spelling := range spellings {
+        key := model.CanonicalKey(spelling)
+        if _, exists := merged[key]; !exists {
+            merged[key] = model.Category{Name: spelling, Status: enum.CategoryStatusActive}
+        }
+    }
+
+    var puts, deletes []types.WriteRequest
+    for _, category := range merged {
+        item, err := buildCategoryItem(category)
+        if err != nil {
+            return 0, err
+        }
+        puts = append(puts, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+    }
+    for _, record := range legacy {
+        if record.Key != model.CanonicalKey(record.Key) {
+            deletes = append(deletes, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{
+                constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.CategoryRecordPartitionKey)},
+                constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: record.Key},
+            }}})
+        }
+    }
+    // the entries keyed by name are deleted once their canonical entries are written
+    err = d.batchWrite(ctx, puts)
+    if err != nil {
+        return 0, err
+    }
+    err = d.batchWrite(ctx, deletes)
+    if err != nil {
+        return 0, err
+    }
+
+    for category, spelling := range spellings {
+        if category == spelling {
+            continue
+        }
+        for {
+            count, err := d.moveCategoryListings(ctx, category, spelling)
+            if err != nil {
+                return 0, err
+            }
+            if count == 0 {
+                break
+            }
+            d.log.Infof("Moved %d listings from category '%s' to '%s'", count, category, spelling)
+        }
+        err = d.deleteCategoryMetric(ctx, category)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    return len(puts), nil
+}
+
+// categoryConflictError re-reads the catalog after a write failed the checks of buildCategoryOpenItems to report why
+func (d DynamoDataAccess) categoryConflictError(ctx context.Context, category string, cause error) error {
+    _, err := model.CheckCategoryOpen(category, d.lookupCategory(ctx))
+    if err != nil {
+        return err
+    }
+    return cause
+}
+
+// buildCategoryOpenItems requires a category and its ancestors to be active in the catalog
+func buildCategoryOpenItems(category string) ([]types.TransactWriteItem, error) {
+    expr, err := expression.NewBuilder().WithCondition(
+        expression.Name("CategoryStatus").Equal(expression.Value(enum.CategoryStatusActive))).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    var items []types.TransactWriteItem
+    for _, ancestor := range model.CategoryAncestors(category) {
+        items = append(items, types.TransactWriteItem{
+            ConditionCheck: &types.ConditionCheck{
+                Key:                       buildCategoryKey(ancestor),
+                ExpressionAttributeNames:  expr.Names(),
+                ExpressionAttributeValues: expr.Values(),
+                ConditionExpression:       expr.Condition(),
+                TableName:                 aws.String(constant.TableName),
+            },
+        })
+    }
+    return items, nil
+}
+
+func buildCategoryItem(category model.Category) (map[string]types.AttributeValue, error) {
+    item, err := attributevalue.MarshalMap(categoryRecord{Key: category.Key(), Name: category.Name, Status: category.Status})
+    if err != nil {
+        return nil, err
+    }
+    for name, value := range buildCategoryKey(category.Name) {
+        item[name] = value
+    }
+    return item, nil
+}
+
+func buildCategoryKey(name string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.CategoryRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: model.CanonicalKey(name)},
+    }
+}
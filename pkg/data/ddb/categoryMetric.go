@@ -0,0 +1,32 @@
+package ddb
+
+import (
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/data/model"
+)
+
+// categoryMetricRecord is the count of the active listings of a category and its subcategories, keyed by the category
+// name under the category metric partition. The count is the sort key of CategoryCountIndex.
+type categoryMetricRecord struct {
+    Category      string `dynamodbav:"Username"` // sort key
+    CategoryCount int    `dynamodbav:"CategoryCount"`
+}
+
+// unmarshalCategoryMetrics unmarshals category metric records to the category metrics of the model
+func unmarshalCategoryMetrics(items []map[string]types.AttributeValue) ([]model.CategoryMetric, error) {
+    var records []categoryMetricRecord
+    err := attributevalue.UnmarshalListOfMaps(items, &records)
+    if err != nil {
+        return nil, err
+    }
+
+    metrics := make([]model.CategoryMetric, 0, len(records))
+    for _, record := range records {
+        metrics = append(metrics, model.CategoryMetric{
+            Category:      record.Category,
+            CategoryCount: record.CategoryCount,
+        })
+    }
+    return metrics, nil
+}
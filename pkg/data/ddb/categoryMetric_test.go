@@ -0,0 +1,35 @@
+package ddb
+
+import (
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "reflect"
+    "strconv"
+    "testing"
+)
+
+func TestUnmarshalCategoryMetrics(t *testing.T) {
+    metrics, err := unmarshalCategoryMetrics(nil)
+    if err != nil || len(metrics) != 0 {
+        t.Fatalf("expected no category metric from no items, got %v, %v", metrics, err)
+    }
+
+    items := []map[string]types.AttributeValue{{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.CategoryMetricRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: "Fashion/Shoes"},
+        constant.CategoryCountAttributeName:   &types.AttributeValueMemberN{Value: "3"},
+    }, {
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.CategoryMetricRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: "Sports"},
+        constant.CategoryCountAttributeName:   &types.AttributeValueMemberN{Value: "0"},
+    }}
+    metrics, err = unmarshalCategoryMetrics(items)
+    if err != nil {
+        t.Fatalf("could not unmarshal category metrics: %v", err)
+    }
+    expected := []model.CategoryMetric{{Category: "Fashion/Shoes", CategoryCount: 3}, {Category: "Sports", CategoryCount: 0}}
+    if !reflect.DeepEqual(metrics, expected) {
+        t.Fatalf("expected category metrics %v, got %v", expected, metrics)
+    }
+}
@@ -0,0 +1,137 @@
+package ddb
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/exception"
+    "strconv"
+)
+
+// indexSortKeyNames maps the category indexes to their sort key attribute
+var indexSortKeyNames = map[string]string{
+    constant.CategoryPriceIndex:     "Price",
+    constant.CategoryCreatedAtIndex: "CreatedAt",
+}
+
+// cursorAttribute is the JSON form of a key attribute of a LastEvaluatedKey. Key attributes are strings or numbers.
+type cursorAttribute struct {
+    S *string `json:"S,omitempty"`
+    N *string `json:"N,omitempty"`
+}
+
+// encodeCursor encodes the start keys of the next queries of the categories of a page as an opaque URL-safe string
+// A nil start key continues from the first listing of the category. Returns an empty string if there are no start keys
+func encodeCursor(startKeys map[string]map[string]types.AttributeValue) (string, error) {
+    if len(startKeys) == 0 {
+        return "", nil
+    }
+
+    categories := make(map[string]map[string]cursorAttribute, len(startKeys))
+    for category, startKey := range startKeys {
+        attributes := make(map[string]cursorAttribute, len(startKey))
+        for name, value := range startKey {
+            switch v := value.(type) {
+            case *types.AttributeValueMemberS:
+                attributes[name] = cursorAttribute{S: &v.Value}
+            case *types.AttributeValueMemberN:
+                attributes[name] = cursorAttribute{N: &v.Value}
+            default:
+                return "", fmt.Errorf("unsupported type of key attribute %s", name)
+            }
+        }
+        categories[category] = attributes
+    }
+
+    encoded, err := json.Marshal(categories)
+    if err != nil {
+        return "", err
+    }
+    return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// decodeCursor decodes a cursor produced by encodeCursor into the ExclusiveStartKey of the query of each category on
+// indexName. The categories must be category or its subcategories, and each start key must hold the table key and
+// the index key of its category, or be empty to start from the first listing.
+// Returns nil for an empty cursor, and exception.InvalidInputException if the cursor is malformed or belongs to
+// another query
+func decodeCursor(cursor string, indexName string, category string) (map[string]map[string]types.AttributeValue, error) {
+    if cursor == "" {
+        return nil, nil
+    }
+
+    decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+    if err != nil {
+        return nil, exception.NewInvalidInputException("invalid cursor", err)
+    }
+    var categories map[string]map[string]cursorAttribute
+    err = json.Unmarshal(decoded, &categories)
+    if err != nil || len(categories) == 0 {
+        return nil, exception.NewInvalidInputException("invalid cursor", err)
+    }
+
+    startKeys := make(map[string]map[string]types.AttributeValue, len(categories))
+    for subcategory, attributes := range categories {
+        if !model.IsInCategory(subcategory, category) {
+            return nil, exception.NewInvalidInputException("cursor belongs to another query", nil)
+        }
+        startKey, err := decodeStartKey(attributes, indexName, subcategory)
+        if err != nil {
+            return nil, err
+        }
+        startKeys[subcategory] = startKey
+    }
+
+    return startKeys, nil
+}
+
+// decodeStartKey decodes the start key of the query of a category, which is nil if attributes are empty
+func decodeStartKey(attributes map[string]cursorAttribute, indexName string, category string) (map[string]types.AttributeValue, error) {
+    if len(attributes) == 0 {
+        return nil, nil
+    }
+
+    startKey := make(map[string]types.AttributeValue, len(attributes))
+    for name, attribute := range attributes {
+        switch {
+        case attribute.S != nil && attribute.N == nil:
+            startKey[name] = &types.AttributeValueMemberS{Value: *attribute.S}
+        case attribute.N != nil && attribute.S == nil:
+            startKey[name] = &types.AttributeValueMemberN{Value: *attribute.N}
+        default:
+            return nil, exception.NewInvalidInputException(fmt.Sprintf("invalid cursor attribute %s", name), nil)
+        }
+    }
+
+    keyNames := []string{"ListingId", "Username", "Category", indexSortKeyNames[indexName]}
+    if len(startKey) != len(keyNames) {
+        return nil, exception.NewInvalidInputException("cursor belongs to another query", nil)
+    }
+    for _, name := range keyNames {
+        if _, ok := startKey[name]; !ok {
+            return nil, exception.NewInvalidInputException("cursor belongs to another query", nil)
+        }
+    }
+    if c, ok := startKey["Category"].(*types.AttributeValueMemberS); !ok || c.Value != category {
+        return nil, exception.NewInvalidInputException("cursor belongs to another query", nil)
+    }
+
+    return startKey, nil
+}
+
+// buildIndexKey builds the key of a listing on a category index, as returned in the LastEvaluatedKey of a query
+func buildIndexKey(listing model.Listing, indexName string) map[string]types.AttributeValue {
+    sortValue := listing.Price
+    if indexName == constant.CategoryCreatedAtIndex {
+        sortValue = int(listing.CreatedAt.Unix())
+    }
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(listing.ListingId)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: listing.Username},
+        "Category":                            &types.AttributeValueMemberS{Value: listing.Category},
+        indexSortKeyNames[indexName]:          &types.AttributeValueMemberN{Value: strconv.Itoa(sortValue)},
+    }
+}
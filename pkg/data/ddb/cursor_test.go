@@ -0,0 +1,49 @@
+package ddb
+
+import (
+    "errors"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/exception"
+    "reflect"
+    "testing"
+    "time"
+)
+
+func TestCursor(t *testing.T) {
+    listing := model.Listing{ListingId: 100003, Username: "user2", Category: "Sports/Running", Price: 2000, CreatedAt: time.Unix(1550838898, 0)}
+    startKeys := map[string]map[string]types.AttributeValue{
+        "Sports":         nil,
+        "Sports/Running": buildIndexKey(listing, constant.CategoryPriceIndex),
+    }
+    cursor, err := encodeCursor(startKeys)
+    if err != nil {
+        t.Fatalf("could not encode cursor: %v", err)
+    }
+
+    decoded, err := decodeCursor(cursor, constant.CategoryPriceIndex, "Sports")
+    if err != nil {
+        t.Fatalf("could not decode cursor: %v", err)
+    }
+    if !reflect.DeepEqual(decoded, startKeys) {
+        t.Fatalf("expected start keys %v, got %v", startKeys, decoded)
+    }
+
+    var invalidInputErr *exception.InvalidInputException
+    for name, decode := range map[string]func() error{
+        "other category":    func() error { _, err := decodeCursor(cursor, constant.CategoryPriceIndex, "Fashion"); return err },
+        "subcategory query": func() error { _, err := decodeCursor(cursor, constant.CategoryPriceIndex, "Sports/Running"); return err },
+        "other index":       func() error { _, err := decodeCursor(cursor, constant.CategoryCreatedAtIndex, "Sports"); return err },
+        "malformed":         func() error { _, err := decodeCursor("xyz", constant.CategoryPriceIndex, "Sports"); return err },
+    } {
+        if err := decode(); !errors.As(err, &invalidInputErr) {
+            t.Fatalf("expected %s cursor to be rejected, got %v", name, err)
+        }
+    }
+
+    cursor, err = encodeCursor(nil)
+    if err != nil || cursor != "" {
+        t.Fatalf("expected empty cursor without start keys, got %q: %v", cursor, err)
+    }
+}
@@ -10,21 +10,27 @@ import (
     "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/aws/smithy-go"
     "go.uber.org/zap"
     "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
     "marketplace-platform/pkg/data/model"
     "marketplace-platform/pkg/data/model/enum"
     "marketplace-platform/pkg/exception"
     "marketplace-platform/pkg/util"
     "os"
+    "sort"
     "strconv"
 )
 
+// DynamoDataAccess is the DynamoDB implementation of data.MarketplaceStore
 type DynamoDataAccess struct {
-    client *dynamodb.Client
+    client dynamoClient
     log    *zap.SugaredLogger
 }
 
+var _ data.MarketplaceStore = DynamoDataAccess{}
+
 func NewDynamoDataAccess(log *zap.SugaredLogger) DynamoDataAccess {
     // try to get the endpoint from the environment variable
     var endpoint string
@@ -35,7 +41,7 @@ func NewDynamoDataAccess(log *zap.SugaredLogger) DynamoDataAccess {
     }
     log.Debug("DynamoDB endpoint: " + endpoint)
 
-    cfg, err := config.LoadDefaultConfig(context.TODO(),
+    cfg, err := config.LoadDefaultConfig(context.Background(),
         config.WithRegion("eu-west-1"),
         config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
             func(service, region string, options ...interface{}) (aws.Endpoint, error) {
@@ -43,6 +49,10 @@ func NewDynamoDataAccess(log *zap.SugaredLogger) DynamoDataAccess {
                     SigningRegion: "eu-west-1",
                 }, nil
             })),
+        // requests are retried by retryClient instead
+        config.WithRetryer(func() aws.Retryer {
+            return aws.NopRetryer{}
+        }),
     )
     if err != nil {
         log.Fatalf("unable to load SDK config, %v", err)
@@ -52,16 +62,17 @@ func NewDynamoDataAccess(log *zap.SugaredLogger) DynamoDataAccess {
     client := dynamodb.NewFromConfig(cfg)
 
     return DynamoDataAccess{
-        client: client,
+        client: newRetryClient(client, log),
         log:    log,
     }
 }
 
-// PutUser a new user
-// Returns nil if the user already exists
-func (d DynamoDataAccess) PutUser(username string) (*model.User, error) {
+// PutUser a new user, or sets the password hash of a user registered without one
+// Returns nil if the user already exists with a password
+func (d DynamoDataAccess) PutUser(ctx context.Context, username string, passwordHash string) (*model.User, error) {
     user := model.User{
-        Username: username,
+        Username:     username,
+        PasswordHash: passwordHash,
     }
 
     // Marshal the User struct to a DynamoDB attribute value map
@@ -86,11 +97,11 @@ func (d DynamoDataAccess) PutUser(username string) (*model.User, error) {
         ConditionExpression:       expr.Condition(),
         TableName:                 aws.String(constant.TableName),
     }
-    _, err = d.client.PutItem(context.TODO(), input)
+    _, err = d.client.PutItem(ctx, input)
     if err != nil {
         var conditionCheckFailedErr *types.ConditionalCheckFailedException
         if errors.As(err, &conditionCheckFailedErr) {
-            return nil, nil
+            return d.setPasswordHash(ctx, user)
         }
         return nil, err
     }
@@ -98,21 +109,53 @@ func (d DynamoDataAccess) PutUser(username string) (*model.User, error) {
     return &user, nil
 }
 
-// GetUser retrieves a user by username
-// Returns nil if the user does not exist
-func (d DynamoDataAccess) GetUser(username string) (*model.User, error) {
-    av, err := model.User{
-        Username: username,
-    }.DdbMarshalMap()
+// setPasswordHash sets the password hash of a user registered before passwords existed
+// Returns nil if the user already has a password
+func (d DynamoDataAccess) setPasswordHash(ctx context.Context, user model.User) (*model.User, error) {
+    expr, err := expression.NewBuilder().WithUpdate(
+        expression.Set(expression.Name(constant.UserPasswordHashAttributeName), expression.Value(user.PasswordHash)),
+    ).WithCondition(
+        expression.Name(constant.ListingTableSortKeyName).AttributeExists().And(
+            expression.Name(constant.UserPasswordHashAttributeName).AttributeNotExists()),
+    ).Build()
     if err != nil {
         return nil, err
     }
 
+    output, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+        Key:                       buildUserKey(user.Key()),
+        TableName:                 aws.String(constant.TableName),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        UpdateExpression:          expr.Update(),
+        ConditionExpression:       expr.Condition(),
+        ReturnValues:              types.ReturnValueAllNew,
+    })
+    if err != nil {
+        var conditionCheckFailedErr *types.ConditionalCheckFailedException
+        if errors.As(err, &conditionCheckFailedErr) {
+            return nil, nil
+        }
+        return nil, err
+    }
+
+    // the user keeps the spelling it was first registered with
+    err = attributevalue.UnmarshalMap(output.Attributes, &user)
+    if err != nil {
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+// GetUser retrieves a user by the canonical key of username
+// Returns nil if the user does not exist
+func (d DynamoDataAccess) GetUser(ctx context.Context, username string) (*model.User, error) {
     input := &dynamodb.GetItemInput{
-        Key:       av,
+        Key:       buildUserKey(model.CanonicalKey(username)),
         TableName: aws.String(constant.TableName),
     }
-    output, err := d.client.GetItem(context.TODO(), input)
+    output, err := d.client.GetItem(ctx, input)
     if err != nil {
         d.log.Errorw("failed to get user", "username", username, "error", err)
         return nil, err
@@ -132,58 +175,250 @@ func (d DynamoDataAccess) GetUser(username string) (*model.User, error) {
     return &user, nil
 }
 
-func (d DynamoDataAccess) getNextListingId() (int, error) {
-    expr, err := expression.NewBuilder().WithKeyCondition(expression.Key(constant.ListingIdIndexPartitionKeyName).Equal(expression.Value(constant.ListingIdIndexPartitionKey))).Build()
+// SetUserRole sets the role of a user by the canonical key of username
+// Returns nil if the user does not exist
+func (d DynamoDataAccess) SetUserRole(ctx context.Context, username string, role enum.Role) (*model.User, error) {
+    expr, err := expression.NewBuilder().WithUpdate(
+        expression.Set(expression.Name(constant.UserRoleAttributeName), expression.Value(role)),
+    ).WithCondition(
+        expression.Name(constant.ListingTableSortKeyName).AttributeExists(),
+    ).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    output, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+        Key:                       buildUserKey(model.CanonicalKey(username)),
+        TableName:                 aws.String(constant.TableName),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        UpdateExpression:          expr.Update(),
+        ConditionExpression:       expr.Condition(),
+        ReturnValues:              types.ReturnValueAllNew,
+    })
+    if err != nil {
+        var conditionCheckFailedErr *types.ConditionalCheckFailedException
+        if errors.As(err, &conditionCheckFailedErr) {
+            return nil, nil
+        }
+        d.log.Errorw("failed to set user role", "username", username, "error", err)
+        return nil, err
+    }
+
+    var user model.User
+    err = attributevalue.UnmarshalMap(output.Attributes, &user)
+    if err != nil {
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+// MigrateUserKeys keys the user records written before usernames were canonical by the canonical key of the
+// username, keeping the username as the display name. Usernames that differ only by case cannot share a key, and one
+// of them has to be renamed before the migration can complete.
+// Returns the number of user records migrated
+func (d DynamoDataAccess) MigrateUserKeys(ctx context.Context) (int, error) {
+    var legacy []string
+    err := d.queryPartition(ctx, constant.UserRootRecordPartitionKey, func(item map[string]types.AttributeValue) error {
+        if _, migrated := item[constant.UserDisplayNameAttributeName]; migrated {
+            return nil
+        }
+        var username string
+        err := attributevalue.Unmarshal(item[constant.ListingTableSortKeyName], &username)
+        if err != nil {
+            return err
+        }
+        legacy = append(legacy, username)
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+
+    for _, username := range legacy {
+        user := model.User{Username: username}
+        if user.Key() == username {
+            _, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+                Key:                       buildUserKey(username),
+                TableName:                 aws.String(constant.TableName),
+                UpdateExpression:          aws.String("SET #name = :name"),
+                ExpressionAttributeNames:  map[string]string{"#name": constant.UserDisplayNameAttributeName},
+                ExpressionAttributeValues: map[string]types.AttributeValue{":name": &types.AttributeValueMemberS{Value: username}},
+            })
+            if err != nil {
+                return 0, err
+            }
+            continue
+        }
+
+        av, err := user.DdbMarshalMap()
+        if err != nil {
+            return 0, err
+        }
+        _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+            TransactItems: []types.TransactWriteItem{
+                {
+                    Put: &types.Put{
+                        Item:                     av,
+                        TableName:                aws.String(constant.TableName),
+                        ConditionExpression:      aws.String("attribute_not_exists(#sk)"),
+                        ExpressionAttributeNames: map[string]string{"#sk": constant.ListingTableSortKeyName},
+                    },
+                },
+                {
+                    Delete: &types.Delete{
+                        Key:       buildUserKey(username),
+                        TableName: aws.String(constant.TableName),
+                    },
+                },
+            },
+        })
+        if err != nil {
+            var canceledErr *types.TransactionCanceledException
+            if !errors.As(err, &canceledErr) {
+                return 0, err
+            }
+            existing, getErr := d.GetUser(ctx, username)
+            if getErr != nil {
+                return 0, getErr
+            }
+            if existing == nil {
+                return 0, err
+            }
+            return 0, fmt.Errorf("usernames '%s' and '%s' differ only by case, rename one of them before upgrading", existing.Username, username)
+        }
+    }
+
+    return len(legacy), nil
+}
+
+// getNextListingId atomically increments the listing ID counter record and returns the new value.
+// Concurrent callers always receive distinct, sequential IDs.
+func (d DynamoDataAccess) getNextListingId(ctx context.Context) (int, error) {
+    counter := expression.Name(constant.ListingIdCounterAttributeName)
+    expr, err := expression.NewBuilder().WithUpdate(expression.Set(
+        counter,
+        expression.Plus(expression.IfNotExists(counter, expression.Value(constant.FirstListingId-1)), expression.Value(1)),
+    )).Build()
     if err != nil {
         return -1, err
     }
 
-    input := &dynamodb.QueryInput{
-        KeyConditionExpression:    expr.KeyCondition(),
-        ProjectionExpression:      expr.Projection(),
+    input := &dynamodb.UpdateItemInput{
+        Key:                       buildListingIdCounterKey(),
         ExpressionAttributeNames:  expr.Names(),
         ExpressionAttributeValues: expr.Values(),
-        ScanIndexForward:          aws.Bool(false),
-        Limit:                     aws.Int32(1),
+        UpdateExpression:          expr.Update(),
+        ReturnValues:              types.ReturnValueUpdatedNew,
         TableName:                 aws.String(constant.TableName),
-        IndexName:                 aws.String(constant.ListingIdIndex),
     }
-    output, err := d.client.Query(context.TODO(), input)
+    output, err := d.client.UpdateItem(ctx, input)
     if err != nil {
         return -1, err
     }
 
-    // unmarshall the listing id if exists, otherwise return 100001
-    if output.Count == 0 {
-        return 100001, nil
+    var listingIdCounter struct {
+        LastListingId int `dynamodbav:"LastListingId"`
+    }
+    err = attributevalue.UnmarshalMap(output.Attributes, &listingIdCounter)
+    if err != nil {
+        return -1, err
     }
 
-    var listing model.Listing
-    err = attributevalue.UnmarshalMap(output.Items[0], &listing)
+    return listingIdCounter.LastListingId, nil
+}
+
+// SeedListingIdCounter creates the listing ID counter record of a table written before listing IDs came from it, at the
+// highest listing ID in the table, so that new listings do not collide with existing ones. A counter that exists
+// already is kept.
+// Returns the highest listing ID if the counter was created, or 0 otherwise
+func (d DynamoDataAccess) SeedListingIdCounter(ctx context.Context) (int, error) {
+    expr, err := expression.NewBuilder().
+        WithFilter(expression.Name(constant.ListingTablePartitionKeyName).GreaterThanEqual(expression.Value(constant.FirstListingId))).
+        WithProjection(expression.NamesList(expression.Name(constant.ListingTablePartitionKeyName))).
+        Build()
+    if err != nil {
+        return 0, err
+    }
+
+    lastListingId := 0
+    paginator := dynamodb.NewScanPaginator(d.client, &dynamodb.ScanInput{
+        TableName:                 aws.String(constant.TableName),
+        FilterExpression:          expr.Filter(),
+        ProjectionExpression:      expr.Projection(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            d.log.Errorf("failed to scan listing IDs: %v", err)
+            return 0, err
+        }
+        var listingIds []struct {
+            ListingId int `dynamodbav:"ListingId"`
+        }
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &listingIds)
+        if err != nil {
+            return 0, err
+        }
+        for _, listingId := range listingIds {
+            if listingId.ListingId > lastListingId {
+                lastListingId = listingId.ListingId
+            }
+        }
+    }
+    if lastListingId == 0 {
+        return 0, nil
+    }
+
+    item := buildListingIdCounterKey()
+    item[constant.ListingIdCounterAttributeName] = &types.AttributeValueMemberN{Value: strconv.Itoa(lastListingId)}
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+        Item:                     item,
+        TableName:                aws.String(constant.TableName),
+        ConditionExpression:      aws.String("attribute_not_exists(#pk)"),
+        ExpressionAttributeNames: map[string]string{"#pk": constant.ListingTablePartitionKeyName},
+    })
+    var conditionCheckFailedErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionCheckFailedErr) {
+        return 0, nil
+    }
     if err != nil {
+        d.log.Errorf("failed to seed listing ID counter: %v", err)
         return 0, err
     }
 
-    return listing.ListingId + 1, nil
+    return lastListingId, nil
 }
 
-// PutListing puts a listing item to the database
+// PutListing puts a listing item to the database and increments the category count if it is active.
+// The listing is added to the search index once it is written.
 func (d DynamoDataAccess) PutListing(
+    ctx context.Context,
     username string,
     title string,
     description string,
     price int,
     category string,
+    status enum.ListingStatus,
 ) (*model.Listing, error) {
-    listingId, err := d.getNextListingId()
+    // validate before allocating an ID so that rejected input does not consume one
+    listing, err := model.NewListing(constant.FirstListingId, username, title, description, price, category, status)
+    if err != nil {
+        d.log.Error("failed to create new listing: ", err)
+        return nil, err
+    }
+    entry, err := model.CheckCategoryOpen(category, d.lookupCategory(ctx))
     if err != nil {
-        d.log.Error("failed to get next listing id: ", err)
         return nil, err
     }
+    listing.Category = entry.Name
 
-    listing, err := model.NewListing(listingId, username, title, description, price, category)
+    listing.ListingId, err = d.getNextListingId(ctx)
     if err != nil {
-        d.log.Error("failed to create new listing: ", err)
+        d.log.Error("failed to get next listing id: ", err)
         return nil, err
     }
 
@@ -199,33 +434,32 @@ func (d DynamoDataAccess) PutListing(
     if err != nil {
         return nil, err
     }
-    incrementCategoryCountExpr, err := expression.NewBuilder().WithUpdate(expression.Add(expression.Name("CategoryCount"), expression.Value(1))).Build()
-    if err != nil {
-        return nil, err
-    }
-    input := &dynamodb.TransactWriteItemsInput{
-        TransactItems: []types.TransactWriteItem{
-            {
-                Put: &types.Put{
-                    Item:                      av,
-                    TableName:                 aws.String(constant.TableName),
-                    ExpressionAttributeNames:  putListingExpr.Names(),
-                    ExpressionAttributeValues: putListingExpr.Values(),
-                    ConditionExpression:       putListingExpr.Condition(),
-                },
-            },
-            {
-                Update: &types.Update{
-                    Key:                       buildCategoryMetricKey(category),
-                    ExpressionAttributeNames:  incrementCategoryCountExpr.Names(),
-                    ExpressionAttributeValues: incrementCategoryCountExpr.Values(),
-                    UpdateExpression:          incrementCategoryCountExpr.Update(),
-                    TableName:                 aws.String(constant.TableName),
-                },
+    transactItems := []types.TransactWriteItem{
+        {
+            Put: &types.Put{
+                Item:                      av,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putListingExpr.Names(),
+                ExpressionAttributeValues: putListingExpr.Values(),
+                ConditionExpression:       putListingExpr.Condition(),
             },
         },
     }
-    _, err = d.client.TransactWriteItems(context.TODO(), input)
+    if listing.IsActive() {
+        incrementItems, err := buildCategoryCountItems(listing.Category, 1)
+        if err != nil {
+            return nil, err
+        }
+        transactItems = append(transactItems, incrementItems...)
+    }
+    // the category may be retired or merged away since it was checked
+    openItems, err := buildCategoryOpenItems(listing.Category)
+    if err != nil {
+        return nil, err
+    }
+    transactItems = append(transactItems, openItems...)
+
+    _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
     if err != nil {
         var txCanceledErr *types.TransactionCanceledException
         if errors.As(err, &txCanceledErr) {
@@ -236,17 +470,22 @@ func (d DynamoDataAccess) PutListing(
                 if idx == 0 && *reason.Code == "ConditionalCheckFailed" {
                     return nil, nil
                 }
+                // only the checks of the catalog have conditions besides the listing
+                if idx > 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return nil, d.categoryConflictError(ctx, listing.Category, err)
+                }
             }
         }
         d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
         return nil, err
     }
+    d.updateSearchIndex(ctx, nil, &listing)
 
     return &listing, nil
 }
 
 // GetListing retrieves a listing by listingId
-func (d DynamoDataAccess) GetListing(listingId int) (*model.Listing, error) {
+func (d DynamoDataAccess) GetListing(ctx context.Context, listingId int) (*model.Listing, error) {
     expr, err := expression.NewBuilder().WithKeyCondition(expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(listingId))).Build()
     if err != nil {
         return nil, err
@@ -260,7 +499,7 @@ func (d DynamoDataAccess) GetListing(listingId int) (*model.Listing, error) {
         TableName:                 aws.String(constant.TableName),
     }
 
-    output, err := d.client.Query(context.TODO(), input)
+    output, err := d.client.Query(ctx, input)
     if err != nil {
         d.log.Errorf("failed to query listing with listingId %s: %v", listingId, err)
         return nil, err
@@ -283,51 +522,222 @@ func (d DynamoDataAccess) GetListing(listingId int) (*model.Listing, error) {
     return &listing, nil
 }
 
-// GetCategory retrieves all listings of a specified category and sorts them by price or creation time
-func (d DynamoDataAccess) GetCategory(category string, sortBy enum.SortBy, order enum.OrderBy) ([]model.Listing, error) {
+// GetCategory retrieves a page of the active listings of a category and its subcategories sorted by price or creation
+// time. Each category with active listings is queried on its own index partition, and the pages of the categories are
+// merged in the order of the sort key. The cursor holds the position in every category that may have more listings.
+func (d DynamoDataAccess) GetCategory(ctx context.Context, query model.CategoryQuery) (*model.ListingPage, error) {
     indexName := constant.CategoryPriceIndex
-    if sortBy == enum.SortByCreatedAt {
+    if query.SortBy == enum.SortByCreatedAt {
         indexName = constant.CategoryCreatedAtIndex
     }
 
+    startKeys, err := decodeCursor(query.Cursor, indexName, query.Category)
+    if err != nil {
+        return nil, err
+    }
+    if startKeys == nil {
+        categories, err := d.getCategoryTree(ctx, query.Category)
+        if err != nil {
+            d.log.Errorf("failed to get subcategories of %s: %v", query.Category, err)
+            return nil, err
+        }
+        startKeys = make(map[string]map[string]types.AttributeValue, len(categories))
+        for _, category := range categories {
+            startKeys[category] = nil
+        }
+    }
+
+    var streams []*categoryStream
+    for category, startKey := range startKeys {
+        stream, err := d.queryCategory(ctx, query, category, indexName, startKey)
+        if err != nil {
+            return nil, err
+        }
+        streams = append(streams, stream)
+    }
+    sort.Slice(streams, func(i, j int) bool {
+        return streams[i].category < streams[j].category
+    })
+
+    page := &model.ListingPage{Listings: mergeCategoryStreams(streams, query)}
+
+    nextStartKeys := make(map[string]map[string]types.AttributeValue)
+    for _, stream := range streams {
+        switch {
+        case stream.merged == len(stream.listings) && len(stream.lastKey) == 0:
+            // the category is exhausted
+        case stream.merged == len(stream.listings):
+            nextStartKeys[stream.category] = stream.lastKey
+        case stream.merged > 0:
+            nextStartKeys[stream.category] = buildIndexKey(stream.listings[stream.merged-1], indexName)
+        default:
+            nextStartKeys[stream.category] = stream.startKey
+        }
+    }
+    page.Cursor, err = encodeCursor(nextStartKeys)
+    if err != nil {
+        d.log.Errorf("failed to encode cursor: %v", err)
+        return nil, err
+    }
+
+    return page, nil
+}
+
+// categoryStream holds the listings read from the index partition of a single category
+type categoryStream struct {
+    category string
+    listings []model.Listing
+    // merged is the number of listings merged into the page
+    merged   int
+    startKey map[string]types.AttributeValue
+    // lastKey is the LastEvaluatedKey of the last query, empty if the partition is exhausted
+    lastKey map[string]types.AttributeValue
+}
+
+// queryCategory reads up to query.Limit listings of a single category after startKey, or all of them without a limit
+// The filter on the status is applied after the query limit, so the query is repeated from its LastEvaluatedKey until
+// the page is full or the partition is exhausted.
+func (d DynamoDataAccess) queryCategory(ctx context.Context, query model.CategoryQuery, category string, indexName string, startKey map[string]types.AttributeValue) (*categoryStream, error) {
+    keyCondition, filter := buildCategoryConditions(query, category)
     expr, err := expression.NewBuilder().
-        WithKeyCondition(expression.Key("Category").Equal(expression.Value(category))).
+        WithKeyCondition(keyCondition).
+        WithFilter(filter).
         Build()
     if err != nil {
         return nil, err
     }
 
-    input := &dynamodb.QueryInput{
+    stream := &categoryStream{category: category, startKey: startKey, lastKey: startKey}
+    for {
+        input := &dynamodb.QueryInput{
+            KeyConditionExpression:    expr.KeyCondition(),
+            FilterExpression:          expr.Filter(),
+            ExpressionAttributeNames:  expr.Names(),
+            ExpressionAttributeValues: expr.Values(),
+            TableName:                 aws.String(constant.TableName),
+            IndexName:                 aws.String(indexName),
+            ScanIndexForward:          aws.Bool(query.OrderBy == enum.OrderByAscending),
+            ExclusiveStartKey:         stream.lastKey,
+        }
+        if query.Limit > 0 {
+            // never evaluate more items than the page has room for, so the LastEvaluatedKey is the last listing
+            input.Limit = aws.Int32(int32(query.Limit - len(stream.listings)))
+        }
+        output, err := d.client.Query(ctx, input)
+        var apiErr smithy.APIError
+        if startKey != nil && errors.As(err, &apiErr) && apiErr.ErrorCode() == "ValidationException" {
+            // the cursor was issued for other bounds of the sort key
+            return nil, exception.NewInvalidInputException("cursor is outside the range of the query", err)
+        }
+        if err != nil {
+            d.log.Errorf("failed to query category %s: %v", category, err)
+            return nil, err
+        }
+
+        var listings []model.Listing
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &listings)
+        if err != nil {
+            d.log.Errorf("failed to unmarshal listings: %v", err)
+            return nil, err
+        }
+        stream.listings = append(stream.listings, listings...)
+
+        stream.lastKey = output.LastEvaluatedKey
+        if len(stream.lastKey) == 0 || (query.Limit > 0 && len(stream.listings) >= query.Limit) {
+            return stream, nil
+        }
+    }
+}
+
+// mergeCategoryStreams takes the first listing in the query order among the streams until the page is full
+func mergeCategoryStreams(streams []*categoryStream, query model.CategoryQuery) []model.Listing {
+    var merged []model.Listing
+    for query.Limit == 0 || len(merged) < query.Limit {
+        var first *categoryStream
+        for _, stream := range streams {
+            if stream.merged < len(stream.listings) &&
+                (first == nil || precedes(stream.listings[stream.merged], first.listings[first.merged], query)) {
+                first = stream
+            }
+        }
+        if first == nil {
+            break
+        }
+        merged = append(merged, first.listings[first.merged])
+        first.merged++
+    }
+    return merged
+}
+
+// precedes reports whether listing a comes before b in the order of the query, breaking ties by listing ID
+func precedes(a model.Listing, b model.Listing, query model.CategoryQuery) bool {
+    if query.OrderBy == enum.OrderByDescending {
+        a, b = b, a
+    }
+    if query.SortBy == enum.SortByPrice && a.Price != b.Price {
+        return a.Price < b.Price
+    }
+    if query.SortBy == enum.SortByCreatedAt && a.CreatedAt.Unix() != b.CreatedAt.Unix() {
+        return a.CreatedAt.Unix() < b.CreatedAt.Unix()
+    }
+    return a.ListingId < b.ListingId
+}
+
+// getCategoryTree retrieves a category and those of its subcategories that have active listings
+func (d DynamoDataAccess) getCategoryTree(ctx context.Context, category string) ([]string, error) {
+    expr, err := expression.NewBuilder().
+        WithKeyCondition(expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(constant.CategoryMetricRecordPartitionKey)).
+            And(expression.Key(constant.ListingTableSortKeyName).BeginsWith(category + model.CategorySeparator))).
+        WithFilter(expression.Name(constant.CategoryCountAttributeName).GreaterThan(expression.Value(0))).
+        Build()
+    if err != nil {
+        return nil, err
+    }
+
+    categories := []string{category}
+    paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
         KeyConditionExpression:    expr.KeyCondition(),
+        FilterExpression:          expr.Filter(),
         ExpressionAttributeNames:  expr.Names(),
         ExpressionAttributeValues: expr.Values(),
         TableName:                 aws.String(constant.TableName),
-        IndexName:                 aws.String(indexName),
-        ScanIndexForward:          aws.Bool(order == enum.OrderByAscending),
-    }
-    output, err := d.client.Query(context.TODO(), input)
-    if err != nil {
-        d.log.Errorf("failed to query category %s: %v", category, err)
-        return nil, err
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            return nil, err
+        }
+        categoryMetrics, err := unmarshalCategoryMetrics(output.Items)
+        if err != nil {
+            return nil, err
+        }
+        for _, categoryMetric := range categoryMetrics {
+            categories = append(categories, categoryMetric.Category)
+        }
     }
+    return categories, nil
+}
 
-    var listings []model.Listing
-    err = attributevalue.UnmarshalListOfMaps(output.Items, &listings)
-    if err != nil {
-        d.log.Errorf("failed to unmarshal listings: %v", err)
+// GetTopCategory retrieves the subcategory of parent, or the top-level category if parent is empty, with the highest
+// total number of listings, the first of its ranking by GetTopCategories
+// Returns nil if no such category has ever been used
+func (d DynamoDataAccess) GetTopCategory(ctx context.Context, parent string) (*model.CategoryMetric, error) {
+    metrics, err := d.GetTopCategories(ctx, model.TopCategoriesQuery{Parent: parent, Limit: 1})
+    if err != nil || len(metrics) == 0 {
         return nil, err
     }
-
-    return listings, nil
+    return &metrics[0], nil
 }
 
-// GetTopCategory retrieves the category with the highest total number of listings
-func (d DynamoDataAccess) GetTopCategory() (string, error) {
+// GetTopCategories ranks the categories selected by query by descending total number of listings, then by descending name.
+// Category metrics are read from CategoryCountIndex by descending count, which leaves the order of equal counts
+// undefined, so reading stops only past the count of the last ranked category and ties are sorted by name.
+func (d DynamoDataAccess) GetTopCategories(ctx context.Context, query model.TopCategoriesQuery) ([]model.CategoryMetric, error) {
     expr, err := expression.NewBuilder().
         WithKeyCondition(expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(constant.CategoryMetricRecordPartitionKey))).
         Build()
     if err != nil {
-        return "", err
+        return nil, err
     }
 
     // Create a QueryInput struct
@@ -338,29 +748,144 @@ func (d DynamoDataAccess) GetTopCategory() (string, error) {
         TableName:                 aws.String(constant.TableName),
         IndexName:                 aws.String(constant.CategoryCountIndex),
         ScanIndexForward:          aws.Bool(false), // descending order
-        Limit:                     aws.Int32(1),
     }
-    output, err := d.client.Query(context.TODO(), input)
+    // metrics are read by descending count, so the count of the last ranked category is known once limit are read
+    var metrics []model.CategoryMetric
+    done := false
+    paginator := dynamodb.NewQueryPaginator(d.client, input)
+    for paginator.HasMorePages() && !done {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            d.log.Errorf("failed to query top categories: %v", err)
+            return nil, err
+        }
+
+        d.log.Debugf("Query output items: %s", util.AnyToJsonString(output.Items))
+        categoryMetrics, err := unmarshalCategoryMetrics(output.Items)
+        if err != nil {
+            d.log.Errorf("failed to unmarshal category metrics: %v", err)
+            return nil, err
+        }
+        for _, categoryMetric := range categoryMetrics {
+            if query.Limit > 0 && len(metrics) >= query.Limit && categoryMetric.CategoryCount < metrics[query.Limit-1].CategoryCount {
+                done = true
+                break
+            }
+            if query.ExcludeEmpty && categoryMetric.CategoryCount == 0 {
+                done = true
+                break
+            }
+            if query.Matches(categoryMetric) {
+                metrics = append(metrics, categoryMetric)
+            }
+        }
+    }
+
+    sort.Slice(metrics, func(i, j int) bool {
+        return metrics[i].RanksBefore(metrics[j])
+    })
+    if query.Limit > 0 && len(metrics) > query.Limit {
+        metrics = metrics[:query.Limit]
+    }
+
+    return metrics, nil
+}
+
+// UpdateListing replaces a listing with the update applied, conditional on the version that was read.
+// If an active listing changes category, the count moves from the old to the new CategoryMetric in the same transaction.
+func (d DynamoDataAccess) UpdateListing(ctx context.Context, username string, listingId int, update model.ListingUpdate) (*model.Listing, error) {
+    listing, err := d.GetListing(ctx, listingId)
+    if err != nil {
+        d.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    if listing == nil {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    if listing.Username != username {
+        return nil, exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", listingId, username), nil)
+    }
+
+    updated, err := listing.Apply(update)
+    if err != nil {
+        return nil, err
+    }
+    if updated.Category != listing.Category {
+        entry, err := model.CheckCategoryOpen(updated.Category, d.lookupCategory(ctx))
+        if err != nil {
+            return nil, err
+        }
+        updated.Category = entry.Name
+    }
+
+    av, err := updated.DdbMarshalMap()
+    if err != nil {
+        d.log.Errorf("failed to marshal Listing struct %s to attribute value map: %v", util.AnyToJsonString(updated), err)
+        return nil, err
+    }
+
+    putListingExpr, err := expression.NewBuilder().WithCondition(buildVersionCondition(*listing)).Build()
     if err != nil {
-        d.log.Errorf("failed to query top category: %v", err)
-        return "", err
+        return nil, err
+    }
+
+    transactItems := []types.TransactWriteItem{
+        {
+            Put: &types.Put{
+                Item:                      av,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putListingExpr.Names(),
+                ExpressionAttributeValues: putListingExpr.Values(),
+                ConditionExpression:       putListingExpr.Condition(),
+            },
+        },
+    }
+    if updated.Category != listing.Category && listing.IsActive() {
+        moveItems, err := buildCategoryCountMoveItems(listing.Category, updated.Category)
+        if err != nil {
+            return nil, err
+        }
+        transactItems = append(transactItems, moveItems...)
+    }
+    if updated.Category != listing.Category {
+        openItems, err := buildCategoryOpenItems(updated.Category)
+        if err != nil {
+            return nil, err
+        }
+        transactItems = append(transactItems, openItems...)
     }
 
-    d.log.Debugf("Query output items: %s", util.AnyToJsonString(output.Items))
-    var categoryMetric model.CategoryMetric
-    err = attributevalue.UnmarshalMap(output.Items[0], &categoryMetric)
+    _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
     if err != nil {
-        d.log.Errorf("failed to unmarshal category metric: %v", err)
-        return "", err
+        var txCanceledErr *types.TransactionCanceledException
+        if errors.As(err, &txCanceledErr) {
+            for idx, reason := range txCanceledErr.CancellationReasons {
+                if *reason.Code != "None" {
+                    d.log.Errorf("Transaction cancelled at index %d with reason: %v", idx, reason)
+                }
+                if idx == 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return nil, exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listingId), err)
+                }
+                if idx > 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return nil, d.categoryConflictError(ctx, updated.Category, err)
+                }
+            }
+        }
+        d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
+        return nil, err
     }
+    d.updateSearchIndex(ctx, listing, &updated)
 
-    return categoryMetric.Category, nil
+    return &updated, nil
 }
 
-// DeleteListing deletes a listing and updates the CategoryMetric
-func (d DynamoDataAccess) DeleteListing(username string, listingId int) error {
+// DeleteListing deletes a listing, conditional on the version that was read, and decrements the CategoryMetric if the
+// listing is active
+func (d DynamoDataAccess) DeleteListing(ctx context.Context, username string, listingId int) error {
     // Get the listing to be deleted
-    listing, err := d.GetListing(listingId)
+    listing, err := d.GetListing(ctx, listingId)
     if err != nil {
         d.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
         return err
@@ -376,46 +901,214 @@ func (d DynamoDataAccess) DeleteListing(username string, listingId int) error {
         return exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", listingId, username), nil)
     }
 
+    if listing.IsSold() {
+        return exception.NewListingSoldException(fmt.Sprintf("listing with listingId %d is sold", listingId), nil)
+    }
+
     // Prepare the TransactWriteItems input
-    deleteListingExpr, err := expression.NewBuilder().WithCondition(
-        expression.Name(constant.ListingTablePartitionKeyName).Equal(expression.Value(listingId)).And(
-            expression.Name(constant.ListingTableSortKeyName).Equal(expression.Value(username))),
-    ).Build()
+    deleteListingExpr, err := expression.NewBuilder().WithCondition(buildVersionCondition(*listing)).Build()
     if err != nil {
         return err
     }
-    decrementCategoryCountExpr, err := expression.NewBuilder().WithUpdate(expression.Add(expression.Name("CategoryCount"), expression.Value(-1))).Build()
+    transactItems := []types.TransactWriteItem{
+        {
+            Delete: &types.Delete{
+                Key: map[string]types.AttributeValue{
+                    constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(listingId)},
+                    constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: username},
+                },
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  deleteListingExpr.Names(),
+                ExpressionAttributeValues: deleteListingExpr.Values(),
+                ConditionExpression:       deleteListingExpr.Condition(),
+            },
+        },
+    }
+    if listing.IsActive() {
+        decrementItems, err := buildCategoryCountItems(listing.Category, -1)
+        if err != nil {
+            return err
+        }
+        transactItems = append(transactItems, decrementItems...)
+    }
+
+    // Execute the transaction
+    _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
     if err != nil {
+        var txCanceledErr *types.TransactionCanceledException
+        if errors.As(err, &txCanceledErr) {
+            for idx, reason := range txCanceledErr.CancellationReasons {
+                if *reason.Code != "None" {
+                    d.log.Errorf("Transaction cancelled at index %d with reason: %v", idx, reason)
+                }
+                if idx == 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listingId), err)
+                }
+            }
+        }
+        d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
         return err
     }
-    input := &dynamodb.TransactWriteItemsInput{
-        TransactItems: []types.TransactWriteItem{
-            {
-                Delete: &types.Delete{
-                    Key: map[string]types.AttributeValue{
-                        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(listingId)},
-                        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: username},
-                    },
-                    TableName:                 aws.String(constant.TableName),
-                    ExpressionAttributeNames:  deleteListingExpr.Names(),
-                    ExpressionAttributeValues: deleteListingExpr.Values(),
-                    ConditionExpression:       deleteListingExpr.Condition(),
-                },
+    d.updateSearchIndex(ctx, listing, nil)
+
+    return nil
+}
+
+// TransitionListing replaces a listing with its next status, conditional on the version that was read. If the listing
+// becomes or stops being active, the CategoryMetric is adjusted in the same transaction.
+func (d DynamoDataAccess) TransitionListing(ctx context.Context, username string, listingId int, transition enum.ListingTransition) (*model.Listing, error) {
+    listing, err := d.GetListing(ctx, listingId)
+    if err != nil {
+        d.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    if listing == nil {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    updated, err := listing.Transition(username, transition)
+    if err != nil {
+        return nil, err
+    }
+
+    av, err := updated.DdbMarshalMap()
+    if err != nil {
+        d.log.Errorf("failed to marshal Listing struct %s to attribute value map: %v", util.AnyToJsonString(updated), err)
+        return nil, err
+    }
+
+    putListingExpr, err := expression.NewBuilder().WithCondition(buildVersionCondition(*listing)).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    transactItems := []types.TransactWriteItem{
+        {
+            Put: &types.Put{
+                Item:                      av,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putListingExpr.Names(),
+                ExpressionAttributeValues: putListingExpr.Values(),
+                ConditionExpression:       putListingExpr.Condition(),
             },
-            {
-                Update: &types.Update{
-                    Key:                       buildCategoryMetricKey(listing.Category),
-                    ExpressionAttributeNames:  decrementCategoryCountExpr.Names(),
-                    ExpressionAttributeValues: decrementCategoryCountExpr.Values(),
-                    UpdateExpression:          decrementCategoryCountExpr.Update(),
-                    TableName:                 aws.String(constant.TableName),
-                },
+        },
+    }
+    if delta := model.CategoryCountDelta(*listing, updated); delta != 0 {
+        countItems, err := buildCategoryCountItems(listing.Category, delta)
+        if err != nil {
+            return nil, err
+        }
+        transactItems = append(transactItems, countItems...)
+    }
+
+    _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+    if err != nil {
+        var txCanceledErr *types.TransactionCanceledException
+        if errors.As(err, &txCanceledErr) {
+            for idx, reason := range txCanceledErr.CancellationReasons {
+                if *reason.Code != "None" {
+                    d.log.Errorf("Transaction cancelled at index %d with reason: %v", idx, reason)
+                }
+                if idx == 0 && *reason.Code == "ConditionalCheckFailed" {
+                    return nil, exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listingId), err)
+                }
+            }
+        }
+        d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
+        return nil, err
+    }
+
+    return &updated, nil
+}
+
+// BuyListing marks a listing as sold to buyer, conditional on the version that was read. The order is written under
+// both the buyer and the seller, and the category count is adjusted in the same transaction.
+func (d DynamoDataAccess) BuyListing(ctx context.Context, buyer string, listingId int) (*model.Order, error) {
+    listing, err := d.GetListing(ctx, listingId)
+    if err != nil {
+        d.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    if listing == nil {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    sold, err := listing.Transition(buyer, enum.ListingTransitionBuy)
+    if err != nil {
+        return nil, err
+    }
+
+    order, err := model.NewOrder(*listing, buyer)
+    if err != nil {
+        return nil, err
+    }
+
+    listingAv, err := sold.DdbMarshalMap()
+    if err != nil {
+        d.log.Errorf("failed to marshal Listing struct %s to attribute value map: %v", util.AnyToJsonString(sold), err)
+        return nil, err
+    }
+    buyerOrderAv, err := order.DdbMarshalMap(order.Buyer)
+    if err != nil {
+        d.log.Errorf("failed to marshal Order struct %s to attribute value map: %v", util.AnyToJsonString(order), err)
+        return nil, err
+    }
+    sellerOrderAv, err := order.DdbMarshalMap(order.Seller)
+    if err != nil {
+        d.log.Errorf("failed to marshal Order struct %s to attribute value map: %v", util.AnyToJsonString(order), err)
+        return nil, err
+    }
+
+    putListingExpr, err := expression.NewBuilder().WithCondition(buildVersionCondition(*listing)).Build()
+    if err != nil {
+        return nil, err
+    }
+    putOrderExpr, err := expression.NewBuilder().WithCondition(
+        expression.Name(constant.ListingTablePartitionKeyName).AttributeNotExists()).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    transactItems := []types.TransactWriteItem{
+        {
+            Put: &types.Put{
+                Item:                      listingAv,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putListingExpr.Names(),
+                ExpressionAttributeValues: putListingExpr.Values(),
+                ConditionExpression:       putListingExpr.Condition(),
+            },
+        },
+        {
+            Put: &types.Put{
+                Item:                      buyerOrderAv,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putOrderExpr.Names(),
+                ExpressionAttributeValues: putOrderExpr.Values(),
+                ConditionExpression:       putOrderExpr.Condition(),
             },
         },
+        {
+            Put: &types.Put{
+                Item:                      sellerOrderAv,
+                TableName:                 aws.String(constant.TableName),
+                ExpressionAttributeNames:  putOrderExpr.Names(),
+                ExpressionAttributeValues: putOrderExpr.Values(),
+                ConditionExpression:       putOrderExpr.Condition(),
+            },
+        },
+    }
+    if delta := model.CategoryCountDelta(*listing, sold); delta != 0 {
+        countItems, err := buildCategoryCountItems(listing.Category, delta)
+        if err != nil {
+            return nil, err
+        }
+        transactItems = append(transactItems, countItems...)
     }
 
-    // Execute the transaction
-    _, err = d.client.TransactWriteItems(context.TODO(), input)
+    _, err = d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
     if err != nil {
         var txCanceledErr *types.TransactionCanceledException
         if errors.As(err, &txCanceledErr) {
@@ -423,13 +1116,228 @@ func (d DynamoDataAccess) DeleteListing(username string, listingId int) error {
                 if *reason.Code != "None" {
                     d.log.Errorf("Transaction cancelled at index %d with reason: %v", idx, reason)
                 }
+                // the order records only exist if the listing was sold, so any failed condition means the listing changed
+                if *reason.Code == "ConditionalCheckFailed" {
+                    return nil, d.buyConflictError(ctx, listingId, err)
+                }
             }
         }
         d.log.Errorf("TransactWriteItems failed with unhandled error: %v", err)
+        return nil, err
+    }
+
+    return &order, nil
+}
+
+// buyConflictError re-reads a listing whose purchase failed a condition check to report why
+func (d DynamoDataAccess) buyConflictError(ctx context.Context, listingId int, cause error) error {
+    listing, err := d.GetListing(ctx, listingId)
+    if err != nil {
         return err
     }
+    if listing == nil {
+        return exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), cause)
+    }
+    if listing.IsSold() {
+        return exception.NewListingSoldException(fmt.Sprintf("listing with listingId %d is already sold", listingId), cause)
+    }
+    return exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listingId), cause)
+}
 
-    return nil
+// GetOrders retrieves the orders in which username is the buyer or the seller, newest first
+func (d DynamoDataAccess) GetOrders(ctx context.Context, username string) ([]model.Order, error) {
+    // the sort key prefix may also match users whose name continues with '#', so filter on the parties as well
+    expr, err := expression.NewBuilder().
+        WithKeyCondition(expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(constant.OrderRecordPartitionKey)).And(
+            expression.Key(constant.ListingTableSortKeyName).BeginsWith(username + "#"))).
+        WithFilter(expression.Name("Buyer").Equal(expression.Value(username)).Or(
+            expression.Name("Seller").Equal(expression.Value(username)))).
+        Build()
+    if err != nil {
+        return nil, err
+    }
+
+    var orders []model.Order
+    paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+        KeyConditionExpression:    expr.KeyCondition(),
+        FilterExpression:          expr.Filter(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        TableName:                 aws.String(constant.TableName),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            d.log.Errorf("failed to query orders of %s: %v", username, err)
+            return nil, err
+        }
+
+        var page []model.Order
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &page)
+        if err != nil {
+            d.log.Errorf("failed to unmarshal orders: %v", err)
+            return nil, err
+        }
+        orders = append(orders, page...)
+    }
+    model.SortOrders(orders)
+
+    return orders, nil
+}
+
+// buildVersionCondition requires the stored listing to still be at the version that was read
+func buildVersionCondition(listing model.Listing) expression.ConditionBuilder {
+    // listings written before versioning was introduced have no Version attribute
+    versionCondition := expression.Name("Version").Equal(expression.Value(listing.Version))
+    if listing.Version == 0 {
+        versionCondition = expression.Name("Version").AttributeNotExists()
+    }
+    return expression.Name(constant.ListingTablePartitionKeyName).AttributeExists().And(
+        expression.Name(constant.ListingTableSortKeyName).Equal(expression.Value(listing.Username)),
+        versionCondition,
+    )
+}
+
+// buildActiveCondition matches active listings, including those written before statuses existed
+func buildActiveCondition() expression.ConditionBuilder {
+    return expression.Name("Status").AttributeNotExists().Or(
+        expression.Name("Status").Equal(expression.Value(enum.ListingStatusActive)))
+}
+
+// buildCategoryConditions builds the key condition and filter of a category query on one of the categories it covers.
+// The bounds on the sort key of the queried index are part of the key condition, and every other criterion is applied
+// by the filter.
+func buildCategoryConditions(query model.CategoryQuery, category string) (expression.KeyConditionBuilder, expression.ConditionBuilder) {
+    keyCondition := expression.Key("Category").Equal(expression.Value(category))
+    filter := buildActiveCondition()
+
+    var minPrice, maxPrice *int64
+    if query.MinPrice != nil {
+        price := int64(*query.MinPrice)
+        minPrice = &price
+    }
+    if query.MaxPrice != nil {
+        price := int64(*query.MaxPrice)
+        maxPrice = &price
+    }
+    createdFrom, createdTo := query.CreatedAtRange()
+
+    if query.SortBy == enum.SortByPrice {
+        keyCondition = buildKeyRange(keyCondition, "Price", minPrice, maxPrice)
+        filter = buildFilterRange(filter, "CreatedAt", createdFrom, createdTo)
+    } else {
+        keyCondition = buildKeyRange(keyCondition, "CreatedAt", createdFrom, createdTo)
+        filter = buildFilterRange(filter, "Price", minPrice, maxPrice)
+    }
+    if query.TitleContains != "" {
+        filter = filter.And(expression.Name("Title").Contains(query.TitleContains))
+    }
+
+    return keyCondition, filter
+}
+
+// buildKeyRange adds inclusive bounds on the sort key to a key condition. Nil bounds are left open.
+func buildKeyRange(keyCondition expression.KeyConditionBuilder, name string, from *int64, to *int64) expression.KeyConditionBuilder {
+    switch {
+    case from != nil && to != nil:
+        return keyCondition.And(expression.Key(name).Between(expression.Value(*from), expression.Value(*to)))
+    case from != nil:
+        return keyCondition.And(expression.Key(name).GreaterThanEqual(expression.Value(*from)))
+    case to != nil:
+        return keyCondition.And(expression.Key(name).LessThanEqual(expression.Value(*to)))
+    default:
+        return keyCondition
+    }
+}
+
+// buildFilterRange adds inclusive bounds on an attribute to a filter. Nil bounds are left open.
+func buildFilterRange(filter expression.ConditionBuilder, name string, from *int64, to *int64) expression.ConditionBuilder {
+    if from != nil {
+        filter = filter.And(expression.Name(name).GreaterThanEqual(expression.Value(*from)))
+    }
+    if to != nil {
+        filter = filter.And(expression.Name(name).LessThanEqual(expression.Value(*to)))
+    }
+    return filter
+}
+
+// buildCategoryCountMoveItems decrements the counts of one category and its ancestors and increments the counts of
+// another category and its ancestors. Common ancestors are left out, as a transaction can update an item only once.
+func buildCategoryCountMoveItems(fromCategory string, toCategory string) ([]types.TransactWriteItem, error) {
+    fromAncestors := model.CategoryAncestors(fromCategory)
+    toAncestors := model.CategoryAncestors(toCategory)
+    common := make(map[string]bool)
+    for _, from := range fromAncestors {
+        for _, to := range toAncestors {
+            if from == to {
+                common[from] = true
+            }
+        }
+    }
+
+    var items []types.TransactWriteItem
+    for _, change := range []struct {
+        categories []string
+        delta      int
+    }{{fromAncestors, -1}, {toAncestors, 1}} {
+        for _, category := range change.categories {
+            if common[category] {
+                continue
+            }
+            item, err := buildCategoryCountItem(category, change.delta)
+            if err != nil {
+                return nil, err
+            }
+            items = append(items, item)
+        }
+    }
+
+    return items, nil
+}
+
+// buildCategoryCountItems adds delta to the counts of a category and its ancestors
+func buildCategoryCountItems(category string, delta int) ([]types.TransactWriteItem, error) {
+    var items []types.TransactWriteItem
+    for _, ancestor := range model.CategoryAncestors(category) {
+        item, err := buildCategoryCountItem(ancestor, delta)
+        if err != nil {
+            return nil, err
+        }
+        items = append(items, item)
+    }
+    return items, nil
+}
+
+// buildCategoryCountItem adds delta to the count of a category
+func buildCategoryCountItem(category string, delta int) (types.TransactWriteItem, error) {
+    expr, err := expression.NewBuilder().WithUpdate(expression.Add(expression.Name(constant.CategoryCountAttributeName), expression.Value(delta))).Build()
+    if err != nil {
+        return types.TransactWriteItem{}, err
+    }
+
+    return types.TransactWriteItem{
+        Update: &types.Update{
+            Key:                       buildCategoryMetricKey(category),
+            ExpressionAttributeNames:  expr.Names(),
+            ExpressionAttributeValues: expr.Values(),
+            UpdateExpression:          expr.Update(),
+            TableName:                 aws.String(constant.TableName),
+        },
+    }, nil
+}
+
+func buildUserKey(key string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.UserRootRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: key},
+    }
+}
+
+func buildListingIdCounterKey() map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.ListingIdCounterRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: constant.ListingIdCounterRecordSortKey},
+    }
 }
 
 func buildCategoryMetricKey(category string) map[string]types.AttributeValue {
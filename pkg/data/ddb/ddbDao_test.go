@@ -0,0 +1,376 @@
+package ddb
+
+import (
+    "context"
+    "errors"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/data/storetest"
+    "net"
+    "net/url"
+    "os"
+    "strconv"
+    "testing"
+    "time"
+)
+
+// newTestStore creates a dedicated table on DynamoDB Local, skipping the test when it is not reachable
+func newTestStore(t *testing.T) DynamoDataAccess {
+    ctx := context.Background()
+    endpoint, endpointExists := os.LookupEnv(constant.DynamoDbEndpointEnvKey)
+    if !endpointExists {
+        endpoint = "http://localhost:8000"
+    }
+    endpointUrl, err := url.Parse(endpoint)
+    if err != nil {
+        t.Fatalf("invalid DynamoDB endpoint %q: %v", endpoint, err)
+    }
+    conn, err := net.DialTimeout("tcp", endpointUrl.Host, time.Second)
+    if err != nil {
+        t.Skipf("DynamoDB Local is not reachable at %s: %v", endpoint, err)
+    }
+    _ = conn.Close()
+
+    tableName := constant.TableName
+    constant.TableName = "Listing" + t.Name()
+    t.Cleanup(func() {
+        constant.TableName = tableName
+    })
+
+    store := NewDynamoDataAccess(zap.NewNop().Sugar())
+    exists, err := store.ListingTableExists(ctx)
+    if err != nil {
+        t.Skipf("DynamoDB Local is not reachable: %v", err)
+    }
+    if exists {
+        err = store.DeleteTable(ctx)
+        if err != nil {
+            t.Fatalf("could not delete leftover table: %v", err)
+        }
+    }
+
+    _, err = store.CreateListingTable(ctx)
+    if err != nil {
+        t.Fatalf("could not create table: %v", err)
+    }
+    t.Cleanup(func() {
+        _ = store.DeleteTable(ctx)
+    })
+    return store
+}
+
+func TestConcurrentPutListing(t *testing.T) {
+    storetest.ConcurrentPutListing(t, newTestStore(t), 50)
+}
+
+func TestConcurrentUpdateListing(t *testing.T) {
+    storetest.ConcurrentUpdateListing(t, newTestStore(t), 20)
+}
+
+func TestConcurrentBuyListing(t *testing.T) {
+    storetest.ConcurrentBuyListing(t, newTestStore(t), 20)
+}
+
+func TestListingLifecycle(t *testing.T) {
+    storetest.ListingLifecycle(t, newTestStore(t))
+}
+
+func TestCategoryPagination(t *testing.T) {
+    storetest.CategoryPagination(t, newTestStore(t))
+}
+
+func TestCategoryFilters(t *testing.T) {
+    storetest.CategoryFilters(t, newTestStore(t))
+}
+
+func TestCategoryHierarchy(t *testing.T) {
+    storetest.CategoryHierarchy(t, newTestStore(t))
+}
+
+func TestSearchListings(t *testing.T) {
+    storetest.SearchListings(t, newTestStore(t))
+}
+
+func TestCategoryCatalog(t *testing.T) {
+    storetest.CategoryCatalog(t, newTestStore(t))
+}
+
+func TestCanonicalNames(t *testing.T) {
+    storetest.CanonicalNames(t, newTestStore(t))
+}
+
+func TestTopCategories(t *testing.T) {
+    storetest.TopCategories(t, newTestStore(t))
+}
+
+func TestEmptyMarketplace(t *testing.T) {
+    storetest.EmptyMarketplace(t, newTestStore(t))
+}
+
+func TestSessions(t *testing.T) {
+    storetest.Sessions(t, newTestStore(t))
+}
+
+func TestRoles(t *testing.T) {
+    storetest.Roles(t, newTestStore(t))
+}
+
+func TestApiKeys(t *testing.T) {
+    storetest.ApiKeys(t, newTestStore(t))
+}
+
+func TestRateLimits(t *testing.T) {
+    storetest.RateLimits(t, newTestStore(t))
+}
+
+func TestConcurrentRateLimit(t *testing.T) {
+    storetest.ConcurrentRateLimit(t, newTestStore(t), 20)
+}
+
+func TestIdempotencyRecords(t *testing.T) {
+    storetest.IdempotencyRecords(t, newTestStore(t))
+}
+
+func TestConcurrentIdempotencyRecord(t *testing.T) {
+    storetest.ConcurrentIdempotencyRecord(t, newTestStore(t), 20)
+}
+
+func TestCancelledContext(t *testing.T) {
+    storetest.CancelledContext(t, newTestStore(t))
+}
+
+// pagedQueryClient answers every query with its pages of items in turn, as DynamoDB does for results over 1 MB
+type pagedQueryClient struct {
+    dynamoClient
+    pages [][]map[string]types.AttributeValue
+}
+
+func (c pagedQueryClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+    page := 0
+    if start, ok := params.ExclusiveStartKey["Page"].(*types.AttributeValueMemberN); ok {
+        page, _ = strconv.Atoi(start.Value)
+    }
+    output := &dynamodb.QueryOutput{Items: c.pages[page]}
+    if page+1 < len(c.pages) {
+        output.LastEvaluatedKey = map[string]types.AttributeValue{
+            "Page": &types.AttributeValueMemberN{Value: strconv.Itoa(page + 1)},
+        }
+    }
+    return output, nil
+}
+
+func TestGetOrdersPages(t *testing.T) {
+    now := time.Now().Truncate(time.Second)
+    client := pagedQueryClient{}
+    for i := 0; i < 3; i++ {
+        item, err := attributevalue.MarshalMap(model.Order{
+            ListingId: constant.FirstListingId + i,
+            Seller:    "seller",
+            Buyer:     "buyer",
+            CreatedAt: now.Add(time.Duration(i) * time.Minute),
+        })
+        if err != nil {
+            t.Fatalf("could not marshal order: %v", err)
+        }
+        client.pages = append(client.pages, []map[string]types.AttributeValue{item})
+    }
+    store := DynamoDataAccess{client: client, log: zap.NewNop().Sugar()}
+
+    orders, err := store.GetOrders(context.Background(), "buyer")
+    if err != nil {
+        t.Fatalf("could not get orders: %v", err)
+    }
+    if len(orders) != 3 || orders[0].ListingId != constant.FirstListingId+2 {
+        t.Fatalf("expected the orders of every page, newest first, got %+v", orders)
+    }
+}
+
+// scannedListingsClient scans the listings with the given IDs, and stores the items put unless their key exists
+type scannedListingsClient struct {
+    dynamoClient
+    listingIds []int
+    items      map[string]map[string]types.AttributeValue
+}
+
+func (c scannedListingsClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+    output := &dynamodb.ScanOutput{}
+    for _, listingId := range c.listingIds {
+        output.Items = append(output.Items, map[string]types.AttributeValue{
+            constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(listingId)},
+        })
+    }
+    return output, nil
+}
+
+func (c scannedListingsClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+    key := params.Item[constant.ListingTablePartitionKeyName].(*types.AttributeValueMemberN).Value
+    if _, exists := c.items[key]; exists {
+        return nil, &types.ConditionalCheckFailedException{Message: aws.String("condition failed")}
+    }
+    c.items[key] = params.Item
+    return &dynamodb.PutItemOutput{}, nil
+}
+
+func TestSeedListingIdCounter(t *testing.T) {
+    ctx := context.Background()
+    client := scannedListingsClient{
+        listingIds: []int{constant.FirstListingId + 4, constant.FirstListingId + 9, constant.FirstListingId},
+        items:      make(map[string]map[string]types.AttributeValue),
+    }
+    store := DynamoDataAccess{client: client, log: zap.NewNop().Sugar()}
+
+    lastListingId, err := store.SeedListingIdCounter(ctx)
+    if err != nil || lastListingId != constant.FirstListingId+9 {
+        t.Fatalf("expected the counter to be seeded at %d, got %d, %v", constant.FirstListingId+9, lastListingId, err)
+    }
+    counter := client.items[strconv.Itoa(constant.ListingIdCounterRecordPartitionKey)]
+    value, ok := counter[constant.ListingIdCounterAttributeName].(*types.AttributeValueMemberN)
+    if !ok || value.Value != strconv.Itoa(constant.FirstListingId+9) {
+        t.Fatalf("expected the counter record to hold the highest listing ID, got %v", counter)
+    }
+
+    // a counter that exists already is kept
+    lastListingId, err = store.SeedListingIdCounter(ctx)
+    if err != nil || lastListingId != 0 {
+        t.Fatalf("expected the existing counter to be kept, got %d, %v", lastListingId, err)
+    }
+}
+
+func TestPutListingAfterSeed(t *testing.T) {
+    ctx := context.Background()
+    store := newTestStore(t)
+    _, err := store.PutCategory(ctx, "Electronics")
+    if err != nil {
+        t.Fatalf("could not put category: %v", err)
+    }
+    _, err = store.PutUser(ctx, "user1", "hash")
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    for i := 0; i < 2; i++ {
+        _, err = store.PutListing(ctx, "user1", "Phone", "Black", 1000, "Electronics", enum.ListingStatusActive)
+        if err != nil {
+            t.Fatalf("could not put listing: %v", err)
+        }
+    }
+
+    // a table written before listing IDs came from the counter has listings but no counter
+    _, err = store.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        Key:       buildListingIdCounterKey(),
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        t.Fatalf("could not delete listing ID counter: %v", err)
+    }
+    lastListingId, err := store.SeedListingIdCounter(ctx)
+    if err != nil || lastListingId != constant.FirstListingId+1 {
+        t.Fatalf("expected the counter to be seeded at %d, got %d, %v", constant.FirstListingId+1, lastListingId, err)
+    }
+
+    listing, err := store.PutListing(ctx, "user1", "Tablet", "White", 2000, "Electronics", enum.ListingStatusActive)
+    if err != nil || listing == nil || listing.ListingId != constant.FirstListingId+2 {
+        t.Fatalf("expected listing %d after the existing ones, got %v, %v", constant.FirstListingId+2, listing, err)
+    }
+}
+
+// ttlClient describes the TTL of a table as status on attribute, and counts the updates of it
+type ttlClient struct {
+    dynamoClient
+    status    types.TimeToLiveStatus
+    attribute string
+    updates   *int
+}
+
+func (c ttlClient) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+    return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &types.TimeToLiveDescription{
+        TimeToLiveStatus: c.status,
+        AttributeName:    aws.String(c.attribute),
+    }}, nil
+}
+
+func (c ttlClient) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+    *c.updates++
+    if aws.ToString(params.TimeToLiveSpecification.AttributeName) != constant.IdempotencyExpiresAtAttributeName {
+        return nil, errors.New("unexpected TTL attribute")
+    }
+    return &dynamodb.UpdateTimeToLiveOutput{}, nil
+}
+
+func TestEnableTimeToLive(t *testing.T) {
+    ctx := context.Background()
+    for _, tc := range []struct {
+        status          types.TimeToLiveStatus
+        attribute       string
+        expectedEnabled bool
+        expectedError   bool
+    }{
+        {types.TimeToLiveStatusDisabled, "", true, false},
+        {types.TimeToLiveStatusDisabling, constant.IdempotencyExpiresAtAttributeName, true, false},
+        {types.TimeToLiveStatusEnabled, constant.IdempotencyExpiresAtAttributeName, false, false},
+        {types.TimeToLiveStatusEnabling, constant.IdempotencyExpiresAtAttributeName, false, false},
+        {types.TimeToLiveStatusEnabled, "ExpiresAt", false, true},
+    } {
+        updates := 0
+        store := DynamoDataAccess{client: ttlClient{status: tc.status, attribute: tc.attribute, updates: &updates}, log: zap.NewNop().Sugar()}
+
+        enabled, err := store.EnableTimeToLive(ctx)
+        if enabled != tc.expectedEnabled || (err != nil) != tc.expectedError {
+            t.Fatalf("TTL %s on %q: expected enabled %v and error %v, got %v, %v", tc.status, tc.attribute,
+                tc.expectedEnabled, tc.expectedError, enabled, err)
+        }
+        if enabled != (updates == 1) {
+            t.Fatalf("TTL %s on %q: expected TTL to be updated only when enabled, got %d updates", tc.status,
+                tc.attribute, updates)
+        }
+    }
+}
+
+func TestTopCategoryTiesMatchIndex(t *testing.T) {
+    ctx := context.Background()
+    store := newTestStore(t)
+    _, err := store.PutUser(ctx, "user1", "hash")
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    for _, category := range []string{"Electronics", "Sports", "Fashion"} {
+        _, err = store.PutCategory(ctx, category)
+        if err != nil {
+            t.Fatalf("could not put category: %v", err)
+        }
+        _, err = store.PutListing(ctx, "user1", "Listing", "tie test", 100, category, enum.ListingStatusActive)
+        if err != nil {
+            t.Fatalf("could not put listing: %v", err)
+        }
+    }
+
+    // the first GetTopCategory took the first metric of the count index read backwards
+    output, err := store.client.Query(ctx, &dynamodb.QueryInput{
+        KeyConditionExpression:   aws.String("#pk = :pk"),
+        ExpressionAttributeNames: map[string]string{"#pk": constant.ListingTablePartitionKeyName},
+        ExpressionAttributeValues: map[string]types.AttributeValue{
+            ":pk": &types.AttributeValueMemberN{Value: strconv.Itoa(constant.CategoryMetricRecordPartitionKey)},
+        },
+        TableName:        aws.String(constant.TableName),
+        IndexName:        aws.String(constant.CategoryCountIndex),
+        ScanIndexForward: aws.Bool(false),
+        Limit:            aws.Int32(1),
+    })
+    if err != nil || len(output.Items) != 1 {
+        t.Fatalf("could not query the count index: %v", err)
+    }
+    metrics, err := unmarshalCategoryMetrics(output.Items)
+    if err != nil {
+        t.Fatalf("could not unmarshal category metric: %v", err)
+    }
+
+    top, err := store.GetTopCategory(ctx, "")
+    if err != nil || top == nil || top.Category != "Sports" || top.Category != metrics[0].Category {
+        t.Fatalf("expected the tie to go to Sports, the first category of the index, got %v and %v, %v", top, metrics, err)
+    }
+}
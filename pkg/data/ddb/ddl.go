@@ -3,17 +3,19 @@ package ddb
 import (
     "context"
     "errors"
+    "fmt"
     "github.com/aws/aws-sdk-go-v2/aws"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
     "marketplace-platform/pkg/constant"
+    "strings"
     "time"
 )
 
 // ListingTableExists checks if the Listing table exists
-func (d DynamoDataAccess) ListingTableExists() (bool, error) {
+func (d DynamoDataAccess) ListingTableExists(ctx context.Context) (bool, error) {
     _, err := d.client.DescribeTable(
-        context.TODO(), &dynamodb.DescribeTableInput{TableName: aws.String(constant.TableName)},
+        ctx, &dynamodb.DescribeTableInput{TableName: aws.String(constant.TableName)},
     )
     if err != nil {
         var notFoundEx *types.ResourceNotFoundException
@@ -27,15 +29,15 @@ func (d DynamoDataAccess) ListingTableExists() (bool, error) {
     return true, nil
 }
 
-// CreateListingTable creates the Listing table
-func (d DynamoDataAccess) CreateListingTable() (*types.TableDescription, error) {
+// listingTableDefinition describes the Listing table with its key schema, LSI and GSIs
+func listingTableDefinition() *dynamodb.CreateTableInput {
     // ignored for DDB Local
     provisionedThroughput := &types.ProvisionedThroughput{
         ReadCapacityUnits:  aws.Int64(5),
         WriteCapacityUnits: aws.Int64(5),
     }
 
-    table, err := d.client.CreateTable(context.TODO(), &dynamodb.CreateTableInput{
+    return &dynamodb.CreateTableInput{
         AttributeDefinitions: []types.AttributeDefinition{{
             AttributeName: aws.String(constant.ListingTablePartitionKeyName),
             AttributeType: types.ScalarAttributeTypeN,
@@ -46,7 +48,7 @@ func (d DynamoDataAccess) CreateListingTable() (*types.TableDescription, error)
             AttributeName: aws.String("Category"),
             AttributeType: types.ScalarAttributeTypeS,
         }, {
-            AttributeName: aws.String("CategoryCount"),
+            AttributeName: aws.String(constant.CategoryCountAttributeName),
             AttributeType: types.ScalarAttributeTypeN,
         }, {
             AttributeName: aws.String("Price"),
@@ -54,9 +56,6 @@ func (d DynamoDataAccess) CreateListingTable() (*types.TableDescription, error)
         }, {
             AttributeName: aws.String("CreatedAt"),
             AttributeType: types.ScalarAttributeTypeN,
-        }, {
-            AttributeName: aws.String(constant.ListingIdIndexPartitionKeyName),
-            AttributeType: types.ScalarAttributeTypeN,
         }},
 
         KeySchema: []types.KeySchemaElement{{
@@ -73,7 +72,7 @@ func (d DynamoDataAccess) CreateListingTable() (*types.TableDescription, error)
                 AttributeName: aws.String(constant.ListingTablePartitionKeyName),
                 KeyType:       types.KeyTypeHash,
             }, {
-                AttributeName: aws.String("CategoryCount"),
+                AttributeName: aws.String(constant.CategoryCountAttributeName),
                 KeyType:       types.KeyTypeRange,
             }},
             Projection: &types.Projection{
@@ -110,45 +109,157 @@ func (d DynamoDataAccess) CreateListingTable() (*types.TableDescription, error)
                 },
                 ProvisionedThroughput: provisionedThroughput,
             },
-            {
-                IndexName: aws.String(constant.ListingIdIndex),
-                KeySchema: []types.KeySchemaElement{{
-                    AttributeName: aws.String(constant.ListingIdIndexPartitionKeyName),
-                    KeyType:       types.KeyTypeHash,
-                }, {
-                    AttributeName: aws.String(constant.ListingTablePartitionKeyName),
-                    KeyType:       types.KeyTypeRange,
-                }},
-                Projection: &types.Projection{
-                    ProjectionType: types.ProjectionTypeAll,
-                },
-                ProvisionedThroughput: provisionedThroughput,
-            },
         },
 
         TableName:             aws.String(constant.TableName),
         ProvisionedThroughput: provisionedThroughput,
-    })
+    }
+}
+
+// CreateListingTable creates the Listing table, with DynamoDB TTL on the expiry of idempotency records
+func (d DynamoDataAccess) CreateListingTable(ctx context.Context) (*types.TableDescription, error) {
+    table, err := d.client.CreateTable(ctx, listingTableDefinition())
     if err != nil {
         d.log.Fatalf("Got error calling CreateTable: %s", err)
     }
 
     waiter := dynamodb.NewTableExistsWaiter(d.client)
-    err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+    err = waiter.Wait(ctx, &dynamodb.DescribeTableInput{
         TableName: aws.String(constant.TableName)}, 5*time.Minute)
     if err != nil {
         d.log.Fatalf("Got error waiting for table to exist: %s", err)
     }
 
+    // TTL can only be enabled once the table is active
+    _, err = d.EnableTimeToLive(ctx)
+    if err != nil {
+        d.log.Fatalf("Got error enabling TTL: %s", err)
+    }
+
     return table.TableDescription, nil
 }
 
+// EnableTimeToLive enables DynamoDB TTL on the expiry of idempotency records, so that expired records are purged,
+// unless it is enabled already
+// Returns true if TTL was enabled by this call
+func (d DynamoDataAccess) EnableTimeToLive(ctx context.Context) (bool, error) {
+    output, err := d.client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        return false, err
+    }
+    if description := output.TimeToLiveDescription; description != nil {
+        switch description.TimeToLiveStatus {
+        case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+            if aws.ToString(description.AttributeName) != constant.IdempotencyExpiresAtAttributeName {
+                return false, fmt.Errorf("table %s has TTL on attribute %s, expected %s", constant.TableName,
+                    aws.ToString(description.AttributeName), constant.IdempotencyExpiresAtAttributeName)
+            }
+            return false, nil
+        }
+    }
+
+    _, err = d.client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+        TableName: aws.String(constant.TableName),
+        TimeToLiveSpecification: &types.TimeToLiveSpecification{
+            AttributeName: aws.String(constant.IdempotencyExpiresAtAttributeName),
+            Enabled:       aws.Bool(true),
+        },
+    })
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// ValidateListingTable checks that the key schema, LSI and GSIs of the existing Listing table match
+// listingTableDefinition. Extra indexes on the existing table are tolerated.
+func (d DynamoDataAccess) ValidateListingTable(ctx context.Context) error {
+    output, err := d.client.DescribeTable(
+        ctx, &dynamodb.DescribeTableInput{TableName: aws.String(constant.TableName)},
+    )
+    if err != nil {
+        return err
+    }
+    actual := output.Table
+    expected := listingTableDefinition()
+
+    actualAttributeTypes := make(map[string]types.ScalarAttributeType)
+    for _, attribute := range actual.AttributeDefinitions {
+        actualAttributeTypes[*attribute.AttributeName] = attribute.AttributeType
+    }
+    for _, attribute := range expected.AttributeDefinitions {
+        actualType, defined := actualAttributeTypes[*attribute.AttributeName]
+        if defined && actualType != attribute.AttributeType {
+            return fmt.Errorf("attribute %s of table %s has type %s, expected %s",
+                *attribute.AttributeName, constant.TableName, actualType, attribute.AttributeType)
+        }
+    }
+
+    if formatKeySchema(actual.KeySchema) != formatKeySchema(expected.KeySchema) {
+        return fmt.Errorf("table %s has key schema [%s], expected [%s]",
+            constant.TableName, formatKeySchema(actual.KeySchema), formatKeySchema(expected.KeySchema))
+    }
+
+    actualIndexes := make(map[string][]types.KeySchemaElement)
+    for _, lsi := range actual.LocalSecondaryIndexes {
+        actualIndexes[*lsi.IndexName] = lsi.KeySchema
+    }
+    for _, gsi := range actual.GlobalSecondaryIndexes {
+        actualIndexes[*gsi.IndexName] = gsi.KeySchema
+    }
+
+    expectedIndexes := make(map[string][]types.KeySchemaElement)
+    for _, lsi := range expected.LocalSecondaryIndexes {
+        expectedIndexes[*lsi.IndexName] = lsi.KeySchema
+    }
+    for _, gsi := range expected.GlobalSecondaryIndexes {
+        expectedIndexes[*gsi.IndexName] = gsi.KeySchema
+    }
+
+    for indexName, expectedKeySchema := range expectedIndexes {
+        actualKeySchema, exists := actualIndexes[indexName]
+        if !exists {
+            return fmt.Errorf("table %s is missing index %s", constant.TableName, indexName)
+        }
+        if formatKeySchema(actualKeySchema) != formatKeySchema(expectedKeySchema) {
+            return fmt.Errorf("index %s of table %s has key schema [%s], expected [%s]",
+                indexName, constant.TableName, formatKeySchema(actualKeySchema), formatKeySchema(expectedKeySchema))
+        }
+    }
+    for indexName := range actualIndexes {
+        if _, exists := expectedIndexes[indexName]; !exists {
+            d.log.Warnf("Table %s has unused index %s", constant.TableName, indexName)
+        }
+    }
+
+    return nil
+}
+
 // DeleteTable deletes the DynamoDB Listing table and all its data
-func (d DynamoDataAccess) DeleteTable() error {
-    _, err := d.client.DeleteTable(context.TODO(), &dynamodb.DeleteTableInput{
+func (d DynamoDataAccess) DeleteTable(ctx context.Context) error {
+    _, err := d.client.DeleteTable(ctx, &dynamodb.DeleteTableInput{
         TableName: aws.String(constant.TableName)})
     if err != nil {
         d.log.Errorf("Got error calling DeleteTable: %s", err)
+        return err
+    }
+
+    waiter := dynamodb.NewTableNotExistsWaiter(d.client)
+    err = waiter.Wait(ctx, &dynamodb.DescribeTableInput{
+        TableName: aws.String(constant.TableName)}, 5*time.Minute)
+    if err != nil {
+        d.log.Errorf("Got error waiting for table to be deleted: %s", err)
     }
     return err
 }
+
+// formatKeySchema renders a key schema as "Name(HASH), Name(RANGE)" for comparison and error messages
+func formatKeySchema(keySchema []types.KeySchemaElement) string {
+    elements := make([]string, len(keySchema))
+    for i, element := range keySchema {
+        elements[i] = fmt.Sprintf("%s(%s)", *element.AttributeName, element.KeyType)
+    }
+    return strings.Join(elements, ", ")
+}
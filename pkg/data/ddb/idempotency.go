@@ -0,0 +1,159 @@
+package ddb
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "strconv"
+    "time"
+)
+
+// idempotencyRecord is the record of an idempotency key, keyed by the record key under the idempotency partition.
+// CreatedAt is in epoch milliseconds, and ExpiresAt in epoch seconds so that DynamoDB TTL can purge expired records.
+type idempotencyRecord struct {
+    Key         string `dynamodbav:"Username"` // sort key
+    RequestHash string `dynamodbav:"IdempotencyRequestHash"`
+    Response    []byte `dynamodbav:"IdempotencyResponse,omitempty"`
+    CreatedAt   int64  `dynamodbav:"IdempotencyCreatedAt"`
+    ExpiresAt   int64  `dynamodbav:"IdempotencyExpiresAt"`
+}
+
+// maxIdempotencyTries bounds the tries of taking a key whose record is deleted between the put and the read
+const maxIdempotencyTries = 3
+
+// PutIdempotencyRecord stores a record holding its key, conditional on no record holding the key or on the record
+// having expired by record.CreatedAt. The record holding the key is read after a failed condition.
+// Returns the record holding the key, or nil if record was stored
+func (d DynamoDataAccess) PutIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+    item, err := marshalIdempotencyRecord(record)
+    if err != nil {
+        return nil, err
+    }
+    expr, err := expression.NewBuilder().WithCondition(
+        expression.Name(constant.ListingTablePartitionKeyName).AttributeNotExists().Or(
+            expression.Name(constant.IdempotencyExpiresAtAttributeName).LessThanEqual(expression.Value(record.CreatedAt.Unix()))),
+    ).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    for try := 1; try <= maxIdempotencyTries; try++ {
+        _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+            Item:                      item,
+            TableName:                 aws.String(constant.TableName),
+            ExpressionAttributeNames:  expr.Names(),
+            ExpressionAttributeValues: expr.Values(),
+            ConditionExpression:       expr.Condition(),
+        })
+        if err == nil {
+            return nil, nil
+        }
+        var conditionCheckFailedErr *types.ConditionalCheckFailedException
+        if !errors.As(err, &conditionCheckFailedErr) {
+            d.log.Errorf("failed to put idempotency record: %v", err)
+            return nil, err
+        }
+
+        existing, err := d.getIdempotencyRecord(ctx, record.Key)
+        if err != nil || existing != nil {
+            return existing, err
+        }
+    }
+    return nil, fmt.Errorf("idempotency record %s was deleted concurrently on every try", record.Key)
+}
+
+// getIdempotencyRecord reads the record under key
+// Returns nil if the record does not exist
+func (d DynamoDataAccess) getIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+    output, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+        Key:            buildIdempotencyKey(key),
+        TableName:      aws.String(constant.TableName),
+        ConsistentRead: aws.Bool(true),
+    })
+    if err != nil {
+        d.log.Errorf("failed to get idempotency record: %v", err)
+        return nil, err
+    }
+    if output.Item == nil {
+        return nil, nil
+    }
+
+    var record idempotencyRecord
+    err = attributevalue.UnmarshalMap(output.Item, &record)
+    if err != nil {
+        d.log.Errorf("failed to unmarshal idempotency record: %v", err)
+        return nil, err
+    }
+
+    return &model.IdempotencyRecord{
+        Key:         record.Key,
+        RequestHash: record.RequestHash,
+        Response:    record.Response,
+        CreatedAt:   time.UnixMilli(record.CreatedAt),
+        ExpiresAt:   time.Unix(record.ExpiresAt, 0),
+    }, nil
+}
+
+// CompleteIdempotencyRecord stores the response and the new expiry of a record
+func (d DynamoDataAccess) CompleteIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) error {
+    item, err := marshalIdempotencyRecord(record)
+    if err != nil {
+        return err
+    }
+
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+        Item:      item,
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to complete idempotency record: %v", err)
+    }
+    return err
+}
+
+// DeleteIdempotencyRecord releases the key of a record without a response
+func (d DynamoDataAccess) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+    _, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        Key:                      buildIdempotencyKey(key),
+        TableName:                aws.String(constant.TableName),
+        ConditionExpression:      aws.String("attribute_not_exists(#response)"),
+        ExpressionAttributeNames: map[string]string{"#response": "IdempotencyResponse"},
+    })
+    var conditionCheckFailedErr *types.ConditionalCheckFailedException
+    if err != nil && !errors.As(err, &conditionCheckFailedErr) {
+        d.log.Errorf("failed to delete idempotency record: %v", err)
+        return err
+    }
+    return nil
+}
+
+func marshalIdempotencyRecord(record model.IdempotencyRecord) (map[string]types.AttributeValue, error) {
+    item, err := attributevalue.MarshalMap(idempotencyRecord{
+        Key:         record.Key,
+        RequestHash: record.RequestHash,
+        Response:    record.Response,
+        CreatedAt:   record.CreatedAt.UnixMilli(),
+        ExpiresAt:   record.ExpiresAt.Unix(),
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+    }
+    for name, value := range buildIdempotencyKey(record.Key) {
+        item[name] = value
+    }
+    return item, nil
+}
+
+func buildIdempotencyKey(key string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.IdempotencyRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: key},
+    }
+}
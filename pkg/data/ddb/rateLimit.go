@@ -0,0 +1,126 @@
+package ddb
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "strconv"
+    "time"
+)
+
+// rateLimitRecord is the token bucket of a user and command class, keyed by the bucket key under the rate limit
+// partition. UpdatedAt is in epoch milliseconds so that refills between close commands are not rounded away.
+type rateLimitRecord struct {
+    Key       string  `dynamodbav:"Username"` // sort key
+    Tokens    float64 `dynamodbav:"RateLimitTokens"`
+    UpdatedAt int64   `dynamodbav:"RateLimitUpdatedAt"`
+    Version   int     `dynamodbav:"RateLimitVersion"`
+}
+
+// maxRateLimitTries bounds the retries of taking a token from a bucket that is updated concurrently
+const maxRateLimitTries = 5
+
+// TakeRateLimitToken takes a token from the rate limit bucket under key. The bucket is written back conditional on its
+// version, and read again if another process updated it in between.
+// Returns false if the bucket is empty, or if it is updated concurrently on every try
+func (d DynamoDataAccess) TakeRateLimitToken(ctx context.Context, key string, limit model.RateLimit, now time.Time) (bool, error) {
+    for try := 1; ; try++ {
+        bucket, err := d.getRateLimitBucket(ctx, key, limit, now)
+        if err != nil {
+            return false, err
+        }
+
+        next, allowed := bucket.Take(limit, now)
+        err = d.putRateLimitBucket(ctx, key, next, bucket.Version)
+        var conditionCheckFailedErr *types.ConditionalCheckFailedException
+        if err == nil {
+            return allowed, nil
+        }
+        if !errors.As(err, &conditionCheckFailedErr) {
+            d.log.Errorf("failed to put rate limit bucket: %v", err)
+            return false, err
+        }
+        if try == maxRateLimitTries {
+            d.log.Warnw("Rate limit bucket updated concurrently, denying", "key", key, "tries", try)
+            return false, nil
+        }
+    }
+}
+
+// getRateLimitBucket reads the bucket under key, or returns a full bucket if there is none
+func (d DynamoDataAccess) getRateLimitBucket(ctx context.Context, key string, limit model.RateLimit, now time.Time) (model.RateLimitBucket, error) {
+    output, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+        Key:            buildRateLimitKey(key),
+        TableName:      aws.String(constant.TableName),
+        ConsistentRead: aws.Bool(true),
+    })
+    if err != nil {
+        d.log.Errorf("failed to get rate limit bucket: %v", err)
+        return model.RateLimitBucket{}, err
+    }
+
+    if output.Item == nil {
+        return model.NewRateLimitBucket(limit, now), nil
+    }
+
+    var record rateLimitRecord
+    err = attributevalue.UnmarshalMap(output.Item, &record)
+    if err != nil {
+        d.log.Errorf("failed to unmarshal rate limit bucket: %v", err)
+        return model.RateLimitBucket{}, err
+    }
+
+    return model.RateLimitBucket{
+        Tokens:    record.Tokens,
+        UpdatedAt: time.UnixMilli(record.UpdatedAt),
+        Version:   record.Version,
+    }, nil
+}
+
+// putRateLimitBucket writes the bucket under key, conditional on the stored bucket still being at version read
+func (d DynamoDataAccess) putRateLimitBucket(ctx context.Context, key string, bucket model.RateLimitBucket, read int) error {
+    item, err := attributevalue.MarshalMap(rateLimitRecord{
+        Key:       key,
+        Tokens:    bucket.Tokens,
+        UpdatedAt: bucket.UpdatedAt.UnixMilli(),
+        Version:   bucket.Version,
+    })
+    if err != nil {
+        return fmt.Errorf("failed to marshal rate limit bucket: %w", err)
+    }
+    for name, value := range buildRateLimitKey(key) {
+        item[name] = value
+    }
+
+    condition := expression.Name("RateLimitVersion").Equal(expression.Value(read))
+    if read == 0 {
+        condition = expression.Name(constant.ListingTablePartitionKeyName).AttributeNotExists()
+    }
+    expr, err := expression.NewBuilder().WithCondition(condition).Build()
+    if err != nil {
+        return err
+    }
+
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+        Item:                      item,
+        TableName:                 aws.String(constant.TableName),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+        ConditionExpression:       expr.Condition(),
+    })
+    return err
+}
+
+func buildRateLimitKey(key string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.RateLimitRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: key},
+    }
+}
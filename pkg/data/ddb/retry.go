@@ -0,0 +1,268 @@
+package ddb
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "expvar"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "github.com/aws/smithy-go"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    mathrand "math/rand"
+    "time"
+)
+
+// dynamoClient is the part of the DynamoDB API used by DynamoDataAccess
+type dynamoClient interface {
+    BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+    BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+    CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+    DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+    DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error)
+    DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+    DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+    GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+    PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+    Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+    Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+    TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+    UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+    UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
+}
+
+var _ dynamoClient = (*dynamodb.Client)(nil)
+var _ dynamoClient = retryClient{}
+
+// retryMetrics counts the attempts, retries and exhausted retries of every DynamoDB operation under keys such as
+// "PutItem.retries", and is published by expvar as "ddb_requests"
+var retryMetrics = expvar.NewMap("ddb_requests")
+
+// retryClient tries the requests of a dynamoClient again, after a capped exponential backoff with full jitter, when
+// they fail for a transient reason
+type retryClient struct {
+    next        dynamoClient
+    log         *zap.SugaredLogger
+    maxAttempts int
+    baseDelay   time.Duration
+    maxDelay    time.Duration
+}
+
+func newRetryClient(next dynamoClient, log *zap.SugaredLogger) retryClient {
+    return retryClient{
+        next:        next,
+        log:         log,
+        maxAttempts: constant.DdbMaxAttempts,
+        baseDelay:   constant.DdbRetryBaseDelay,
+        maxDelay:    constant.DdbRetryMaxDelay,
+    }
+}
+
+// retry calls request until it succeeds, fails with an error for which retryable is false, or runs out of attempts
+// Returns the result of the last attempt, or the error of ctx when it ends between attempts
+func retry[T any](ctx context.Context, r retryClient, operation string, retryable func(error) bool, request func() (T, error)) (T, error) {
+    for attempt := 1; ; attempt++ {
+        retryMetrics.Add(operation+".attempts", 1)
+        output, err := request()
+        if err == nil || !retryable(err) {
+            return output, err
+        }
+        if attempt >= r.maxAttempts {
+            retryMetrics.Add(operation+".exhausted", 1)
+            r.log.Errorw("DynamoDB request failed", "operation", operation, "attempts", attempt, "error", err)
+            return output, err
+        }
+
+        delay := r.backoff(attempt)
+        retryMetrics.Add(operation+".retries", 1)
+        r.log.Warnw("Retrying DynamoDB request", "operation", operation, "attempt", attempt, "delay", delay,
+            "error", err)
+        err = sleep(ctx, delay)
+        if err != nil {
+            var zero T
+            return zero, err
+        }
+    }
+}
+
+// sleep waits for delay, or until ctx ends
+// Returns the error of ctx if it ended first
+func sleep(ctx context.Context, delay time.Duration) error {
+    timer := time.NewTimer(delay)
+    defer timer.Stop()
+    select {
+    case <-ctx.Done():
+        return ctx.Err()
+    case <-timer.C:
+        return nil
+    }
+}
+
+// backoff returns a random delay before the retry following the given attempt, of up to the base delay doubled on
+// every attempt and capped at the max delay
+func (r retryClient) backoff(attempt int) time.Duration {
+    ceiling := r.maxDelay
+    if attempt < 32 && r.baseDelay<<(attempt-1) < ceiling {
+        ceiling = r.baseDelay << (attempt - 1)
+    }
+    if ceiling <= 0 {
+        return 0
+    }
+    return time.Duration(mathrand.Int63n(int64(ceiling) + 1))
+}
+
+// isRetryable tells whether a request failed for a reason that may go away by trying again: throttling, a
+// conflicting transaction or a server error. A failed condition is final, and so is the end of the context.
+func isRetryable(err error) bool {
+    if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+        return false
+    }
+    var conditionCheckFailedErr *types.ConditionalCheckFailedException
+    if errors.As(err, &conditionCheckFailedErr) {
+        return false
+    }
+
+    // a transaction is retryable when an item conflicted or was throttled, and none of its conditions failed
+    var txCanceledErr *types.TransactionCanceledException
+    if errors.As(err, &txCanceledErr) {
+        retryable := false
+        for _, reason := range txCanceledErr.CancellationReasons {
+            switch aws.ToString(reason.Code) {
+            case "ConditionalCheckFailed":
+                return false
+            case "TransactionConflict", "ThrottlingError", "ProvisionedThroughputExceeded", "RequestLimitExceeded":
+                retryable = true
+            }
+        }
+        return retryable
+    }
+
+    if isThrottled(err) {
+        return true
+    }
+    var apiErr smithy.APIError
+    if errors.As(err, &apiErr) {
+        switch apiErr.ErrorCode() {
+        case "TransactionConflictException", "TransactionInProgressException", "InternalServerError":
+            return true
+        }
+    }
+
+    var responseErr interface{ HTTPStatusCode() int }
+    return errors.As(err, &responseErr) && responseErr.HTTPStatusCode() >= 500
+}
+
+// isThrottled tells whether a request was throttled, in which case DynamoDB rejected it without applying it
+func isThrottled(err error) bool {
+    var apiErr smithy.APIError
+    if errors.As(err, &apiErr) {
+        switch apiErr.ErrorCode() {
+        case "ProvisionedThroughputExceededException", "RequestLimitExceeded", "ThrottlingException":
+            return true
+        }
+    }
+    return false
+}
+
+// newClientRequestToken returns a random token making the attempts of a transaction idempotent
+func newClientRequestToken() string {
+    token := make([]byte, 16)
+    _, _ = rand.Read(token)
+    return hex.EncodeToString(token)
+}
+
+func (r retryClient) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+    return retry(ctx, r, "BatchGetItem", isRetryable, func() (*dynamodb.BatchGetItemOutput, error) {
+        return r.next.BatchGetItem(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+    return retry(ctx, r, "BatchWriteItem", isRetryable, func() (*dynamodb.BatchWriteItemOutput, error) {
+        return r.next.BatchWriteItem(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+    return retry(ctx, r, "CreateTable", isRetryable, func() (*dynamodb.CreateTableOutput, error) {
+        return r.next.CreateTable(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+    return retry(ctx, r, "DeleteItem", isRetryable, func() (*dynamodb.DeleteItemOutput, error) {
+        return r.next.DeleteItem(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+    return retry(ctx, r, "DeleteTable", isRetryable, func() (*dynamodb.DeleteTableOutput, error) {
+        return r.next.DeleteTable(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+    return retry(ctx, r, "DescribeTable", isRetryable, func() (*dynamodb.DescribeTableOutput, error) {
+        return r.next.DescribeTable(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+    return retry(ctx, r, "DescribeTimeToLive", isRetryable, func() (*dynamodb.DescribeTimeToLiveOutput, error) {
+        return r.next.DescribeTimeToLive(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+    return retry(ctx, r, "GetItem", isRetryable, func() (*dynamodb.GetItemOutput, error) {
+        return r.next.GetItem(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+    return retry(ctx, r, "PutItem", isRetryable, func() (*dynamodb.PutItemOutput, error) {
+        return r.next.PutItem(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+    return retry(ctx, r, "Query", isRetryable, func() (*dynamodb.QueryOutput, error) {
+        return r.next.Query(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+    return retry(ctx, r, "Scan", isRetryable, func() (*dynamodb.ScanOutput, error) {
+        return r.next.Scan(ctx, params, optFns...)
+    })
+}
+
+// TransactWriteItems gives every attempt of a transaction the same client request token, so that a transaction
+// applied before its response was lost is not applied twice
+func (r retryClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+    if params.ClientRequestToken == nil {
+        input := *params
+        input.ClientRequestToken = aws.String(newClientRequestToken())
+        params = &input
+    }
+    return retry(ctx, r, "TransactWriteItems", isRetryable, func() (*dynamodb.TransactWriteItemsOutput, error) {
+        return r.next.TransactWriteItems(ctx, params, optFns...)
+    })
+}
+
+// UpdateItem is only tried again when throttled, since an update adding to a counter may have been applied before a
+// server error or a conflict, and would be applied twice
+func (r retryClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+    return retry(ctx, r, "UpdateItem", isThrottled, func() (*dynamodb.UpdateItemOutput, error) {
+        return r.next.UpdateItem(ctx, params, optFns...)
+    })
+}
+
+func (r retryClient) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+    return retry(ctx, r, "UpdateTimeToLive", isRetryable, func() (*dynamodb.UpdateTimeToLiveOutput, error) {
+        return r.next.UpdateTimeToLive(ctx, params, optFns...)
+    })
+}
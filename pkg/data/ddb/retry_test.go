@@ -0,0 +1,347 @@
+package ddb
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "expvar"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    smithyhttp "github.com/aws/smithy-go/transport/http"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/api/rest"
+    "marketplace-platform/pkg/data/memory"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/service"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+)
+
+// faultClient fails the requests of an operation with the faults queued for it before passing them to next, which
+// answers with empty outputs when nil
+type faultClient struct {
+    dynamoClient
+    mu     sync.Mutex
+    faults map[string][]error
+    calls  map[string]int
+    tokens []string // the client request tokens of the transactions
+}
+
+func newFaultClient(next dynamoClient) *faultClient {
+    return &faultClient{dynamoClient: next, faults: make(map[string][]error), calls: make(map[string]int)}
+}
+
+// inject queues faults for the next requests of operation
+func (f *faultClient) inject(operation string, faults ...error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.faults[operation] = append(f.faults[operation], faults...)
+}
+
+// fault counts a request of operation and returns its queued fault, if any
+func (f *faultClient) fault(operation string) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.calls[operation]++
+    faults := f.faults[operation]
+    if len(faults) == 0 {
+        return nil
+    }
+    f.faults[operation] = faults[1:]
+    return faults[0]
+}
+
+func (f *faultClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+    if err := f.fault("GetItem"); err != nil {
+        return nil, err
+    }
+    if f.dynamoClient == nil {
+        return &dynamodb.GetItemOutput{}, nil
+    }
+    return f.dynamoClient.GetItem(ctx, params, optFns...)
+}
+
+func (f *faultClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+    if err := f.fault("PutItem"); err != nil {
+        return nil, err
+    }
+    if f.dynamoClient == nil {
+        return &dynamodb.PutItemOutput{}, nil
+    }
+    return f.dynamoClient.PutItem(ctx, params, optFns...)
+}
+
+func (f *faultClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+    f.mu.Lock()
+    f.tokens = append(f.tokens, aws.ToString(params.ClientRequestToken))
+    f.mu.Unlock()
+    if err := f.fault("TransactWriteItems"); err != nil {
+        return nil, err
+    }
+    if f.dynamoClient == nil {
+        return &dynamodb.TransactWriteItemsOutput{}, nil
+    }
+    return f.dynamoClient.TransactWriteItems(ctx, params, optFns...)
+}
+
+func (f *faultClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+    if err := f.fault("UpdateItem"); err != nil {
+        return nil, err
+    }
+    if f.dynamoClient == nil {
+        return &dynamodb.UpdateItemOutput{}, nil
+    }
+    return f.dynamoClient.UpdateItem(ctx, params, optFns...)
+}
+
+func throttlingFault() error {
+    return &types.ProvisionedThroughputExceededException{Message: aws.String("throughput exceeded")}
+}
+
+func transactionFault(codes ...string) error {
+    reasons := make([]types.CancellationReason, len(codes))
+    for i, code := range codes {
+        reasons[i] = types.CancellationReason{Code: aws.String(code)}
+    }
+    return &types.TransactionCanceledException{Message: aws.String("transaction cancelled"), CancellationReasons: reasons}
+}
+
+func serverFault(status int) error {
+    return &smithyhttp.ResponseError{
+        Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+        Err:      errors.New(http.StatusText(status)),
+    }
+}
+
+// newTestRetryClient retries the requests of next without waiting long between attempts
+func newTestRetryClient(next dynamoClient) retryClient {
+    return retryClient{next: next, log: zap.NewNop().Sugar(), maxAttempts: 4, baseDelay: time.Millisecond, maxDelay: 5 * time.Millisecond}
+}
+
+func retryMetric(key string) int64 {
+    metric, ok := retryMetrics.Get(key).(*expvar.Int)
+    if !ok {
+        return 0
+    }
+    return metric.Value()
+}
+
+func TestRetryTransientFaults(t *testing.T) {
+    ctx := context.Background()
+
+    for name, fault := range map[string]error{
+        "throttling":           throttlingFault(),
+        "transaction conflict": transactionFault("None", "TransactionConflict"),
+        "throttled item":       transactionFault("ThrottlingError", "None"),
+        "server error":         serverFault(http.StatusServiceUnavailable),
+    } {
+        faults := newFaultClient(nil)
+        faults.inject("TransactWriteItems", fault, fault)
+        retries := retryMetric("TransactWriteItems.retries")
+
+        _, err := newTestRetryClient(faults).TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{})
+        if err != nil {
+            t.Fatalf("expected %s to be retried, got %v", name, err)
+        }
+        if faults.calls["TransactWriteItems"] != 3 {
+            t.Fatalf("expected %s to be retried twice, got %d attempts", name, faults.calls["TransactWriteItems"])
+        }
+        if retryMetric("TransactWriteItems.retries")-retries != 2 {
+            t.Fatalf("expected %s to count 2 retries, got %d", name, retryMetric("TransactWriteItems.retries")-retries)
+        }
+
+        // every attempt of the transaction carries the same token, so that it applies at most once
+        if faults.tokens[0] == "" || faults.tokens[1] != faults.tokens[0] || faults.tokens[2] != faults.tokens[0] {
+            t.Fatalf("expected the attempts of a transaction to share their token, got %q", faults.tokens)
+        }
+    }
+}
+
+func TestRetryTerminalFaults(t *testing.T) {
+    ctx := context.Background()
+
+    for name, fault := range map[string]error{
+        "failed condition":             &types.ConditionalCheckFailedException{Message: aws.String("condition failed")},
+        "failed transaction condition": transactionFault("TransactionConflict", "ConditionalCheckFailed"),
+        "client error":                 serverFault(http.StatusBadRequest),
+        "validation":                   &types.ResourceNotFoundException{Message: aws.String("table not found")},
+    } {
+        faults := newFaultClient(nil)
+        faults.inject("PutItem", fault, fault)
+
+        _, err := newTestRetryClient(faults).PutItem(ctx, &dynamodb.PutItemInput{})
+        if err != fault {
+            t.Fatalf("expected %s to be returned, got %v", name, err)
+        }
+        if faults.calls["PutItem"] != 1 {
+            t.Fatalf("expected %s not to be retried, got %d attempts", name, faults.calls["PutItem"])
+        }
+    }
+}
+
+func TestRetryUpdates(t *testing.T) {
+    ctx := context.Background()
+
+    // a throttled update was not applied, so it is tried again
+    faults := newFaultClient(nil)
+    faults.inject("UpdateItem", throttlingFault())
+    _, err := newTestRetryClient(faults).UpdateItem(ctx, &dynamodb.UpdateItemInput{})
+    if err != nil || faults.calls["UpdateItem"] != 2 {
+        t.Fatalf("expected the throttled update to be retried, got %v after %d attempts", err, faults.calls["UpdateItem"])
+    }
+
+    // an update may have been applied before a server error or a conflict, and adding to a counter twice is wrong
+    for name, fault := range map[string]error{
+        "server error":         serverFault(http.StatusInternalServerError),
+        "transaction conflict": &types.TransactionConflictException{Message: aws.String("transaction conflict")},
+    } {
+        faults := newFaultClient(nil)
+        faults.inject("UpdateItem", fault)
+
+        _, err := newTestRetryClient(faults).UpdateItem(ctx, &dynamodb.UpdateItemInput{})
+        if err != fault {
+            t.Fatalf("expected %s to be returned, got %v", name, err)
+        }
+        if faults.calls["UpdateItem"] != 1 {
+            t.Fatalf("expected %s not to be retried, got %d attempts", name, faults.calls["UpdateItem"])
+        }
+    }
+}
+
+func TestRetryExhausted(t *testing.T) {
+    ctx := context.Background()
+    faults := newFaultClient(nil)
+    faults.inject("GetItem", throttlingFault(), throttlingFault(), throttlingFault(), throttlingFault(), throttlingFault())
+    exhausted := retryMetric("GetItem.exhausted")
+
+    _, err := newTestRetryClient(faults).GetItem(ctx, &dynamodb.GetItemInput{})
+    var throughputErr *types.ProvisionedThroughputExceededException
+    if !errors.As(err, &throughputErr) {
+        t.Fatalf("expected the last fault to be returned, got %v", err)
+    }
+    if faults.calls["GetItem"] != 4 {
+        t.Fatalf("expected 4 attempts, got %d", faults.calls["GetItem"])
+    }
+    if retryMetric("GetItem.exhausted")-exhausted != 1 {
+        t.Fatalf("expected the exhausted retries to be counted")
+    }
+}
+
+func TestRetryMetricsServed(t *testing.T) {
+    faults := newFaultClient(nil)
+    faults.inject("GetItem", throttlingFault())
+    _, err := newTestRetryClient(faults).GetItem(context.Background(), &dynamodb.GetItemInput{})
+    if err != nil {
+        t.Fatalf("expected the throttled request to be retried, got %v", err)
+    }
+
+    log := zap.NewNop().Sugar()
+    server := httptest.NewServer(rest.NewServer(service.NewMarketplace(memory.NewMemoryDataAccess(log), log), log))
+    defer server.Close()
+    response, err := http.Get(server.URL + "/debug/vars")
+    if err != nil {
+        t.Fatalf("request failed: %v", err)
+    }
+    defer response.Body.Close()
+    var vars struct {
+        DdbRequests map[string]int64 `json:"ddb_requests"`
+    }
+    err = json.NewDecoder(response.Body).Decode(&vars)
+    if err != nil {
+        t.Fatalf("could not decode the metrics: %v", err)
+    }
+    if vars.DdbRequests["GetItem.retries"] < 1 || vars.DdbRequests["GetItem.retries"] != retryMetric("GetItem.retries") {
+        t.Fatalf("expected the retries to be served, got %v", vars.DdbRequests)
+    }
+}
+
+func TestRetryCancelled(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancel()
+    faults := newFaultClient(nil)
+    faults.inject("GetItem", throttlingFault(), throttlingFault())
+    client := newTestRetryClient(faults)
+    client.baseDelay = time.Hour
+    client.maxDelay = time.Hour
+
+    _, err := client.GetItem(ctx, &dynamodb.GetItemInput{})
+    if !errors.Is(err, context.DeadlineExceeded) || faults.calls["GetItem"] > 2 {
+        t.Fatalf("expected the retries to end with the context, got %v after %d attempts", err, faults.calls["GetItem"])
+    }
+}
+
+// unprocessedClient leaves every item of its batch writes unprocessed
+type unprocessedClient struct {
+    dynamoClient
+}
+
+func (c unprocessedClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+    return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+}
+
+func TestUnprocessedItemsCancelled(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancel()
+    store := DynamoDataAccess{client: unprocessedClient{}, log: zap.NewNop().Sugar()}
+
+    started := time.Now()
+    err := store.batchWrite(ctx, []types.WriteRequest{{DeleteRequest: &types.DeleteRequest{}}})
+    if !errors.Is(err, context.DeadlineExceeded) || time.Since(started) > 500*time.Millisecond {
+        t.Fatalf("expected the retries of unprocessed items to end with the context, got %v after %v", err,
+            time.Since(started))
+    }
+}
+
+func TestBackoff(t *testing.T) {
+    client := retryClient{baseDelay: 10 * time.Millisecond, maxDelay: 100 * time.Millisecond}
+    for attempt, ceiling := range map[int]time.Duration{
+        1:  10 * time.Millisecond,
+        2:  20 * time.Millisecond,
+        4:  80 * time.Millisecond,
+        5:  100 * time.Millisecond,
+        64: 100 * time.Millisecond,
+    } {
+        for i := 0; i < 100; i++ {
+            delay := client.backoff(attempt)
+            if delay < 0 || delay > ceiling {
+                t.Fatalf("expected the delay after attempt %d to be at most %v, got %v", attempt, ceiling, delay)
+            }
+        }
+    }
+}
+
+func TestRetryListingTransactions(t *testing.T) {
+    ctx := context.Background()
+    store := newTestStore(t)
+    faults := newFaultClient(store.client)
+    store.client = newTestRetryClient(faults)
+
+    _, err := store.PutCategory(ctx, "Electronics")
+    if err != nil {
+        t.Fatalf("could not put category: %v", err)
+    }
+    _, err = store.PutUser(ctx, "user1", "hash")
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+
+    faults.inject("TransactWriteItems", transactionFault("TransactionConflict", "None", "None"), throttlingFault())
+    listing, err := store.PutListing(ctx, "user1", "Phone", "brand new", 1000, "Electronics", enum.ListingStatusActive)
+    if err != nil || listing == nil {
+        t.Fatalf("expected the listing to be created despite the faults, got %v, %v", listing, err)
+    }
+
+    faults.inject("TransactWriteItems", transactionFault("TransactionConflict", "None"), serverFault(http.StatusInternalServerError))
+    err = store.DeleteListing(ctx, "user1", listing.ListingId)
+    if err != nil {
+        t.Fatalf("expected the listing to be deleted despite the faults, got %v", err)
+    }
+    deleted, err := store.GetListing(ctx, listing.ListingId)
+    if err != nil || deleted != nil {
+        t.Fatalf("expected the listing to be deleted, got %v, %v", deleted, err)
+    }
+}
@@ -0,0 +1,426 @@
+package ddb
+
+import (
+    "context"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/search"
+    "strconv"
+    "time"
+)
+
+// searchTermRecord is an entry of the inverted index: a distinct term of a listing, keyed by "<term>#<listingId>" under
+// the search partition. The listing key is kept so that matching listings can be read in batches.
+type searchTermRecord struct {
+    ListingId int    `dynamodbav:"SearchListingId"`
+    Username  string `dynamodbav:"SearchUsername"`
+}
+
+// searchCorpusRecord holds the number of indexed listings and their total number of terms
+type searchCorpusRecord struct {
+    Documents   int `dynamodbav:"SearchDocuments"`
+    TotalLength int `dynamodbav:"SearchTotalLength"`
+}
+
+const (
+    batchWriteSize      = 25
+    batchGetSize        = 100
+    maxUnprocessedTries = 5
+)
+
+// SearchListings retrieves the active listings matching the query text, ranked by BM25 relevance
+func (d DynamoDataAccess) SearchListings(ctx context.Context, query model.SearchQuery) ([]model.Listing, error) {
+    queryTerms := search.UniqueTerms(search.Analyze(query.Text))
+    if len(queryTerms) == 0 {
+        return nil, nil
+    }
+
+    corpus, err := d.getSearchCorpus(ctx)
+    if err != nil {
+        return nil, err
+    }
+    corpus.Frequencies = make(map[string]int, len(queryTerms))
+    candidates := make(map[int]string)
+    for _, term := range queryTerms {
+        records, err := d.getSearchTermRecords(ctx, term)
+        if err != nil {
+            return nil, err
+        }
+        corpus.Frequencies[term] = len(records)
+        for _, record := range records {
+            candidates[record.ListingId] = record.Username
+        }
+    }
+
+    candidateListings, err := d.batchGetListings(ctx, candidates)
+    if err != nil {
+        return nil, err
+    }
+    listings := make(map[int]model.Listing)
+    var documents []search.Document
+    for _, listing := range candidateListings {
+        if query.Matches(listing) {
+            listings[listing.ListingId] = listing
+            documents = append(documents, search.Document{ListingId: listing.ListingId, Terms: search.DocumentTerms(listing.Title, listing.Description)})
+        }
+    }
+
+    results := search.Rank(queryTerms, documents, corpus)
+    if query.Limit > 0 && len(results) > query.Limit {
+        results = results[:query.Limit]
+    }
+    ranked := make([]model.Listing, 0, len(results))
+    for _, result := range results {
+        ranked = append(ranked, listings[result.ListingId])
+    }
+    return ranked, nil
+}
+
+// RebuildSearchIndex deletes every search record and indexes every listing in the table
+// Listings written while the index is rebuilt may be missed, so it is meant to run before serving traffic.
+func (d DynamoDataAccess) RebuildSearchIndex(ctx context.Context) (int, error) {
+    var deletes []types.WriteRequest
+    err := d.queryPartition(ctx, constant.SearchRecordPartitionKey, func(item map[string]types.AttributeValue) error {
+        deletes = append(deletes, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{
+            constant.ListingTablePartitionKeyName: item[constant.ListingTablePartitionKeyName],
+            constant.ListingTableSortKeyName:      item[constant.ListingTableSortKeyName],
+        }}})
+        return nil
+    })
+    if err != nil {
+        d.log.Errorf("failed to read search records: %v", err)
+        return 0, err
+    }
+    err = d.batchWrite(ctx, deletes)
+    if err != nil {
+        d.log.Errorf("failed to delete search records: %v", err)
+        return 0, err
+    }
+
+    expr, err := expression.NewBuilder().WithFilter(
+        expression.Name(constant.ListingTablePartitionKeyName).GreaterThanEqual(expression.Value(constant.FirstListingId))).Build()
+    if err != nil {
+        return 0, err
+    }
+    var puts []types.WriteRequest
+    var corpus searchCorpusRecord
+    paginator := dynamodb.NewScanPaginator(d.client, &dynamodb.ScanInput{
+        TableName:                 aws.String(constant.TableName),
+        FilterExpression:          expr.Filter(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            d.log.Errorf("failed to scan listings: %v", err)
+            return 0, err
+        }
+        var listings []model.Listing
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &listings)
+        if err != nil {
+            return 0, err
+        }
+        for _, listing := range listings {
+            terms := search.DocumentTerms(listing.Title, listing.Description)
+            requests, err := buildSearchTermPuts(listing, search.UniqueTerms(terms))
+            if err != nil {
+                return 0, err
+            }
+            puts = append(puts, requests...)
+            corpus.Documents++
+            corpus.TotalLength += len(terms)
+        }
+    }
+
+    err = d.batchWrite(ctx, puts)
+    if err != nil {
+        d.log.Errorf("failed to write search records: %v", err)
+        return 0, err
+    }
+    item, err := attributevalue.MarshalMap(corpus)
+    if err != nil {
+        return 0, err
+    }
+    for name, value := range buildSearchRecordKey(constant.SearchCorpusRecordSortKey) {
+        item[name] = value
+    }
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(constant.TableName), Item: item})
+    if err != nil {
+        d.log.Errorf("failed to write search corpus: %v", err)
+        return 0, err
+    }
+
+    return corpus.Documents, nil
+}
+
+// updateSearchIndex replaces the search terms of the previous version of a listing with those of the current one. A
+// nil version stands for a listing that does not exist. The index is written after the listing, outside of its
+// transaction, because a listing can have more terms than a transaction can hold. A failure leaves the index out of
+// date until RebuildSearchIndex runs, so it is logged instead of failing the write of the listing.
+func (d DynamoDataAccess) updateSearchIndex(ctx context.Context, previous *model.Listing, current *model.Listing) {
+    var previousTerms, currentTerms []string
+    var listingId int
+    if previous != nil {
+        previousTerms = search.DocumentTerms(previous.Title, previous.Description)
+        listingId = previous.ListingId
+    }
+    if current != nil {
+        currentTerms = search.DocumentTerms(current.Title, current.Description)
+        listingId = current.ListingId
+    }
+
+    err := d.writeSearchTerms(ctx, previous, previousTerms, current, currentTerms)
+    if err != nil {
+        d.log.Errorf("search index is out of date for listing %d, rebuild it with -reindex: %v", listingId, err)
+    }
+}
+
+func (d DynamoDataAccess) writeSearchTerms(ctx context.Context, previous *model.Listing, previousTerms []string, current *model.Listing, currentTerms []string) error {
+    previousSet := make(map[string]bool)
+    for _, term := range previousTerms {
+        previousSet[term] = true
+    }
+    currentSet := make(map[string]bool)
+    for _, term := range currentTerms {
+        currentSet[term] = true
+    }
+
+    var requests []types.WriteRequest
+    for _, term := range search.UniqueTerms(previousTerms) {
+        if !currentSet[term] {
+            requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{
+                Key: buildSearchRecordKey(searchTermSortKey(term, previous.ListingId)),
+            }})
+        }
+    }
+    if current != nil {
+        var added []string
+        for _, term := range search.UniqueTerms(currentTerms) {
+            if !previousSet[term] {
+                added = append(added, term)
+            }
+        }
+        puts, err := buildSearchTermPuts(*current, added)
+        if err != nil {
+            return err
+        }
+        requests = append(requests, puts...)
+    }
+    err := d.batchWrite(ctx, requests)
+    if err != nil {
+        return err
+    }
+
+    documentsDelta := 0
+    if previous == nil {
+        documentsDelta++
+    }
+    if current == nil {
+        documentsDelta--
+    }
+    lengthDelta := len(currentTerms) - len(previousTerms)
+    if documentsDelta == 0 && lengthDelta == 0 {
+        return nil
+    }
+
+    expr, err := expression.NewBuilder().WithUpdate(expression.
+        Add(expression.Name("SearchDocuments"), expression.Value(documentsDelta)).
+        Add(expression.Name("SearchTotalLength"), expression.Value(lengthDelta))).Build()
+    if err != nil {
+        return err
+    }
+    _, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+        TableName:                 aws.String(constant.TableName),
+        Key:                       buildSearchRecordKey(constant.SearchCorpusRecordSortKey),
+        UpdateExpression:          expr.Update(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+    })
+    return err
+}
+
+// getSearchCorpus reads the number of indexed listings and their total number of terms
+func (d DynamoDataAccess) getSearchCorpus(ctx context.Context) (search.Corpus, error) {
+    output, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+        TableName: aws.String(constant.TableName),
+        Key:       buildSearchRecordKey(constant.SearchCorpusRecordSortKey),
+    })
+    if err != nil {
+        d.log.Errorf("failed to get search corpus: %v", err)
+        return search.Corpus{}, err
+    }
+
+    var record searchCorpusRecord
+    err = attributevalue.UnmarshalMap(output.Item, &record)
+    if err != nil {
+        return search.Corpus{}, err
+    }
+    return search.Corpus{Documents: record.Documents, TotalLength: record.TotalLength}, nil
+}
+
+// getSearchTermRecords reads the index entries of a term
+func (d DynamoDataAccess) getSearchTermRecords(ctx context.Context, term string) ([]searchTermRecord, error) {
+    expr, err := expression.NewBuilder().WithKeyCondition(
+        expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(constant.SearchRecordPartitionKey)).
+            And(expression.Key(constant.ListingTableSortKeyName).BeginsWith(term + "#"))).Build()
+    if err != nil {
+        return nil, err
+    }
+
+    var records []searchTermRecord
+    paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+        TableName:                 aws.String(constant.TableName),
+        KeyConditionExpression:    expr.KeyCondition(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            d.log.Errorf("failed to query search term %s: %v", term, err)
+            return nil, err
+        }
+        var page []searchTermRecord
+        err = attributevalue.UnmarshalListOfMaps(output.Items, &page)
+        if err != nil {
+            return nil, err
+        }
+        records = append(records, page...)
+    }
+    return records, nil
+}
+
+// batchGetListings reads listings by listing ID and owner, skipping listings that no longer exist
+func (d DynamoDataAccess) batchGetListings(ctx context.Context, owners map[int]string) ([]model.Listing, error) {
+    var keys []map[string]types.AttributeValue
+    for listingId, username := range owners {
+        keys = append(keys, map[string]types.AttributeValue{
+            constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(listingId)},
+            constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: username},
+        })
+    }
+
+    var listings []model.Listing
+    for start := 0; start < len(keys); start += batchGetSize {
+        end := start + batchGetSize
+        if end > len(keys) {
+            end = len(keys)
+        }
+        requestItems := map[string]types.KeysAndAttributes{constant.TableName: {Keys: keys[start:end]}}
+        for try := 0; len(requestItems) > 0; try++ {
+            if try == maxUnprocessedTries {
+                return nil, fmt.Errorf("listings still unprocessed after %d tries", try)
+            }
+            err := sleep(ctx, time.Duration(try*try)*50*time.Millisecond)
+            if err != nil {
+                return nil, err
+            }
+
+            output, err := d.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+            if err != nil {
+                d.log.Errorf("failed to batch get listings: %v", err)
+                return nil, err
+            }
+            var page []model.Listing
+            err = attributevalue.UnmarshalListOfMaps(output.Responses[constant.TableName], &page)
+            if err != nil {
+                return nil, err
+            }
+            listings = append(listings, page...)
+            requestItems = output.UnprocessedKeys
+        }
+    }
+    return listings, nil
+}
+
+// batchWrite writes requests in batches, retrying unprocessed items with a growing delay
+func (d DynamoDataAccess) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+    for start := 0; start < len(requests); start += batchWriteSize {
+        end := start + batchWriteSize
+        if end > len(requests) {
+            end = len(requests)
+        }
+        requestItems := map[string][]types.WriteRequest{constant.TableName: requests[start:end]}
+        for try := 0; len(requestItems) > 0; try++ {
+            if try == maxUnprocessedTries {
+                return fmt.Errorf("write requests still unprocessed after %d tries", try)
+            }
+            err := sleep(ctx, time.Duration(try*try)*50*time.Millisecond)
+            if err != nil {
+                return err
+            }
+
+            output, err := d.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requestItems})
+            if err != nil {
+                return err
+            }
+            requestItems = output.UnprocessedItems
+        }
+    }
+    return nil
+}
+
+// queryPartition calls handle with every item of a partition
+func (d DynamoDataAccess) queryPartition(ctx context.Context, partitionKey int, handle func(item map[string]types.AttributeValue) error) error {
+    expr, err := expression.NewBuilder().WithKeyCondition(
+        expression.Key(constant.ListingTablePartitionKeyName).Equal(expression.Value(partitionKey))).Build()
+    if err != nil {
+        return err
+    }
+
+    paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+        TableName:                 aws.String(constant.TableName),
+        KeyConditionExpression:    expr.KeyCondition(),
+        ExpressionAttributeNames:  expr.Names(),
+        ExpressionAttributeValues: expr.Values(),
+    })
+    for paginator.HasMorePages() {
+        output, err := paginator.NextPage(ctx)
+        if err != nil {
+            return err
+        }
+        for _, item := range output.Items {
+            err = handle(item)
+            if err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// buildSearchTermPuts builds the index entries of the given terms of a listing
+func buildSearchTermPuts(listing model.Listing, terms []string) ([]types.WriteRequest, error) {
+    requests := make([]types.WriteRequest, 0, len(terms))
+    for _, term := range terms {
+        item, err := attributevalue.MarshalMap(searchTermRecord{ListingId: listing.ListingId, Username: listing.Username})
+        if err != nil {
+            return nil, err
+        }
+        for name, value := range buildSearchRecordKey(searchTermSortKey(term, listing.ListingId)) {
+            item[name] = value
+        }
+        requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+    }
+    return requests, nil
+}
+
+// buildSearchRecordKey builds the key of a record in the search partition
+func buildSearchRecordKey(sortKey string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.SearchRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: sortKey},
+    }
+}
+
+// searchTermSortKey is the sort key of the index entry of a term of a listing. Terms never contain "#".
+func searchTermSortKey(term string, listingId int) string {
+    return term + "#" + strconv.Itoa(listingId)
+}
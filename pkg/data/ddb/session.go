@@ -0,0 +1,102 @@
+package ddb
+
+import (
+    "context"
+    "fmt"
+    "github.com/aws/aws-sdk-go-v2/aws"
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "strconv"
+    "time"
+)
+
+// sessionRecord is a login session, keyed by the hash of its token under the session partition. Times are in Unix
+// seconds, and are named apart from the listing attributes so that sessions stay out of the listing indexes.
+type sessionRecord struct {
+    TokenHash string `dynamodbav:"Username"` // sort key
+    Username  string `dynamodbav:"SessionUsername"`
+    CreatedAt int64  `dynamodbav:"SessionCreatedAt"`
+    ExpiresAt int64  `dynamodbav:"SessionExpiresAt"`
+}
+
+// PutSession stores a session
+func (d DynamoDataAccess) PutSession(ctx context.Context, session model.Session) error {
+    item, err := attributevalue.MarshalMap(sessionRecord{
+        TokenHash: session.TokenHash,
+        Username:  session.Username,
+        CreatedAt: session.CreatedAt.Unix(),
+        ExpiresAt: session.ExpiresAt.Unix(),
+    })
+    if err != nil {
+        return fmt.Errorf("failed to marshal session: %w", err)
+    }
+    for name, value := range buildSessionKey(session.TokenHash) {
+        item[name] = value
+    }
+
+    _, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+        Item:      item,
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to put session: %v", err)
+        return err
+    }
+
+    return nil
+}
+
+// GetSession retrieves a session by the hash of its token
+// Returns nil if the session does not exist
+func (d DynamoDataAccess) GetSession(ctx context.Context, tokenHash string) (*model.Session, error) {
+    output, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+        Key:       buildSessionKey(tokenHash),
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to get session: %v", err)
+        return nil, err
+    }
+
+    if output.Item == nil {
+        return nil, nil
+    }
+
+    var record sessionRecord
+    err = attributevalue.UnmarshalMap(output.Item, &record)
+    if err != nil {
+        d.log.Errorf("failed to unmarshal session: %v", err)
+        return nil, err
+    }
+
+    return &model.Session{
+        TokenHash: record.TokenHash,
+        Username:  record.Username,
+        CreatedAt: time.Unix(record.CreatedAt, 0),
+        ExpiresAt: time.Unix(record.ExpiresAt, 0),
+    }, nil
+}
+
+// DeleteSession revokes a session by the hash of its token
+func (d DynamoDataAccess) DeleteSession(ctx context.Context, tokenHash string) error {
+    _, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+        Key:       buildSessionKey(tokenHash),
+        TableName: aws.String(constant.TableName),
+    })
+    if err != nil {
+        d.log.Errorf("failed to delete session: %v", err)
+        return err
+    }
+
+    return nil
+}
+
+func buildSessionKey(tokenHash string) map[string]types.AttributeValue {
+    return map[string]types.AttributeValue{
+        constant.ListingTablePartitionKeyName: &types.AttributeValueMemberN{Value: strconv.Itoa(constant.SessionRecordPartitionKey)},
+        constant.ListingTableSortKeyName:      &types.AttributeValueMemberS{Value: tokenHash},
+    }
+}
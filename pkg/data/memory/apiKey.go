@@ -0,0 +1,56 @@
+package memory
+
+import (
+    "context"
+    "marketplace-platform/pkg/data/model"
+)
+
+// PutApiKey stores an API key
+func (m *MemoryDataAccess) PutApiKey(ctx context.Context, key model.ApiKey) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.apiKeys[key.KeyId] = key
+
+    return nil
+}
+
+// GetApiKey retrieves an API key by its ID
+// Returns nil if the key does not exist
+func (m *MemoryDataAccess) GetApiKey(ctx context.Context, keyId string) (*model.ApiKey, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    key, exists := m.apiKeys[keyId]
+    if !exists {
+        return nil, nil
+    }
+
+    return &key, nil
+}
+
+// GetApiKeys retrieves the API keys of a user, oldest first
+func (m *MemoryDataAccess) GetApiKeys(ctx context.Context, username string) ([]model.ApiKey, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    keys := []model.ApiKey{}
+    for _, key := range m.apiKeys {
+        if model.CanonicalKey(key.Username) == model.CanonicalKey(username) {
+            keys = append(keys, key)
+        }
+    }
+    model.SortApiKeys(keys)
+
+    return keys, nil
+}
+
+// DeleteApiKey revokes an API key by its ID
+func (m *MemoryDataAccess) DeleteApiKey(ctx context.Context, keyId string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.apiKeys, keyId)
+
+    return nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+    "context"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "sort"
+)
+
+// PutCategory adds an active category to the catalog
+// Returns nil if the category already exists
+func (m *MemoryDataAccess) PutCategory(ctx context.Context, name string) (*model.Category, error) {
+    category, err := model.NewCategory(name)
+    if err != nil {
+        return nil, err
+    }
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, exists := m.categories[category.Key()]; exists {
+        return nil, nil
+    }
+    category.Name, err = model.NewCategoryPath(name, m.lookupCategory)
+    if err != nil {
+        return nil, err
+    }
+
+    m.categories[category.Key()] = category
+
+    return &category, nil
+}
+
+// GetCategories retrieves the catalog sorted by category name
+func (m *MemoryDataAccess) GetCategories(ctx context.Context) ([]model.Category, error) {
+    m.mu.RLock()
+    categories := make([]model.Category, 0, len(m.categories))
+    for _, category := range m.categories {
+        categories = append(categories, category)
+    }
+    m.mu.RUnlock()
+
+    sort.Slice(categories, func(i, j int) bool {
+        return categories[i].Name < categories[j].Name
+    })
+
+    return categories, nil
+}
+
+// LookupCategory retrieves the catalog entry of a category, whatever its case
+// Returns nil if the category is not in the catalog
+func (m *MemoryDataAccess) LookupCategory(ctx context.Context, name string) (*model.Category, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    return m.lookupCategory(name)
+}
+
+// RetireCategory marks a category as retired
+func (m *MemoryDataAccess) RetireCategory(ctx context.Context, name string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := model.CanonicalKey(name)
+    category, exists := m.categories[key]
+    if !exists {
+        return exception.NewCategoryDoesNotExistException(fmt.Sprintf("category '%s' does not exist", name), nil)
+    }
+
+    category.Status = enum.CategoryStatusRetired
+    m.categories[key] = category
+
+    return nil
+}
+
+// RenameCategory renames a category and its subcategories, moving their listings and counts
+func (m *MemoryDataAccess) RenameCategory(ctx context.Context, from string, to string) error {
+    return m.moveCategory(from, to, false)
+}
+
+// MergeCategory moves the subtree of a category into another category
+func (m *MemoryDataAccess) MergeCategory(ctx context.Context, from string, to string) error {
+    return m.moveCategory(from, to, true)
+}
+
+// moveCategory moves the catalog entries, listings and counts of the subtree of from to to under the write lock.
+// Entries that already exist under to keep their status.
+func (m *MemoryDataAccess) moveCategory(from string, to string, merge bool) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    entry, err := model.RequireCategory(from, m.lookupCategory)
+    if err != nil {
+        return err
+    }
+    from = entry.Name
+
+    var subtree []model.Category
+    for _, category := range m.categories {
+        if model.IsInCategory(category.Name, from) {
+            subtree = append(subtree, category)
+        }
+    }
+    to, err = model.CheckCategoryMove(from, to, merge, subtree, m.lookupCategory)
+    if err != nil {
+        return err
+    }
+
+    for _, category := range subtree {
+        delete(m.categories, category.Key())
+    }
+    for _, category := range subtree {
+        category.Name = model.MoveCategoryPath(category.Name, from, to)
+        if _, exists := m.categories[category.Key()]; !exists {
+            m.categories[category.Key()] = category
+        }
+    }
+
+    for listingId, listing := range m.listings {
+        if !model.IsInCategory(listing.Category, from) {
+            continue
+        }
+        moved := listing
+        moved.Category = model.MoveCategoryPath(listing.Category, from, to)
+        moved.Version++
+        m.listings[listingId] = moved
+        if listing.IsActive() {
+            m.addCategoryCount(listing.Category, -1)
+            m.addCategoryCount(moved.Category, 1)
+        }
+    }
+    // the subtree has no listings left, so its counts are all zero
+    for _, category := range subtree {
+        delete(m.categoryCounts, category.Name)
+    }
+
+    return nil
+}
+
+// lookupCategory retrieves a catalog entry by its canonical key. The caller must hold the lock.
+func (m *MemoryDataAccess) lookupCategory(name string) (*model.Category, error) {
+    category, exists := m.categories[model.CanonicalKey(name)]
+    if !exists {
+        return nil, nil
+    }
+    return &category, nil
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+    "context"
+    "marketplace-platform/pkg/data/model"
+)
+
+// PutIdempotencyRecord stores a record holding its key, unless an unexpired record holds it already
+// Returns the record holding the key, or nil if record was stored
+func (m *MemoryDataAccess) PutIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if existing, exists := m.idempotency[record.Key]; exists && !existing.IsExpired(record.CreatedAt) {
+        return &existing, nil
+    }
+    m.idempotency[record.Key] = record
+
+    return nil, nil
+}
+
+// CompleteIdempotencyRecord stores the response and the new expiry of a record
+func (m *MemoryDataAccess) CompleteIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.idempotency[record.Key] = record
+
+    return nil
+}
+
+// DeleteIdempotencyRecord releases the key of a record without a response
+func (m *MemoryDataAccess) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if existing, exists := m.idempotency[key]; exists && !existing.IsCompleted() {
+        delete(m.idempotency, key)
+    }
+
+    return nil
+}
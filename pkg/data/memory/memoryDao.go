@@ -0,0 +1,388 @@
+package memory
+
+import (
+    "context"
+    "fmt"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "sort"
+    "sync"
+    "time"
+)
+
+// MemoryDataAccess is a goroutine-safe, in-memory implementation of data.MarketplaceStore.
+// It mirrors the semantics of ddb.DynamoDataAccess and is meant for tests and offline development.
+type MemoryDataAccess struct {
+    mu             sync.RWMutex
+    // users and categories are keyed by their canonical key
+    users          map[string]model.User
+    listings       map[int]model.Listing
+    // categoryCounts counts the active listings of each category and its subcategories
+    categoryCounts map[string]int
+    orders         map[int]model.Order
+    categories     map[string]model.Category
+    // sessions are keyed by the hash of their token
+    sessions       map[string]model.Session
+    // api keys are keyed by their ID
+    apiKeys        map[string]model.ApiKey
+    rateLimits     map[string]model.RateLimitBucket
+    idempotency    map[string]model.IdempotencyRecord
+    lastListingId  int
+    // search index: the listings containing each term and the number of terms of each listing
+    searchTerms       map[string]map[int]bool
+    searchLengths     map[int]int
+    searchTotalLength int
+    log            *zap.SugaredLogger
+}
+
+var _ data.MarketplaceStore = (*MemoryDataAccess)(nil)
+
+func NewMemoryDataAccess(log *zap.SugaredLogger) *MemoryDataAccess {
+    return &MemoryDataAccess{
+        users:          make(map[string]model.User),
+        listings:       make(map[int]model.Listing),
+        categoryCounts: make(map[string]int),
+        orders:         make(map[int]model.Order),
+        categories:     make(map[string]model.Category),
+        sessions:       make(map[string]model.Session),
+        apiKeys:        make(map[string]model.ApiKey),
+        rateLimits:     make(map[string]model.RateLimitBucket),
+        idempotency:    make(map[string]model.IdempotencyRecord),
+        lastListingId:  constant.FirstListingId - 1,
+        searchTerms:    make(map[string]map[int]bool),
+        searchLengths:  make(map[int]int),
+        log:            log,
+    }
+}
+
+// PutUser a new user, or sets the password hash of a user registered without one
+// Returns nil if the user already exists with a password
+func (m *MemoryDataAccess) PutUser(ctx context.Context, username string, passwordHash string) (*model.User, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    user := model.User{
+        Username:     username,
+        PasswordHash: passwordHash,
+    }
+    if existing, exists := m.users[user.Key()]; exists {
+        if existing.HasPassword() {
+            return nil, nil
+        }
+        user.Username = existing.Username
+        user.Role = existing.Role
+    }
+    m.users[user.Key()] = user
+
+    return &user, nil
+}
+
+// GetUser retrieves a user by username
+// Returns nil if the user does not exist
+func (m *MemoryDataAccess) GetUser(ctx context.Context, username string) (*model.User, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    user, exists := m.users[model.CanonicalKey(username)]
+    if !exists {
+        return nil, nil
+    }
+
+    return &user, nil
+}
+
+// SetUserRole sets the role of a user
+// Returns nil if the user does not exist
+func (m *MemoryDataAccess) SetUserRole(ctx context.Context, username string, role enum.Role) (*model.User, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := model.CanonicalKey(username)
+    user, exists := m.users[key]
+    if !exists {
+        return nil, nil
+    }
+    user.Role = role
+    m.users[key] = user
+
+    return &user, nil
+}
+
+// PutListing puts a listing, increments the category count if it is active and indexes it for search
+func (m *MemoryDataAccess) PutListing(
+    ctx context.Context,
+    username string,
+    title string,
+    description string,
+    price int,
+    category string,
+    status enum.ListingStatus,
+) (*model.Listing, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    // validate before allocating an ID so that rejected input does not consume one
+    listing, err := model.NewListing(constant.FirstListingId, username, title, description, price, category, status)
+    if err != nil {
+        m.log.Error("failed to create new listing: ", err)
+        return nil, err
+    }
+    entry, err := model.CheckCategoryOpen(category, m.lookupCategory)
+    if err != nil {
+        return nil, err
+    }
+    listing.Category = entry.Name
+
+    m.lastListingId++
+    listing.ListingId = m.lastListingId
+
+    m.listings[listing.ListingId] = listing
+    if listing.IsActive() {
+        m.addCategoryCount(listing.Category, 1)
+    }
+    m.indexListing(listing)
+
+    return &listing, nil
+}
+
+// GetListing retrieves a listing by listingId
+// Returns nil if the listing does not exist
+func (m *MemoryDataAccess) GetListing(ctx context.Context, listingId int) (*model.Listing, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    listing, exists := m.listings[listingId]
+    if !exists {
+        return nil, nil
+    }
+
+    return &listing, nil
+}
+
+// GetCategory retrieves a page of the active listings of a specified category sorted by price or creation time
+func (m *MemoryDataAccess) GetCategory(ctx context.Context, query model.CategoryQuery) (*model.ListingPage, error) {
+    cursor, err := data.DecodeKeysetCursor(query.Cursor, query.Category, query.SortBy)
+    if err != nil {
+        return nil, err
+    }
+
+    m.mu.RLock()
+    var listings []model.Listing
+    for _, listing := range m.listings {
+        if model.IsInCategory(listing.Category, query.Category) && listing.IsActive() && query.Matches(listing) {
+            listings = append(listings, listing)
+        }
+    }
+    m.mu.RUnlock()
+
+    // order by sort key, with the first listing of the query order as the smallest
+    less := func(a model.Listing, b model.Listing) bool {
+        if query.OrderBy == enum.OrderByDescending {
+            a, b = b, a
+        }
+        return lessListing(a, b, query.SortBy)
+    }
+    sort.Slice(listings, func(i, j int) bool {
+        return less(listings[i], listings[j])
+    })
+
+    if cursor != nil {
+        last := model.Listing{ListingId: cursor.ListingId, Price: int(cursor.SortValue), CreatedAt: time.Unix(cursor.SortValue, 0)}
+        start := sort.Search(len(listings), func(i int) bool {
+            return less(last, listings[i])
+        })
+        listings = listings[start:]
+    }
+
+    page := &model.ListingPage{Listings: listings}
+    if query.Limit > 0 && len(listings) > query.Limit {
+        page.Listings = listings[:query.Limit]
+        page.Cursor = data.EncodeKeysetCursor(data.NewKeysetCursor(query.Category, page.Listings[query.Limit-1], query.SortBy))
+    }
+
+    return page, nil
+}
+
+// lessListing orders two listings by the sort key of the matching DynamoDB index, breaking ties by listing ID
+func lessListing(a model.Listing, b model.Listing, sortBy enum.SortBy) bool {
+    if sortBy == enum.SortByPrice {
+        if a.Price != b.Price {
+            return a.Price < b.Price
+        }
+    } else if a.CreatedAt.Unix() != b.CreatedAt.Unix() {
+        return a.CreatedAt.Unix() < b.CreatedAt.Unix()
+    }
+    return a.ListingId < b.ListingId
+}
+
+// GetTopCategory retrieves the subcategory of parent, or the top-level category if parent is empty, with the highest
+// total number of listings, the first of its ranking by GetTopCategories. Returns nil if no such category has ever
+// been used
+func (m *MemoryDataAccess) GetTopCategory(ctx context.Context, parent string) (*model.CategoryMetric, error) {
+    metrics, err := m.GetTopCategories(ctx, model.TopCategoriesQuery{Parent: parent, Limit: 1})
+    if err != nil || len(metrics) == 0 {
+        return nil, err
+    }
+    return &metrics[0], nil
+}
+
+// GetTopCategories ranks the categories selected by query by descending total number of listings, then by descending name
+func (m *MemoryDataAccess) GetTopCategories(ctx context.Context, query model.TopCategoriesQuery) ([]model.CategoryMetric, error) {
+    m.mu.RLock()
+    var metrics []model.CategoryMetric
+    for category, count := range m.categoryCounts {
+        metric := model.CategoryMetric{Category: category, CategoryCount: count}
+        if query.Matches(metric) {
+            metrics = append(metrics, metric)
+        }
+    }
+    m.mu.RUnlock()
+
+    sort.Slice(metrics, func(i, j int) bool {
+        return metrics[i].RanksBefore(metrics[j])
+    })
+    if query.Limit > 0 && len(metrics) > query.Limit {
+        metrics = metrics[:query.Limit]
+    }
+
+    return metrics, nil
+}
+
+// UpdateListing applies update to a listing and moves the category count if an active listing changes category
+func (m *MemoryDataAccess) UpdateListing(ctx context.Context, username string, listingId int, update model.ListingUpdate) (*model.Listing, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    listing, exists := m.listings[listingId]
+    if !exists {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    if listing.Username != username {
+        return nil, exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", listingId, username), nil)
+    }
+
+    updated, err := listing.Apply(update)
+    if err != nil {
+        return nil, err
+    }
+    if updated.Category != listing.Category {
+        entry, err := model.CheckCategoryOpen(updated.Category, m.lookupCategory)
+        if err != nil {
+            return nil, err
+        }
+        updated.Category = entry.Name
+    }
+
+    m.listings[listingId] = updated
+    if updated.Category != listing.Category && listing.IsActive() {
+        m.addCategoryCount(listing.Category, -1)
+        m.addCategoryCount(updated.Category, 1)
+    }
+    m.unindexListing(listing)
+    m.indexListing(updated)
+
+    return &updated, nil
+}
+
+// DeleteListing deletes a listing and decrements the category count if it is active
+func (m *MemoryDataAccess) DeleteListing(ctx context.Context, username string, listingId int) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    listing, exists := m.listings[listingId]
+    if !exists {
+        return exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    if listing.Username != username {
+        return exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", listingId, username), nil)
+    }
+
+    if listing.IsSold() {
+        return exception.NewListingSoldException(fmt.Sprintf("listing with listingId %d is sold", listingId), nil)
+    }
+
+    delete(m.listings, listingId)
+    if listing.IsActive() {
+        m.addCategoryCount(listing.Category, -1)
+    }
+    m.unindexListing(listing)
+
+    return nil
+}
+
+// TransitionListing moves a listing to the next status of transition and adjusts the category count
+func (m *MemoryDataAccess) TransitionListing(ctx context.Context, username string, listingId int, transition enum.ListingTransition) (*model.Listing, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    listing, exists := m.listings[listingId]
+    if !exists {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    updated, err := listing.Transition(username, transition)
+    if err != nil {
+        return nil, err
+    }
+
+    m.listings[listingId] = updated
+    m.addCategoryCount(listing.Category, model.CategoryCountDelta(listing, updated))
+
+    return &updated, nil
+}
+
+// BuyListing marks a listing as sold to buyer, records the order and decrements the category count
+func (m *MemoryDataAccess) BuyListing(ctx context.Context, buyer string, listingId int) (*model.Order, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    listing, exists := m.listings[listingId]
+    if !exists {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    sold, err := listing.Transition(buyer, enum.ListingTransitionBuy)
+    if err != nil {
+        return nil, err
+    }
+
+    order, err := model.NewOrder(listing, buyer)
+    if err != nil {
+        return nil, err
+    }
+
+    m.listings[listingId] = sold
+    m.orders[listingId] = order
+    m.addCategoryCount(listing.Category, model.CategoryCountDelta(listing, sold))
+
+    return &order, nil
+}
+
+// GetOrders retrieves the orders in which username is the buyer or the seller, newest first
+func (m *MemoryDataAccess) GetOrders(ctx context.Context, username string) ([]model.Order, error) {
+    m.mu.RLock()
+    var orders []model.Order
+    for _, order := range m.orders {
+        if order.Buyer == username || order.Seller == username {
+            orders = append(orders, order)
+        }
+    }
+    m.mu.RUnlock()
+
+    model.SortOrders(orders)
+
+    return orders, nil
+}
+
+// addCategoryCount adds delta to the count of a category and its ancestors. The caller must hold the write lock.
+func (m *MemoryDataAccess) addCategoryCount(category string, delta int) {
+    for _, ancestor := range model.CategoryAncestors(category) {
+        m.categoryCounts[ancestor] += delta
+    }
+}
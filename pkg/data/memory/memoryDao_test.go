@@ -0,0 +1,83 @@
+package memory
+
+import (
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/data/storetest"
+    "testing"
+)
+
+func TestConcurrentPutListing(t *testing.T) {
+    storetest.ConcurrentPutListing(t, NewMemoryDataAccess(zap.NewNop().Sugar()), 200)
+}
+
+func TestConcurrentUpdateListing(t *testing.T) {
+    storetest.ConcurrentUpdateListing(t, NewMemoryDataAccess(zap.NewNop().Sugar()), 20)
+}
+
+func TestConcurrentBuyListing(t *testing.T) {
+    storetest.ConcurrentBuyListing(t, NewMemoryDataAccess(zap.NewNop().Sugar()), 20)
+}
+
+func TestListingLifecycle(t *testing.T) {
+    storetest.ListingLifecycle(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestCategoryPagination(t *testing.T) {
+    storetest.CategoryPagination(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestCategoryFilters(t *testing.T) {
+    storetest.CategoryFilters(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestCategoryHierarchy(t *testing.T) {
+    storetest.CategoryHierarchy(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestSearchListings(t *testing.T) {
+    storetest.SearchListings(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestCategoryCatalog(t *testing.T) {
+    storetest.CategoryCatalog(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestCanonicalNames(t *testing.T) {
+    storetest.CanonicalNames(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestTopCategories(t *testing.T) {
+    storetest.TopCategories(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestEmptyMarketplace(t *testing.T) {
+    storetest.EmptyMarketplace(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestSessions(t *testing.T) {
+    storetest.Sessions(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestRoles(t *testing.T) {
+    storetest.Roles(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestApiKeys(t *testing.T) {
+    storetest.ApiKeys(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestRateLimits(t *testing.T) {
+    storetest.RateLimits(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestConcurrentRateLimit(t *testing.T) {
+    storetest.ConcurrentRateLimit(t, NewMemoryDataAccess(zap.NewNop().Sugar()), 20)
+}
+
+func TestIdempotencyRecords(t *testing.T) {
+    storetest.IdempotencyRecords(t, NewMemoryDataAccess(zap.NewNop().Sugar()))
+}
+
+func TestConcurrentIdempotencyRecord(t *testing.T) {
+    storetest.ConcurrentIdempotencyRecord(t, NewMemoryDataAccess(zap.NewNop().Sugar()), 20)
+}
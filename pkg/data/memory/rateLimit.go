@@ -0,0 +1,23 @@
+package memory
+
+import (
+    "context"
+    "marketplace-platform/pkg/data/model"
+    "time"
+)
+
+// TakeRateLimitToken takes a token from the rate limit bucket under key
+// Returns false if the bucket is empty
+func (m *MemoryDataAccess) TakeRateLimitToken(ctx context.Context, key string, limit model.RateLimit, now time.Time) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    bucket, exists := m.rateLimits[key]
+    if !exists {
+        bucket = model.NewRateLimitBucket(limit, now)
+    }
+    bucket, allowed := bucket.Take(limit, now)
+    m.rateLimits[key] = bucket
+
+    return allowed, nil
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+    "context"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/search"
+)
+
+// SearchListings retrieves the active listings matching the query text, ranked by BM25 relevance
+func (m *MemoryDataAccess) SearchListings(ctx context.Context, query model.SearchQuery) ([]model.Listing, error) {
+    queryTerms := search.UniqueTerms(search.Analyze(query.Text))
+
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    corpus := search.Corpus{
+        Documents:   len(m.searchLengths),
+        TotalLength: m.searchTotalLength,
+        Frequencies: make(map[string]int, len(queryTerms)),
+    }
+    candidates := make(map[int]bool)
+    for _, term := range queryTerms {
+        corpus.Frequencies[term] = len(m.searchTerms[term])
+        for listingId := range m.searchTerms[term] {
+            candidates[listingId] = true
+        }
+    }
+
+    var documents []search.Document
+    for listingId := range candidates {
+        listing := m.listings[listingId]
+        if query.Matches(listing) {
+            documents = append(documents, search.Document{ListingId: listingId, Terms: search.DocumentTerms(listing.Title, listing.Description)})
+        }
+    }
+
+    results := search.Rank(queryTerms, documents, corpus)
+    if query.Limit > 0 && len(results) > query.Limit {
+        results = results[:query.Limit]
+    }
+    listings := make([]model.Listing, 0, len(results))
+    for _, result := range results {
+        listings = append(listings, m.listings[result.ListingId])
+    }
+    return listings, nil
+}
+
+// RebuildSearchIndex replaces the search index with one built from every listing
+func (m *MemoryDataAccess) RebuildSearchIndex(ctx context.Context) (int, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.searchTerms = make(map[string]map[int]bool)
+    m.searchLengths = make(map[int]int)
+    m.searchTotalLength = 0
+    for _, listing := range m.listings {
+        m.indexListing(listing)
+    }
+    return len(m.listings), nil
+}
+
+// indexListing adds a listing to the search index. The caller must hold the write lock.
+func (m *MemoryDataAccess) indexListing(listing model.Listing) {
+    terms := search.DocumentTerms(listing.Title, listing.Description)
+    for _, term := range terms {
+        if m.searchTerms[term] == nil {
+            m.searchTerms[term] = make(map[int]bool)
+        }
+        m.searchTerms[term][listing.ListingId] = true
+    }
+    m.searchLengths[listing.ListingId] = len(terms)
+    m.searchTotalLength += len(terms)
+}
+
+// unindexListing removes a listing from the search index. The caller must hold the write lock.
+func (m *MemoryDataAccess) unindexListing(listing model.Listing) {
+    for _, term := range search.DocumentTerms(listing.Title, listing.Description) {
+        delete(m.searchTerms[term], listing.ListingId)
+        if len(m.searchTerms[term]) == 0 {
+            delete(m.searchTerms, term)
+        }
+    }
+    m.searchTotalLength -= m.searchLengths[listing.ListingId]
+    delete(m.searchLengths, listing.ListingId)
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+    "context"
+    "marketplace-platform/pkg/data/model"
+)
+
+// PutSession stores a session
+func (m *MemoryDataAccess) PutSession(ctx context.Context, session model.Session) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.sessions[session.TokenHash] = session
+
+    return nil
+}
+
+// GetSession retrieves a session by the hash of its token
+// Returns nil if the session does not exist
+func (m *MemoryDataAccess) GetSession(ctx context.Context, tokenHash string) (*model.Session, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    session, exists := m.sessions[tokenHash]
+    if !exists {
+        return nil, nil
+    }
+
+    return &session, nil
+}
+
+// DeleteSession revokes a session by the hash of its token
+func (m *MemoryDataAccess) DeleteSession(ctx context.Context, tokenHash string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.sessions, tokenHash)
+
+    return nil
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+    "crypto/rand"
+    "crypto/subtle"
+    "encoding/base64"
+    "encoding/hex"
+    "marketplace-platform/pkg/data/model/enum"
+    "sort"
+    "strings"
+    "time"
+)
+
+// ApiKeyPrefix starts every API key, telling keys apart from usernames and session tokens
+const ApiKeyPrefix = "mpk_"
+
+// apiKeyIdBytes and apiKeySecretBytes are the numbers of random bytes of the ID and the secret of an API key
+const (
+    apiKeyIdBytes     = 8
+    apiKeySecretBytes = 32
+)
+
+// ApiKey is a credential of a user for scripts and services, formatted as "mpk_<id>_<secret>". The ID is public and
+// names the key when listing or revoking it, while only the hash of the secret is stored.
+type ApiKey struct {
+    KeyId      string           `json:"keyId"`
+    SecretHash string           `json:"-"`
+    Username   string           `json:"username"`
+    Scope      enum.ApiKeyScope `json:"scope"`
+    CreatedAt  time.Time        `json:"createdAt"`
+    ExpiresAt  time.Time        `json:"expiresAt"`
+}
+
+// NewApiKey creates an API key of username with scope, expiring after ttl
+// Returns the key and its secret form to hand to the owner
+func NewApiKey(username string, scope enum.ApiKeyScope, ttl time.Duration) (ApiKey, string, error) {
+    id := make([]byte, apiKeyIdBytes)
+    _, err := rand.Read(id)
+    if err != nil {
+        return ApiKey{}, "", err
+    }
+    secret := make([]byte, apiKeySecretBytes)
+    _, err = rand.Read(secret)
+    if err != nil {
+        return ApiKey{}, "", err
+    }
+    keyId := hex.EncodeToString(id)
+    encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+
+    now := time.Now().Truncate(time.Second)
+    return ApiKey{
+        KeyId:      keyId,
+        SecretHash: HashSessionToken(encodedSecret),
+        Username:   username,
+        Scope:      scope,
+        CreatedAt:  now,
+        ExpiresAt:  now.Add(ttl),
+    }, ApiKeyPrefix + keyId + "_" + encodedSecret, nil
+}
+
+// IsApiKey reports whether a credential is an API key rather than a username or a session token
+func IsApiKey(credential string) bool {
+    return strings.HasPrefix(credential, ApiKeyPrefix)
+}
+
+// ParseApiKey splits an API key into its ID and secret
+// Returns ok false if the key is malformed
+func ParseApiKey(key string) (keyId string, secret string, ok bool) {
+    rest, found := strings.CutPrefix(key, ApiKeyPrefix)
+    if !found {
+        return "", "", false
+    }
+    // the ID is hex encoded, so the first separator ends it even though the secret may contain more
+    keyId, secret, found = strings.Cut(rest, "_")
+    if !found || len(keyId) != 2*apiKeyIdBytes || secret == "" {
+        return "", "", false
+    }
+    return keyId, secret, true
+}
+
+// Matches reports whether secret is the secret of the key
+func (k ApiKey) Matches(secret string) bool {
+    return subtle.ConstantTimeCompare([]byte(HashSessionToken(secret)), []byte(k.SecretHash)) == 1
+}
+
+// IsExpired reports whether the key can no longer be used
+func (k ApiKey) IsExpired(now time.Time) bool {
+    return !now.Before(k.ExpiresAt)
+}
+
+func (k ApiKey) String() string {
+    // print api key in the format:
+    // "<key_id>|<scope>|<created_at>|<expires_at>"
+    return k.KeyId + "|" + k.Scope.String() + "|" + k.CreatedAt.Format("2006-01-02 15:04:05") + "|" + k.ExpiresAt.Format("2006-01-02 15:04:05")
+}
+
+// SortApiKeys sorts API keys oldest first, breaking ties by key ID
+func SortApiKeys(keys []ApiKey) {
+    sort.Slice(keys, func(i, j int) bool {
+        if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+            return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+        }
+        return keys[i].KeyId < keys[j].KeyId
+    })
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+    "golang.org/x/text/cases"
+    "golang.org/x/text/unicode/norm"
+    "sort"
+)
+
+// CanonicalKey returns the key under which usernames and category names are unique: the Unicode case folding of s in
+// NFC, so that names differing only by case or by the encoding of their accents share a key
+func CanonicalKey(s string) string {
+    // folding is applied to the decomposed form, as a precomposed character may fold differently from its parts
+    return norm.NFC.String(cases.Fold().String(norm.NFD.String(s)))
+}
+
+// CategorySpellings chooses one spelling of each category among categories written with differing cases, keeping the
+// spellings of a category and of its ancestors in agreement: the last name of a category is taken from the first of
+// its spellings in byte order, under the chosen spelling of its parent
+// Returns the chosen spelling of each of categories and of their ancestors
+func CategorySpellings(categories []string) map[string]string {
+    // spellings of the last name of each category, by canonical key
+    names := make(map[string][]string)
+    for _, category := range categories {
+        for _, ancestor := range CategoryAncestors(category) {
+            key := CanonicalKey(ancestor)
+            names[key] = append(names[key], CategoryName(ancestor))
+        }
+    }
+
+    // parents sort before their subcategories, so that their spelling is chosen first
+    keys := make([]string, 0, len(names))
+    for key := range names {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    chosen := make(map[string]string, len(keys))
+    for _, key := range keys {
+        sort.Strings(names[key])
+        spelling := names[key][0]
+        if parent := ParentCategory(key); parent != "" {
+            spelling = chosen[parent] + CategorySeparator + spelling
+        }
+        chosen[key] = spelling
+    }
+
+    spellings := make(map[string]string)
+    for _, category := range categories {
+        for _, ancestor := range CategoryAncestors(category) {
+            spellings[ancestor] = chosen[CanonicalKey(ancestor)]
+        }
+    }
+    return spellings
+}
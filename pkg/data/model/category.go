@@ -0,0 +1,198 @@
+package model
+
+import (
+    "fmt"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "strings"
+)
+
+// Categories form a tree named by paths such as "Electronics/Phones", in which "Electronics" is the parent category
+const (
+    CategorySeparator = "/"
+    // MaxCategoryDepth bounds the number of category counts updated with a listing
+    MaxCategoryDepth = 5
+)
+
+// IsValidCategory reports whether a category is a path of at most MaxCategoryDepth non-empty names
+func IsValidCategory(category string) bool {
+    names := strings.Split(category, CategorySeparator)
+    if len(names) > MaxCategoryDepth {
+        return false
+    }
+    for _, name := range names {
+        if strings.TrimSpace(name) == "" {
+            return false
+        }
+    }
+    return true
+}
+
+// CategoryAncestors returns a category followed by its ancestors up to the top-level category
+func CategoryAncestors(category string) []string {
+    ancestors := []string{category}
+    for {
+        i := strings.LastIndex(category, CategorySeparator)
+        if i < 0 {
+            return ancestors
+        }
+        category = category[:i]
+        ancestors = append(ancestors, category)
+    }
+}
+
+// CategoryName returns the last name of a category path
+func CategoryName(category string) string {
+    return category[strings.LastIndex(category, CategorySeparator)+1:]
+}
+
+// ParentCategory returns the parent of a category, or an empty string for a top-level category
+func ParentCategory(category string) string {
+    i := strings.LastIndex(category, CategorySeparator)
+    if i < 0 {
+        return ""
+    }
+    return category[:i]
+}
+
+// IsInCategory reports whether a category is ancestor or one of its descendants
+func IsInCategory(category string, ancestor string) bool {
+    return category == ancestor || strings.HasPrefix(category, ancestor+CategorySeparator)
+}
+
+// MoveCategoryPath returns the path of a category of the subtree of from once from is moved to to
+func MoveCategoryPath(category string, from string, to string) string {
+    return to + strings.TrimPrefix(category, from)
+}
+
+// Category is an entry of the category catalog managed by admins
+type Category struct {
+    Name   string              `json:"name" validate:"required,category"`
+    Status enum.CategoryStatus `json:"status" validate:"oneof=ACTIVE RETIRED"`
+}
+
+// NewCategory creates an active catalog entry
+func NewCategory(name string) (Category, error) {
+    category := Category{
+        Name:   name,
+        Status: enum.CategoryStatusActive,
+    }
+
+    err := validate.Struct(category)
+    if err != nil {
+        return Category{}, err
+    }
+
+    return category, nil
+}
+
+// Key returns the canonical key of the category, under which catalog entries are unique
+func (c Category) Key() string {
+    return CanonicalKey(c.Name)
+}
+
+// IsRetired reports whether new listings are rejected from the category
+func (c Category) IsRetired() bool {
+    return c.Status == enum.CategoryStatusRetired
+}
+
+func (c Category) String() string {
+    // print category in the format:
+    // "<name>|<status>"
+    return c.Name + "|" + c.Status.String()
+}
+
+// CategoryLookup retrieves an entry of the catalog by the canonical key of name, or nil if there is none
+type CategoryLookup func(name string) (*Category, error)
+
+// RequireCategory retrieves the catalog entry of a category, whose name is the spelling kept in the catalog
+// Returns exception.CategoryDoesNotExistException if the category is not in the catalog
+func RequireCategory(category string, lookup CategoryLookup) (Category, error) {
+    entry, err := lookup(category)
+    if err != nil {
+        return Category{}, err
+    }
+    if entry == nil {
+        return Category{}, exception.NewCategoryDoesNotExistException(fmt.Sprintf("category '%s' does not exist", category), nil)
+    }
+    return *entry, nil
+}
+
+// CheckCategoryOpen checks that listings can be put in a category, which must be in the catalog without a retired
+// ancestor
+// Returns the catalog entry of the category, otherwise exception.CategoryDoesNotExistException or
+// exception.CategoryRetiredException
+func CheckCategoryOpen(category string, lookup CategoryLookup) (Category, error) {
+    entry, err := RequireCategory(category, lookup)
+    if err != nil {
+        return Category{}, err
+    }
+    for _, ancestor := range CategoryAncestors(entry.Name) {
+        ancestorEntry, err := lookup(ancestor)
+        if err != nil {
+            return Category{}, err
+        }
+        if ancestorEntry != nil && ancestorEntry.IsRetired() {
+            return Category{}, exception.NewCategoryRetiredException(fmt.Sprintf("category '%s' is retired", ancestorEntry.Name), nil)
+        }
+    }
+    return entry, nil
+}
+
+// NewCategoryPath checks that a category can be added to the catalog under an open parent
+// Returns the path of the category under the spelling of its parent in the catalog, otherwise the errors of
+// CheckCategoryOpen
+func NewCategoryPath(category string, lookup CategoryLookup) (string, error) {
+    parent := ParentCategory(category)
+    if parent == "" {
+        return category, nil
+    }
+    parentEntry, err := CheckCategoryOpen(parent, lookup)
+    if err != nil {
+        return "", err
+    }
+    return parentEntry.Name + CategorySeparator + CategoryName(category), nil
+}
+
+// CheckCategoryMove checks that subtree, the catalog entries of from and its subcategories, can be renamed to to, or
+// merged into to if merge is set. from is the spelling of the catalog.
+// Returns the path of to in the catalog, otherwise exception.CategoryDoesNotExistException,
+// exception.CategoryAlreadyExistException or exception.InvalidInputException
+func CheckCategoryMove(from string, to string, merge bool, subtree []Category, lookup CategoryLookup) (string, error) {
+    if !IsValidCategory(to) {
+        return "", exception.NewInvalidInputException(fmt.Sprintf("invalid category '%s'", to), nil)
+    }
+    if IsInCategory(CanonicalKey(to), CanonicalKey(from)) {
+        return "", exception.NewInvalidInputException(fmt.Sprintf("cannot move category '%s' into itself", from), nil)
+    }
+
+    entry, err := lookup(to)
+    if err != nil {
+        return "", err
+    }
+    if merge {
+        if entry == nil {
+            return "", exception.NewCategoryDoesNotExistException(fmt.Sprintf("category '%s' does not exist", to), nil)
+        }
+        to = entry.Name
+    } else {
+        if entry != nil {
+            return "", exception.NewCategoryAlreadyExistException(fmt.Sprintf("category '%s' already exists", to), nil)
+        }
+        if parent := ParentCategory(to); parent != "" {
+            parentEntry, err := RequireCategory(parent, lookup)
+            if err != nil {
+                return "", err
+            }
+            to = parentEntry.Name + CategorySeparator + CategoryName(to)
+        }
+    }
+
+    for _, subtreeEntry := range subtree {
+        moved := MoveCategoryPath(subtreeEntry.Name, from, to)
+        if !IsValidCategory(moved) {
+            return "", exception.NewInvalidInputException(fmt.Sprintf("invalid category '%s'", moved), nil)
+        }
+    }
+    return to, nil
+}
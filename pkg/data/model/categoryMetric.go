@@ -1,10 +1,46 @@
 package model
 
+import (
+    "strconv"
+)
+
+// CategoryMetric is the number of active listings of a category and its subcategories
 type CategoryMetric struct {
-    Category      string `dynamodbav:"Username" validate:"required"`   // sort key
-    CategoryCount int    `dynamodbav:"CategoryCount" validate:"gte=0"` // LSI
+    Category      string `json:"category" validate:"required"`
+    CategoryCount int    `json:"categoryCount" validate:"gte=0"`
 }
 
 func (c CategoryMetric) Validate() error {
     return validate.Struct(c)
 }
+
+// RanksBefore reports whether a category ranks before another one: categories with more listings come first, and
+// categories with as many listings by descending name. The first GetTopCategory read the DynamoDB count index
+// backwards, which orders equal counts by descending name, and the ranking keeps that order for every store.
+func (c CategoryMetric) RanksBefore(other CategoryMetric) bool {
+    if c.CategoryCount != other.CategoryCount {
+        return c.CategoryCount > other.CategoryCount
+    }
+    return c.Category > other.Category
+}
+
+func (c CategoryMetric) String() string {
+    // print category metric in the format:
+    // "<category>|<count>"
+    return c.Category + "|" + strconv.Itoa(c.CategoryCount)
+}
+
+// TopCategoriesQuery ranks the direct subcategories of Parent, or the top-level categories if Parent is empty, by
+// their total number of listings
+type TopCategoriesQuery struct {
+    Parent string
+    // Limit is the number of categories ranked. Zero ranks every category.
+    Limit int
+    // ExcludeEmpty leaves out the categories left without active listings, whose metric is kept with a count of zero
+    ExcludeEmpty bool
+}
+
+// Matches reports whether a category is ranked by the query
+func (q TopCategoriesQuery) Matches(metric CategoryMetric) bool {
+    return ParentCategory(metric.Category) == q.Parent && (!q.ExcludeEmpty || metric.CategoryCount > 0)
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "strings"
+    "time"
+)
+
+// CategoryQuery selects a page of the active listings of a category and its subcategories
+type CategoryQuery struct {
+    Category string
+    SortBy   enum.SortBy
+    OrderBy  enum.OrderBy
+    // Limit is the maximum number of listings in the page. 0 fetches every remaining listing.
+    Limit int
+    // Cursor continues from the end of a previous page. Empty starts from the first listing.
+    Cursor string
+
+    // MinPrice and MaxPrice bound the price in cents, inclusive. Nil leaves the bound open.
+    MinPrice *int
+    MaxPrice *int
+    // CreatedAfter and CreatedBefore bound the creation time, exclusive, at a resolution of seconds
+    CreatedAfter  *time.Time
+    CreatedBefore *time.Time
+    // TitleContains keeps the listings whose title contains it, case-sensitively
+    TitleContains string
+}
+
+// ListingPage is a page of listings and the cursor of the next page, which is empty if there are no more listings
+type ListingPage struct {
+    Listings []Listing `json:"listings"`
+    Cursor   string    `json:"cursor,omitempty"`
+}
+
+// Validate checks that the filters of the query can match a listing
+func (q CategoryQuery) Validate() error {
+    err := validatePriceRange(q.MinPrice, q.MaxPrice)
+    if err != nil {
+        return err
+    }
+    if from, to := q.CreatedAtRange(); from != nil && to != nil && *from > *to {
+        return exception.NewInvalidInputException("created after bound is not before created before bound", nil)
+    }
+    return nil
+}
+
+// HasFilters reports whether the query narrows the category by price, creation time or title
+func (q CategoryQuery) HasFilters() bool {
+    return q.MinPrice != nil || q.MaxPrice != nil || q.CreatedAfter != nil || q.CreatedBefore != nil || q.TitleContains != ""
+}
+
+// CreatedAtRange returns the creation time bounds as inclusive Unix seconds. Nil leaves the bound open.
+func (q CategoryQuery) CreatedAtRange() (from *int64, to *int64) {
+    if q.CreatedAfter != nil {
+        after := q.CreatedAfter.Unix() + 1
+        from = &after
+    }
+    if q.CreatedBefore != nil {
+        before := q.CreatedBefore.Unix() - 1
+        if q.CreatedBefore.Nanosecond() > 0 {
+            // a fraction of a second past the bound still excludes the whole second before it
+            before++
+        }
+        to = &before
+    }
+    return from, to
+}
+
+// Matches reports whether a listing satisfies the price, creation time and title filters of the query
+func (q CategoryQuery) Matches(listing Listing) bool {
+    if !inPriceRange(listing, q.MinPrice, q.MaxPrice) {
+        return false
+    }
+    from, to := q.CreatedAtRange()
+    if from != nil && listing.CreatedAt.Unix() < *from {
+        return false
+    }
+    if to != nil && listing.CreatedAt.Unix() > *to {
+        return false
+    }
+    return strings.Contains(listing.Title, q.TitleContains)
+}
+
+// validatePriceRange checks that inclusive price bounds in cents can match a listing
+func validatePriceRange(minPrice *int, maxPrice *int) error {
+    if (minPrice != nil && *minPrice < 0) || (maxPrice != nil && *maxPrice < 0) {
+        return exception.NewInvalidInputException("price bounds cannot be negative", nil)
+    }
+    if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+        return exception.NewInvalidInputException("minimum price is above maximum price", nil)
+    }
+    return nil
+}
+
+// inPriceRange reports whether the price of a listing is within inclusive bounds. Nil bounds are open.
+func inPriceRange(listing Listing, minPrice *int, maxPrice *int) bool {
+    return (minPrice == nil || listing.Price >= *minPrice) && (maxPrice == nil || listing.Price <= *maxPrice)
+}
@@ -0,0 +1,31 @@
+package enum
+
+// ApiKeyScope is persisted as its string value. Each scope includes the scopes before it.
+type ApiKeyScope string
+
+const (
+    ApiKeyScopeReadOnly      ApiKeyScope = "read-only"
+    ApiKeyScopeListingsWrite ApiKeyScope = "listings:write"
+    ApiKeyScopeAdmin         ApiKeyScope = "admin"
+)
+
+var apiKeyScopeRanks = map[ApiKeyScope]int{
+    ApiKeyScopeReadOnly:      1,
+    ApiKeyScopeListingsWrite: 2,
+    ApiKeyScopeAdmin:         3,
+}
+
+// IsValid reports whether s is a known scope
+func (s ApiKeyScope) IsValid() bool {
+    _, exists := apiKeyScopeRanks[s]
+    return exists
+}
+
+// Includes reports whether a key of scope s may be used where scope required is needed
+func (s ApiKeyScope) Includes(required ApiKeyScope) bool {
+    return s.IsValid() && apiKeyScopeRanks[s] >= apiKeyScopeRanks[required]
+}
+
+func (s ApiKeyScope) String() string {
+    return string(s)
+}
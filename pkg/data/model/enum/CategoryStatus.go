@@ -0,0 +1,13 @@
+package enum
+
+// CategoryStatus is persisted as its string value
+type CategoryStatus string
+
+const (
+    CategoryStatusActive  CategoryStatus = "ACTIVE"
+    CategoryStatusRetired CategoryStatus = "RETIRED"
+)
+
+func (s CategoryStatus) String() string {
+    return string(s)
+}
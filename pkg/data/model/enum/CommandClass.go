@@ -0,0 +1,16 @@
+package enum
+
+// CommandClass groups the commands sharing a rate limit. It is persisted as part of the key of a rate limit bucket.
+type CommandClass string
+
+const (
+    CommandClassRead  CommandClass = "read"
+    CommandClassWrite CommandClass = "write"
+    CommandClassAdmin CommandClass = "admin"
+    // CommandClassLogin limits the login attempts on a username, whoever makes them
+    CommandClassLogin CommandClass = "login"
+)
+
+func (c CommandClass) String() string {
+    return string(c)
+}
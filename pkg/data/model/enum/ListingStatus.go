@@ -0,0 +1,19 @@
+package enum
+
+// ListingStatus is persisted as its string value
+type ListingStatus string
+
+const (
+    ListingStatusDraft     ListingStatus = "DRAFT"
+    ListingStatusActive    ListingStatus = "ACTIVE"
+    ListingStatusReserved  ListingStatus = "RESERVED"
+    ListingStatusSold      ListingStatus = "SOLD"
+    ListingStatusExpired   ListingStatus = "EXPIRED"
+    ListingStatusWithdrawn ListingStatus = "WITHDRAWN"
+    // ListingStatusHidden is set by moderators, keeping the listing out of categories and search until unhidden
+    ListingStatusHidden ListingStatus = "HIDDEN"
+)
+
+func (s ListingStatus) String() string {
+    return string(s)
+}
@@ -0,0 +1,28 @@
+package enum
+
+// ListingTransition is an action that moves a listing from one ListingStatus to another
+type ListingTransition int
+
+const (
+    ListingTransitionPublish ListingTransition = iota
+    ListingTransitionReserve
+    ListingTransitionUnreserve
+    ListingTransitionWithdraw
+    ListingTransitionExpire
+    ListingTransitionBuy
+    ListingTransitionHide
+    ListingTransitionUnhide
+)
+
+func (t ListingTransition) String() string {
+    return []string{
+        "publish",
+        "reserve",
+        "unreserve",
+        "withdraw",
+        "expire",
+        "buy",
+        "hide",
+        "unhide",
+    }[t]
+}
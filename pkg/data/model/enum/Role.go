@@ -0,0 +1,14 @@
+package enum
+
+// Role is persisted as its string value. Users registered before roles existed have none and are regular users.
+type Role string
+
+const (
+    RoleUser      Role = "USER"
+    RoleModerator Role = "MODERATOR"
+    RoleAdmin     Role = "ADMIN"
+)
+
+func (r Role) String() string {
+    return string(r)
+}
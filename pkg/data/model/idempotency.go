@@ -0,0 +1,49 @@
+package model
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "time"
+)
+
+// MaxIdempotencyKeyLength bounds the length of the idempotency keys chosen by clients
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyRecord remembers a mutating command run under an idempotency key: the hash of the command and its
+// parameters, and the JSON of its result once it succeeded. A record without a response holds its key while the
+// command runs, until the deadline of the command.
+type IdempotencyRecord struct {
+    Key         string
+    RequestHash string
+    Response    []byte
+    CreatedAt   time.Time
+    ExpiresAt   time.Time
+}
+
+// IdempotencyRecordKey returns the key of the record of an idempotency key of username, so that every user chooses
+// keys independently of the others
+func IdempotencyRecordKey(username string, key string) string {
+    return CanonicalKey(username) + "#" + key
+}
+
+// HashIdempotentRequest returns the hash of a command and its parameters, which a replay must match
+func HashIdempotentRequest(command string, params ...any) (string, error) {
+    encoded, err := json.Marshal(params)
+    if err != nil {
+        return "", fmt.Errorf("failed to marshal the parameters of %s: %w", command, err)
+    }
+    hash := sha256.Sum256(append([]byte(command+"\n"), encoded...))
+    return hex.EncodeToString(hash[:]), nil
+}
+
+// IsCompleted reports whether the command of the record succeeded and its response can be replayed
+func (r IdempotencyRecord) IsCompleted() bool {
+    return r.Response != nil
+}
+
+// IsExpired reports whether the key of the record can be used again
+func (r IdempotencyRecord) IsExpired(now time.Time) bool {
+    return !now.Before(r.ExpiresAt)
+}
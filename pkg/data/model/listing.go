@@ -1,24 +1,46 @@
 package model
 
 import (
+    "fmt"
     "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
     "strconv"
     "time"
 )
 
 type Listing struct {
-    ListingId   int       `dynamodbav:"ListingId" validate:"gt=100000"` // partition key
-    Username    string    `dynamodbav:"Username" validate:"required"`   // sort key
-    Title       string    `dynamodbav:"Title" validate:"required"`
-    Description string    `dynamodbav:"Description"`
-    Price       int       `dynamodbav:"Price" validate:"gte=0"`
-    Category    string    `dynamodbav:"Category" validate:"required"`
-    CreatedAt   time.Time `dynamodbav:"CreatedAt,unixtime"`
+    ListingId   int                `dynamodbav:"ListingId" json:"listingId" validate:"gt=100000"` // partition key
+    Username    string             `dynamodbav:"Username" json:"username" validate:"required"`    // sort key
+    Title       string             `dynamodbav:"Title" json:"title" validate:"required"`
+    Description string             `dynamodbav:"Description" json:"description"`
+    Price       int                `dynamodbav:"Price" json:"price" validate:"gte=0"`
+    Category    string             `dynamodbav:"Category" json:"category" validate:"required,category"`
+    CreatedAt   time.Time          `dynamodbav:"CreatedAt,unixtime" json:"createdAt"`
+    Version     int                `dynamodbav:"Version" json:"version"` // incremented on every update for optimistic concurrency
+    Status      enum.ListingStatus `dynamodbav:"Status" json:"status" validate:"omitempty,oneof=DRAFT ACTIVE RESERVED SOLD EXPIRED WITHDRAWN HIDDEN"`
+    ReservedBy  string             `dynamodbav:"ReservedBy,omitempty" json:"reservedBy,omitempty"` // set while the listing is reserved
 }
 
-func NewListing(listingId int, username string, title string, description string, price int, category string) (Listing, error) {
+// ListingUpdate holds the fields to change on a listing. Nil fields are left unchanged.
+type ListingUpdate struct {
+    Title       *string `json:"title"`
+    Description *string `json:"description"`
+    Price       *int    `json:"price"`
+    Category    *string `json:"category"`
+}
+
+func (u ListingUpdate) IsEmpty() bool {
+    return u.Title == nil && u.Description == nil && u.Price == nil && u.Category == nil
+}
+
+// NewListing creates a listing in status, which is either DRAFT or ACTIVE
+func NewListing(listingId int, username string, title string, description string, price int, category string, status enum.ListingStatus) (Listing, error) {
+    if status != enum.ListingStatusDraft && status != enum.ListingStatusActive {
+        return Listing{}, exception.NewInvalidInputException(fmt.Sprintf("listings cannot be created in status %s", status), nil)
+    }
+
     listing := Listing{
         ListingId:   listingId,
         Username:    username,
@@ -27,6 +49,8 @@ func NewListing(listingId int, username string, title string, description string
         Price:       price,
         Category:    category,
         CreatedAt:   time.Now(),
+        Version:     1,
+        Status:      status,
     }
 
     err := validate.Struct(listing)
@@ -37,22 +61,62 @@ func NewListing(listingId int, username string, title string, description string
     return listing, nil
 }
 
+// CurrentStatus returns the status of the listing. Listings written before statuses were introduced are active.
+func (l Listing) CurrentStatus() enum.ListingStatus {
+    if l.Status == "" {
+        return enum.ListingStatusActive
+    }
+    return l.Status
+}
+
+// IsSold reports whether the listing has been bought
+func (l Listing) IsSold() bool {
+    return l.CurrentStatus() == enum.ListingStatusSold
+}
+
+// IsActive reports whether the listing is visible in its category and counted in its CategoryMetric
+func (l Listing) IsActive() bool {
+    return l.CurrentStatus() == enum.ListingStatusActive
+}
+
 func (l Listing) Validate() error {
     return validate.Struct(l)
 }
 
-func (l Listing) DdbMarshalMap() (map[string]types.AttributeValue, error) {
-    av, err := attributevalue.MarshalMap(l)
-    if err != nil {
-        return av, err
+// Apply returns a validated copy of the listing with the update applied and the version incremented.
+// Sold and withdrawn listings cannot be updated.
+func (l Listing) Apply(update ListingUpdate) (Listing, error) {
+    switch l.CurrentStatus() {
+    case enum.ListingStatusSold:
+        return Listing{}, exception.NewListingSoldException(fmt.Sprintf("listing with listingId %d is sold", l.ListingId), nil)
+    case enum.ListingStatusWithdrawn:
+        return Listing{}, exception.NewInvalidStatusTransitionException("cannot update a withdrawn listing", nil)
+    }
+
+    if update.Title != nil {
+        l.Title = *update.Title
+    }
+    if update.Description != nil {
+        l.Description = *update.Description
+    }
+    if update.Price != nil {
+        l.Price = *update.Price
     }
+    if update.Category != nil {
+        l.Category = *update.Category
+    }
+    l.Version++
 
-    // Add GSI attribute
-    av[constant.ListingIdIndexPartitionKeyName] = &types.AttributeValueMemberN{
-        Value: strconv.Itoa(constant.ListingIdIndexPartitionKey),
+    err := validate.Struct(l)
+    if err != nil {
+        return Listing{}, err
     }
 
-    return av, nil
+    return l, nil
+}
+
+func (l Listing) DdbMarshalMap() (map[string]types.AttributeValue, error) {
+    return attributevalue.MarshalMap(l)
 }
 
 func (l Listing) String() string {
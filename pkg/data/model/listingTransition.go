@@ -0,0 +1,111 @@
+package model
+
+import (
+    "fmt"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "strings"
+)
+
+// listingTransitions is the listing state machine: the status each transition leads to from each status it is
+// allowed in. SOLD and WITHDRAWN are final.
+var listingTransitions = map[enum.ListingTransition]map[enum.ListingStatus]enum.ListingStatus{
+    enum.ListingTransitionPublish: {
+        enum.ListingStatusDraft:   enum.ListingStatusActive,
+        enum.ListingStatusExpired: enum.ListingStatusActive,
+    },
+    enum.ListingTransitionReserve: {
+        enum.ListingStatusActive: enum.ListingStatusReserved,
+    },
+    enum.ListingTransitionUnreserve: {
+        enum.ListingStatusReserved: enum.ListingStatusActive,
+    },
+    enum.ListingTransitionWithdraw: {
+        enum.ListingStatusDraft:    enum.ListingStatusWithdrawn,
+        enum.ListingStatusActive:   enum.ListingStatusWithdrawn,
+        enum.ListingStatusReserved: enum.ListingStatusWithdrawn,
+        enum.ListingStatusExpired:  enum.ListingStatusWithdrawn,
+        enum.ListingStatusHidden:   enum.ListingStatusWithdrawn,
+    },
+    enum.ListingTransitionExpire: {
+        enum.ListingStatusActive: enum.ListingStatusExpired,
+    },
+    enum.ListingTransitionBuy: {
+        enum.ListingStatusActive:   enum.ListingStatusSold,
+        enum.ListingStatusReserved: enum.ListingStatusSold,
+    },
+    enum.ListingTransitionHide: {
+        enum.ListingStatusActive:   enum.ListingStatusHidden,
+        enum.ListingStatusReserved: enum.ListingStatusHidden,
+    },
+    enum.ListingTransitionUnhide: {
+        enum.ListingStatusHidden: enum.ListingStatusActive,
+    },
+}
+
+// Transition returns a copy of the listing moved to the status that transition leads to when performed by username,
+// with the version incremented.
+// Publish, withdraw and expire are performed by the owner. Reserve and buy are performed by anyone but the owner, and a
+// reserved listing can only be bought by the user who reserved it. Unreserve is performed by the owner or that user.
+// Hide and unhide are performed by moderators, whose role is checked by the caller. Hiding drops a reservation.
+func (l Listing) Transition(username string, transition enum.ListingTransition) (Listing, error) {
+    current := l.CurrentStatus()
+
+    switch transition {
+    case enum.ListingTransitionReserve, enum.ListingTransitionBuy:
+        if l.Username == username {
+            return Listing{}, exception.NewSelfPurchaseException(fmt.Sprintf("listing with listingId %d is owned by %s", l.ListingId, username), nil)
+        }
+        if transition == enum.ListingTransitionBuy && current == enum.ListingStatusSold {
+            return Listing{}, exception.NewListingSoldException(fmt.Sprintf("listing with listingId %d is already sold", l.ListingId), nil)
+        }
+        if transition == enum.ListingTransitionBuy && current == enum.ListingStatusReserved && l.ReservedBy != username {
+            return Listing{}, exception.NewInvalidStatusTransitionException("cannot buy a listing reserved by another user", nil)
+        }
+    case enum.ListingTransitionHide, enum.ListingTransitionUnhide:
+    case enum.ListingTransitionUnreserve:
+        if l.Username != username && l.ReservedBy != username {
+            return Listing{}, exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is neither owned nor reserved by %s", l.ListingId, username), nil)
+        }
+    default:
+        if l.Username != username {
+            return Listing{}, exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", l.ListingId, username), nil)
+        }
+    }
+
+    next, allowed := listingTransitions[transition][current]
+    if !allowed {
+        return Listing{}, exception.NewInvalidStatusTransitionException(fmt.Sprintf("cannot %s %s listing", transition, withArticle(current)), nil)
+    }
+
+    l.Status = next
+    l.ReservedBy = ""
+    if next == enum.ListingStatusReserved {
+        l.ReservedBy = username
+    }
+    l.Version++
+
+    return l, nil
+}
+
+// CategoryCountDelta is the change of the category count when a listing moves from one status to another.
+// Only active listings are counted.
+func CategoryCountDelta(from Listing, to Listing) int {
+    delta := 0
+    if from.IsActive() {
+        delta--
+    }
+    if to.IsActive() {
+        delta++
+    }
+    return delta
+}
+
+// withArticle renders a status as "a sold" or "an active" for error messages
+func withArticle(status enum.ListingStatus) string {
+    name := strings.ToLower(status.String())
+    if strings.ContainsAny(name[:1], "aeiou") {
+        return "an " + name
+    }
+    return "a " + name
+}
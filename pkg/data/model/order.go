@@ -0,0 +1,78 @@
+package model
+
+import (
+    "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+    "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "marketplace-platform/pkg/constant"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// Order records the purchase of a listing. A listing can be bought only once, so the listing ID identifies the order.
+// Title and Price are snapshots taken at the time of purchase.
+type Order struct {
+    ListingId int       `dynamodbav:"OrderListingId" json:"listingId" validate:"gt=100000"`
+    Title     string    `dynamodbav:"OrderTitle" json:"title"`
+    Price     int       `dynamodbav:"OrderPrice" json:"price" validate:"gte=0"`
+    Seller    string    `dynamodbav:"Seller" json:"seller" validate:"required"`
+    Buyer     string    `dynamodbav:"Buyer" json:"buyer" validate:"required,nefield=Seller"`
+    CreatedAt time.Time `dynamodbav:"OrderedAt,unixtime" json:"createdAt"`
+}
+
+func NewOrder(listing Listing, buyer string) (Order, error) {
+    order := Order{
+        ListingId: listing.ListingId,
+        Title:     listing.Title,
+        Price:     listing.Price,
+        Seller:    listing.Username,
+        Buyer:     buyer,
+        CreatedAt: time.Now(),
+    }
+
+    err := validate.Struct(order)
+    if err != nil {
+        return Order{}, err
+    }
+
+    return order, nil
+}
+
+// DdbMarshalMap marshals the copy of the order kept under username, which is either the buyer or the seller
+func (o Order) DdbMarshalMap(username string) (map[string]types.AttributeValue, error) {
+    av, err := attributevalue.MarshalMap(o)
+    if err != nil {
+        return av, err
+    }
+
+    // fix the partition key and sort key
+    av[constant.ListingTablePartitionKeyName] = &types.AttributeValueMemberN{
+        Value: strconv.Itoa(constant.OrderRecordPartitionKey),
+    }
+    av[constant.ListingTableSortKeyName] = &types.AttributeValueMemberS{
+        Value: OrderRecordSortKey(username, o.ListingId),
+    }
+
+    return av, nil
+}
+
+// OrderRecordSortKey is the sort key of the copy of an order kept under username
+func OrderRecordSortKey(username string, listingId int) string {
+    return username + "#" + strconv.Itoa(listingId)
+}
+
+func (o Order) String() string {
+    // print order in the format:
+    // "<listing_id>|<title>|<price>|<created_at>|<seller>|<buyer>"
+    return strconv.Itoa(o.ListingId) + "|" + o.Title + "|" + strconv.Itoa(o.Price/100) + "|" + o.CreatedAt.Format("2006-01-02 15:04:05") + "|" + o.Seller + "|" + o.Buyer
+}
+
+// SortOrders sorts orders newest first, breaking ties by descending listing ID
+func SortOrders(orders []Order) {
+    sort.Slice(orders, func(i, j int) bool {
+        if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+            return orders[i].CreatedAt.After(orders[j].CreatedAt)
+        }
+        return orders[i].ListingId > orders[j].ListingId
+    })
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+    "fmt"
+    "marketplace-platform/pkg/data/model/enum"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// RateLimitOff disables a rate limit where a limit is configured
+const RateLimitOff = "off"
+
+// RateLimit is a token bucket holding up to Burst tokens and refilled at Burst tokens per Period. Every command takes
+// a token, so a user can send Burst commands at once and Burst commands per Period after that. A zero limit is disabled.
+type RateLimit struct {
+    Burst  int
+    Period time.Duration
+}
+
+// ParseRateLimit parses a limit formatted as "<burst>/<period>", such as "60/1m", or "off"
+func ParseRateLimit(value string) (RateLimit, error) {
+    if value == RateLimitOff {
+        return RateLimit{}, nil
+    }
+    burst, period, found := strings.Cut(value, "/")
+    if !found {
+        return RateLimit{}, fmt.Errorf("expected <burst>/<period>, got '%s'", value)
+    }
+    limit := RateLimit{}
+    var err error
+    limit.Burst, err = strconv.Atoi(burst)
+    if err != nil || limit.Burst <= 0 {
+        return RateLimit{}, fmt.Errorf("invalid burst '%s', expected a positive number", burst)
+    }
+    limit.Period, err = time.ParseDuration(period)
+    if err != nil || limit.Period <= 0 {
+        return RateLimit{}, fmt.Errorf("invalid period '%s', expected a positive duration", period)
+    }
+    return limit, nil
+}
+
+// IsEnabled reports whether commands are limited
+func (l RateLimit) IsEnabled() bool {
+    return l.Burst > 0
+}
+
+func (l RateLimit) String() string {
+    if !l.IsEnabled() {
+        return RateLimitOff
+    }
+    return strconv.Itoa(l.Burst) + "/" + l.Period.String()
+}
+
+// RateLimitBucket is the stored state of the token bucket of a user and command class. Version is incremented on every
+// update so that concurrent updates can be detected.
+type RateLimitBucket struct {
+    Tokens    float64
+    UpdatedAt time.Time
+    Version   int
+}
+
+// NewRateLimitBucket returns the full bucket of a user who has not sent any command yet
+func NewRateLimitBucket(limit RateLimit, now time.Time) RateLimitBucket {
+    return RateLimitBucket{Tokens: float64(limit.Burst), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time elapsed since its last update and takes a token if a whole one is left
+// Returns the updated bucket, and false if the command is rate limited
+func (b RateLimitBucket) Take(limit RateLimit, now time.Time) (RateLimitBucket, bool) {
+    // a clock going backwards adds no tokens
+    if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+        b.Tokens += float64(limit.Burst) * float64(elapsed) / float64(limit.Period)
+        b.UpdatedAt = now
+    }
+    if b.Tokens > float64(limit.Burst) {
+        b.Tokens = float64(limit.Burst)
+    }
+    b.Version++
+
+    if b.Tokens < 1 {
+        return b, false
+    }
+    b.Tokens--
+    return b, true
+}
+
+// RateLimitKey is the key of the bucket of a user and command class
+func RateLimitKey(class enum.CommandClass, username string) string {
+    return class.String() + "#" + CanonicalKey(username)
+}
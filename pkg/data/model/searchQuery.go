@@ -0,0 +1,32 @@
+package model
+
+import (
+    "marketplace-platform/pkg/exception"
+    "strings"
+)
+
+// SearchQuery searches the titles and descriptions of the active listings
+type SearchQuery struct {
+    Text string
+    // Category restricts the results to a category and its subcategories. Empty searches every category.
+    Category string
+    // MinPrice and MaxPrice bound the price in cents, inclusive. Nil leaves the bound open.
+    MinPrice *int
+    MaxPrice *int
+    // Limit is the maximum number of results. 0 returns every match.
+    Limit int
+}
+
+// Validate checks that the query has text and that its filters can match a listing
+func (q SearchQuery) Validate() error {
+    if strings.TrimSpace(q.Text) == "" {
+        return exception.NewInvalidInputException("empty search query", nil)
+    }
+    return validatePriceRange(q.MinPrice, q.MaxPrice)
+}
+
+// Matches reports whether a listing is active and satisfies the category and price filters of the query
+func (q SearchQuery) Matches(listing Listing) bool {
+    return listing.IsActive() && (q.Category == "" || IsInCategory(listing.Category, q.Category)) &&
+        inPriceRange(listing, q.MinPrice, q.MaxPrice)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "time"
+)
+
+// sessionTokenBytes is the number of random bytes of a session token
+const sessionTokenBytes = 32
+
+// Session is a login of a user, identified by the hash of its token so that the stored sessions cannot be used to
+// authenticate. The token itself is only known to the client.
+type Session struct {
+    TokenHash string    `json:"-"`
+    Username  string    `json:"username"`
+    CreatedAt time.Time `json:"createdAt"`
+    ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NewSession creates a session of username expiring after ttl
+// Returns the session and its token
+func NewSession(username string, ttl time.Duration) (Session, string, error) {
+    random := make([]byte, sessionTokenBytes)
+    _, err := rand.Read(random)
+    if err != nil {
+        return Session{}, "", err
+    }
+    token := base64.RawURLEncoding.EncodeToString(random)
+
+    now := time.Now().Truncate(time.Second)
+    return Session{
+        TokenHash: HashSessionToken(token),
+        Username:  username,
+        CreatedAt: now,
+        ExpiresAt: now.Add(ttl),
+    }, token, nil
+}
+
+// HashSessionToken returns the hash under which the session of a token is stored
+func HashSessionToken(token string) string {
+    hash := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(hash[:])
+}
+
+// IsExpired reports whether the session can no longer be used
+func (s Session) IsExpired(now time.Time) bool {
+    return !now.Before(s.ExpiresAt)
+}
@@ -3,28 +3,73 @@ package model
 import (
     "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
     "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+    "golang.org/x/crypto/bcrypt"
     "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model/enum"
     "strconv"
 )
 
+// Passwords are hashed with bcrypt, which only reads the first 72 bytes of a password
+const (
+    MinPasswordLength = 8
+    MaxPasswordLength = 72
+)
+
+// User is a registered user. The username keeps the spelling it was registered with, and is unique by its canonical key.
+// Users registered before passwords existed have no password hash and cannot log in until they register again.
 type User struct {
-    Username string `dynamodbav:"Username"`
+    Username     string    `dynamodbav:"DisplayName" json:"username" validate:"required"`
+    PasswordHash string    `dynamodbav:"PasswordHash,omitempty" json:"-"`
+    Role         enum.Role `dynamodbav:"Role,omitempty" json:"role,omitempty"`
 }
 
 func (u User) Validate() error {
     return validate.Struct(u)
 }
 
+// CurrentRole returns the role granted to the user. Users without a role are regular users.
+func (u User) CurrentRole() enum.Role {
+    if u.Role == "" {
+        return enum.RoleUser
+    }
+    return u.Role
+}
+
+// HasPassword reports whether the user registered with a password
+func (u User) HasPassword() bool {
+    return u.PasswordHash != ""
+}
+
+// CheckPassword reports whether password is the password of the user
+func (u User) CheckPassword(password string) bool {
+    return u.HasPassword() && bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// HashPassword hashes a password of MinPasswordLength to MaxPasswordLength bytes to be stored with a user
+func HashPassword(password string) (string, error) {
+    hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+    if err != nil {
+        return "", err
+    }
+    return string(hash), nil
+}
+
+// Key returns the canonical key of the username
+func (u User) Key() string {
+    return CanonicalKey(u.Username)
+}
+
 func (u User) DdbMarshalMap() (map[string]types.AttributeValue, error) {
     av, err := attributevalue.MarshalMap(u)
     if err != nil {
         return av, err
     }
 
-    // fix the partition key
+    // fix the partition key, and key the record by the canonical username
     av[constant.ListingTablePartitionKeyName] = &types.AttributeValueMemberN{
         Value: strconv.Itoa(constant.UserRootRecordPartitionKey),
     }
+    av[constant.ListingTableSortKeyName] = &types.AttributeValueMemberS{Value: u.Key()}
 
     return av, nil
 }
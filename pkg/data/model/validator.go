@@ -12,6 +12,13 @@ func init() {
     if err := validate.RegisterValidation("uuid", isValidUUIDValidator); err != nil {
         panic(err)
     }
+    if err := validate.RegisterValidation("category", isValidCategoryValidator); err != nil {
+        panic(err)
+    }
+}
+
+func isValidCategoryValidator(fl validator.FieldLevel) bool {
+    return IsValidCategory(fl.Field().String())
 }
 
 func isValidUUIDValidator(fl validator.FieldLevel) bool {
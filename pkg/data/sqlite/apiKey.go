@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "time"
+)
+
+const apiKeyColumns = `key_id, secret_hash, username, scope, created_at, expires_at`
+
+// PutApiKey stores an API key
+func (s *SqliteDataAccess) PutApiKey(ctx context.Context, key model.ApiKey) error {
+    _, err := s.db.ExecContext(ctx, `INSERT INTO api_keys (`+apiKeyColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+        key.KeyId, key.SecretHash, key.Username, key.Scope, key.CreatedAt.Unix(), key.ExpiresAt.Unix())
+    if err != nil {
+        return fmt.Errorf("failed to insert api key: %w", err)
+    }
+
+    return nil
+}
+
+// GetApiKey retrieves an API key by its ID
+// Returns nil if the key does not exist
+func (s *SqliteDataAccess) GetApiKey(ctx context.Context, keyId string) (*model.ApiKey, error) {
+    key, err := scanApiKey(s.db.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_id = ?`, keyId))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, nil
+    }
+    if err != nil {
+        s.log.Errorw("failed to get api key", "keyId", keyId, "error", err)
+        return nil, err
+    }
+
+    return &key, nil
+}
+
+// GetApiKeys retrieves the API keys of a user, oldest first
+func (s *SqliteDataAccess) GetApiKeys(ctx context.Context, username string) ([]model.ApiKey, error) {
+    rows, err := s.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE username =
+        (SELECT username FROM users WHERE username_key = ?) ORDER BY created_at, key_id`, model.CanonicalKey(username))
+    if err != nil {
+        return nil, fmt.Errorf("failed to query api keys: %w", err)
+    }
+    defer rows.Close()
+
+    keys := []model.ApiKey{}
+    for rows.Next() {
+        key, err := scanApiKey(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan api key: %w", err)
+        }
+        keys = append(keys, key)
+    }
+
+    return keys, rows.Err()
+}
+
+// DeleteApiKey revokes an API key by its ID
+func (s *SqliteDataAccess) DeleteApiKey(ctx context.Context, keyId string) error {
+    _, err := s.db.ExecContext(ctx, `DELETE FROM api_keys WHERE key_id = ?`, keyId)
+    if err != nil {
+        return fmt.Errorf("failed to delete api key: %w", err)
+    }
+
+    return nil
+}
+
+func scanApiKey(row rowScanner) (model.ApiKey, error) {
+    var key model.ApiKey
+    var createdAt, expiresAt int64
+    err := row.Scan(&key.KeyId, &key.SecretHash, &key.Username, &key.Scope, &createdAt, &expiresAt)
+    if err != nil {
+        return model.ApiKey{}, err
+    }
+    key.CreatedAt = time.Unix(createdAt, 0)
+    key.ExpiresAt = time.Unix(expiresAt, 0)
+    return key, nil
+}
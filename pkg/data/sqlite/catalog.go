@@ -0,0 +1,351 @@
+package sqlite
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+)
+
+// subtreeCondition matches a category column against a category and its subcategories, whose paths sort between the
+// category followed by the separator and by the next character
+func subtreeCondition(column string, category string) (string, []any) {
+    return `(` + column + ` = ? OR (` + column + ` > ? AND ` + column + ` < ?))`,
+        []any{category, category + model.CategorySeparator, category + "0"}
+}
+
+// PutCategory adds an active category to the catalog
+// Returns nil if the category already exists
+func (s *SqliteDataAccess) PutCategory(ctx context.Context, name string) (*model.Category, error) {
+    category, err := model.NewCategory(name)
+    if err != nil {
+        return nil, err
+    }
+
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    category.Name, err = model.NewCategoryPath(name, lookupCategory(ctx, tx))
+    if err != nil {
+        return nil, err
+    }
+
+    // a conflict on either the name or its canonical key means the category exists
+    result, err := tx.ExecContext(ctx, `INSERT INTO categories (name, status, name_key) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+        category.Name, category.Status, category.Key())
+    if err != nil {
+        return nil, fmt.Errorf("failed to insert category: %w", err)
+    }
+    inserted, err := result.RowsAffected()
+    if err != nil {
+        return nil, err
+    }
+    if inserted == 0 {
+        return nil, nil
+    }
+
+    err = tx.Commit()
+    if err != nil {
+        return nil, err
+    }
+
+    return &category, nil
+}
+
+// GetCategories retrieves the catalog sorted by category name
+func (s *SqliteDataAccess) GetCategories(ctx context.Context) ([]model.Category, error) {
+    rows, err := s.db.QueryContext(ctx, `SELECT name, status FROM categories ORDER BY name`)
+    if err != nil {
+        s.log.Errorf("failed to query categories: %v", err)
+        return nil, err
+    }
+    return scanCategories(rows)
+}
+
+// LookupCategory retrieves the catalog entry of a category by its canonical key
+// Returns nil if the category is not in the catalog
+func (s *SqliteDataAccess) LookupCategory(ctx context.Context, name string) (*model.Category, error) {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    return lookupCategory(ctx, tx)(name)
+}
+
+// RetireCategory marks a category as retired
+func (s *SqliteDataAccess) RetireCategory(ctx context.Context, name string) error {
+    result, err := s.db.ExecContext(ctx, `UPDATE categories SET status = ? WHERE name_key = ?`, enum.CategoryStatusRetired, model.CanonicalKey(name))
+    if err != nil {
+        return fmt.Errorf("failed to retire category: %w", err)
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if rowsAffected == 0 {
+        return exception.NewCategoryDoesNotExistException(fmt.Sprintf("category '%s' does not exist", name), nil)
+    }
+
+    return nil
+}
+
+// RenameCategory renames a category and its subcategories, moving their listings and counts in one transaction
+func (s *SqliteDataAccess) RenameCategory(ctx context.Context, from string, to string) error {
+    return s.moveCategory(ctx, from, to, false)
+}
+
+// MergeCategory moves the subtree of a category into another category in one transaction
+func (s *SqliteDataAccess) MergeCategory(ctx context.Context, from string, to string) error {
+    return s.moveCategory(ctx, from, to, true)
+}
+
+// moveCategory moves the catalog entries, listings and counts of the subtree of from to to. Entries that already
+// exist under to keep their status.
+func (s *SqliteDataAccess) moveCategory(ctx context.Context, from string, to string, merge bool) error {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer rollback(tx)
+
+    entry, err := model.RequireCategory(from, lookupCategory(ctx, tx))
+    if err != nil {
+        return err
+    }
+    from = entry.Name
+
+    condition, args := subtreeCondition("name", from)
+    rows, err := tx.QueryContext(ctx, `SELECT name, status FROM categories WHERE `+condition, args...)
+    if err != nil {
+        return err
+    }
+    subtree, err := scanCategories(rows)
+    if err != nil {
+        return err
+    }
+    to, err = model.CheckCategoryMove(from, to, merge, subtree, lookupCategory(ctx, tx))
+    if err != nil {
+        return err
+    }
+
+    for _, category := range subtree {
+        category.Name = model.MoveCategoryPath(category.Name, from, to)
+        _, err = tx.ExecContext(ctx, `INSERT INTO categories (name, status, name_key) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+            category.Name, category.Status, category.Key())
+        if err != nil {
+            return fmt.Errorf("failed to insert category: %w", err)
+        }
+    }
+    _, err = tx.ExecContext(ctx, `DELETE FROM categories WHERE `+condition, args...)
+    if err != nil {
+        return err
+    }
+
+    // the counts move with the active listings of each category of the subtree
+    condition, args = subtreeCondition("category", from)
+    counts, err := countActiveListings(ctx, tx, condition, args)
+    if err != nil {
+        return err
+    }
+    for category, count := range counts {
+        err = addCategoryCount(ctx, tx, category, -count)
+        if err != nil {
+            return err
+        }
+        err = addCategoryCount(ctx, tx, model.MoveCategoryPath(category, from, to), count)
+        if err != nil {
+            return err
+        }
+    }
+    _, err = tx.ExecContext(ctx, `DELETE FROM category_metrics WHERE `+condition, args...)
+    if err != nil {
+        return err
+    }
+
+    _, err = tx.ExecContext(ctx, `UPDATE listings SET category = ? || substr(category, ?), version = version + 1 WHERE `+condition,
+        append([]any{to, len(from) + 1}, args...)...)
+    if err != nil {
+        return fmt.Errorf("failed to move listings: %w", err)
+    }
+
+    return tx.Commit()
+}
+
+// countActiveListings counts the active listings of each category matching condition
+func countActiveListings(ctx context.Context, tx *sql.Tx, condition string, args []any) (map[string]int, error) {
+    rows, err := tx.QueryContext(ctx, `SELECT category, COUNT(*) FROM listings WHERE status = ? AND `+condition+` GROUP BY category`,
+        append([]any{enum.ListingStatusActive}, args...)...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    counts := make(map[string]int)
+    for rows.Next() {
+        var category string
+        var count int
+        err = rows.Scan(&category, &count)
+        if err != nil {
+            return nil, err
+        }
+        counts[category] = count
+    }
+    return counts, rows.Err()
+}
+
+// lookupCategory retrieves catalog entries by their canonical key within the transaction
+func lookupCategory(ctx context.Context, tx *sql.Tx) model.CategoryLookup {
+    return func(name string) (*model.Category, error) {
+        var category model.Category
+        err := tx.QueryRowContext(ctx, `SELECT name, status FROM categories WHERE name_key = ?`, model.CanonicalKey(name)).
+            Scan(&category.Name, &category.Status)
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        return &category, nil
+    }
+}
+
+func scanCategories(rows *sql.Rows) ([]model.Category, error) {
+    defer rows.Close()
+
+    categories := []model.Category{}
+    for rows.Next() {
+        var category model.Category
+        err := rows.Scan(&category.Name, &category.Status)
+        if err != nil {
+            return nil, err
+        }
+        categories = append(categories, category)
+    }
+    return categories, rows.Err()
+}
+
+// seedCategories adds the categories of the existing listings and counts, and their ancestors, to the catalog
+func seedCategories(ctx context.Context, tx *sql.Tx) error {
+    rows, err := tx.QueryContext(ctx, `SELECT category FROM listings UNION SELECT category FROM category_metrics`)
+    if err != nil {
+        return err
+    }
+    var categories []string
+    for rows.Next() {
+        var category string
+        err = rows.Scan(&category)
+        if err != nil {
+            _ = rows.Close()
+            return err
+        }
+        categories = append(categories, category)
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return err
+    }
+
+    for _, category := range categories {
+        for _, ancestor := range model.CategoryAncestors(category) {
+            _, err = tx.ExecContext(ctx, `INSERT INTO categories (name) VALUES (?) ON CONFLICT (name) DO NOTHING`, ancestor)
+            if err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// mergeCategorySpellings keys the catalog by canonical category names. The catalog entries, listings and counts of
+// categories spelled with differing cases are merged under the spelling chosen by model.CategorySpellings, and each
+// merged entry keeps the status of its first spelling in byte order.
+func mergeCategorySpellings(ctx context.Context, tx *sql.Tx) error {
+    rows, err := tx.QueryContext(ctx, `SELECT name, status FROM categories ORDER BY name`)
+    if err != nil {
+        return err
+    }
+    entries, err := scanCategories(rows)
+    if err != nil {
+        return err
+    }
+    rows, err = tx.QueryContext(ctx, `SELECT DISTINCT category FROM listings`)
+    if err != nil {
+        return err
+    }
+    var categories []string
+    for rows.Next() {
+        var category string
+        err = rows.Scan(&category)
+        if err != nil {
+            _ = rows.Close()
+            return err
+        }
+        categories = append(categories, category)
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return err
+    }
+    for _, entry := range entries {
+        categories = append(categories, entry.Name)
+    }
+    spellings := model.CategorySpellings(categories)
+
+    merged := make(map[string]model.Category)
+    for _, entry := range entries {
+        key := entry.Key()
+        if _, exists := merged[key]; !exists {
+            merged[key] = model.Category{Name: spellings[entry.Name], Status: entry.Status}
+        }
+    }
+    for _, spelling := range spellings {
+        key := model.CanonicalKey(spelling)
+        if _, exists := merged[key]; !exists {
+            merged[key] = model.Category{Name: spelling, Status: enum.CategoryStatusActive}
+        }
+    }
+    _, err = tx.ExecContext(ctx, `DELETE FROM categories`)
+    if err != nil {
+        return err
+    }
+    for _, category := range merged {
+        _, err = tx.ExecContext(ctx, `INSERT INTO categories (name, status, name_key) VALUES (?, ?, ?)`,
+            category.Name, category.Status, category.Key())
+        if err != nil {
+            return err
+        }
+    }
+    _, err = tx.ExecContext(ctx, `CREATE UNIQUE INDEX categories_name_key ON categories (name_key)`)
+    if err != nil {
+        return err
+    }
+
+    respelled := false
+    for category, spelling := range spellings {
+        if category == spelling {
+            continue
+        }
+        respelled = true
+        _, err = tx.ExecContext(ctx, `UPDATE listings SET category = ?, version = version + 1 WHERE category = ?`, spelling, category)
+        if err != nil {
+            return fmt.Errorf("failed to move listings: %w", err)
+        }
+    }
+    if !respelled {
+        return nil
+    }
+    // the counts of the other spellings are dropped and counted again under the chosen ones
+    _, err = tx.ExecContext(ctx, `DELETE FROM category_metrics WHERE category NOT IN (SELECT name FROM categories)`)
+    if err != nil {
+        return err
+    }
+    return rollUpCategoryCounts(ctx, tx)
+}
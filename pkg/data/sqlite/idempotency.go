@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+    "context"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "time"
+)
+
+// PutIdempotencyRecord stores a record holding its key, replacing a record that expired by record.CreatedAt. The
+// insert is a single statement, so processes sharing the database file cannot both take the key.
+// Returns the record holding the key, or nil if record was stored
+func (s *SqliteDataAccess) PutIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+    result, err := s.db.ExecContext(ctx, `INSERT INTO idempotency_records
+        (record_key, request_hash, response, created_at, expires_at) VALUES (?, ?, NULL, ?, ?)
+        ON CONFLICT (record_key) DO UPDATE SET request_hash = excluded.request_hash, response = NULL,
+            created_at = excluded.created_at, expires_at = excluded.expires_at
+        WHERE idempotency_records.expires_at <= excluded.created_at`,
+        record.Key, record.RequestHash, record.CreatedAt.UnixMilli(), record.ExpiresAt.UnixMilli())
+    if err != nil {
+        return nil, fmt.Errorf("failed to put idempotency record: %w", err)
+    }
+    stored, err := result.RowsAffected()
+    if err != nil {
+        return nil, err
+    }
+    if stored > 0 {
+        return nil, nil
+    }
+
+    existing := model.IdempotencyRecord{Key: record.Key}
+    var createdAt, expiresAt int64
+    err = s.db.QueryRowContext(ctx, `SELECT request_hash, response, created_at, expires_at FROM idempotency_records
+        WHERE record_key = ?`, record.Key).Scan(&existing.RequestHash, &existing.Response, &createdAt, &expiresAt)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+    }
+    existing.CreatedAt = time.UnixMilli(createdAt)
+    existing.ExpiresAt = time.UnixMilli(expiresAt)
+
+    return &existing, nil
+}
+
+// CompleteIdempotencyRecord stores the response and the new expiry of a record
+func (s *SqliteDataAccess) CompleteIdempotencyRecord(ctx context.Context, record model.IdempotencyRecord) error {
+    _, err := s.db.ExecContext(ctx, `UPDATE idempotency_records SET response = ?, expires_at = ? WHERE record_key = ?`,
+        record.Response, record.ExpiresAt.UnixMilli(), record.Key)
+    if err != nil {
+        return fmt.Errorf("failed to complete idempotency record: %w", err)
+    }
+    return nil
+}
+
+// DeleteIdempotencyRecord releases the key of a record without a response
+func (s *SqliteDataAccess) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+    _, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_records WHERE record_key = ? AND response IS NULL`, key)
+    if err != nil {
+        return fmt.Errorf("failed to delete idempotency record: %w", err)
+    }
+    return nil
+}
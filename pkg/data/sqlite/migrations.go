@@ -0,0 +1,323 @@
+package sqlite
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+)
+
+// migration is a versioned schema change. Migrations are applied in order and never edited once released.
+type migration struct {
+    version     int
+    description string
+    statements  []string
+    // backfill migrates existing data that cannot be derived in SQL, in the transaction of the statements
+    backfill func(ctx context.Context, tx *sql.Tx) error
+}
+
+var migrations = []migration{
+    {
+        version:     1,
+        description: "create users, listings and category_metrics tables",
+        statements: []string{
+            `CREATE TABLE users (
+                username TEXT NOT NULL PRIMARY KEY
+            )`,
+            `CREATE TABLE listings (
+                listing_id  INTEGER NOT NULL PRIMARY KEY,
+                username    TEXT    NOT NULL REFERENCES users (username),
+                title       TEXT    NOT NULL,
+                description TEXT    NOT NULL,
+                price       INTEGER NOT NULL CHECK (price >= 0),
+                category    TEXT    NOT NULL,
+                created_at  INTEGER NOT NULL
+            )`,
+            `CREATE INDEX listings_category_price ON listings (category, price)`,
+            `CREATE INDEX listings_category_created_at ON listings (category, created_at)`,
+            `CREATE TABLE category_metrics (
+                category       TEXT    NOT NULL PRIMARY KEY,
+                category_count INTEGER NOT NULL DEFAULT 0
+            )`,
+            `CREATE INDEX category_metrics_category_count ON category_metrics (category_count)`,
+        },
+    },
+    {
+        version:     2,
+        description: "allocate listing IDs from a counter instead of the current maximum",
+        statements: []string{
+            `CREATE TABLE sequences (
+                name       TEXT    NOT NULL PRIMARY KEY,
+                last_value INTEGER NOT NULL
+            )`,
+            `INSERT INTO sequences (name, last_value) SELECT 'listing_id', MAX(listing_id) FROM listings HAVING COUNT(*) > 0`,
+        },
+    },
+    {
+        version:     3,
+        description: "add listing version for optimistic concurrency",
+        statements: []string{
+            `ALTER TABLE listings ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+        },
+    },
+    {
+        version:     4,
+        description: "add listing status and orders table",
+        statements: []string{
+            `ALTER TABLE listings ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE'`,
+            // a listing can be bought only once, so the listing ID identifies the order
+            `CREATE TABLE orders (
+                listing_id INTEGER NOT NULL PRIMARY KEY,
+                title      TEXT    NOT NULL,
+                price      INTEGER NOT NULL,
+                seller     TEXT    NOT NULL REFERENCES users (username),
+                buyer      TEXT    NOT NULL REFERENCES users (username) CHECK (buyer <> seller),
+                created_at INTEGER NOT NULL
+            )`,
+            `CREATE INDEX orders_seller ON orders (seller)`,
+            `CREATE INDEX orders_buyer ON orders (buyer)`,
+        },
+    },
+    {
+        version:     5,
+        description: "add the user holding a reserved listing",
+        statements: []string{
+            `ALTER TABLE listings ADD COLUMN reserved_by TEXT NOT NULL DEFAULT ''`,
+        },
+    },
+    {
+        version:     6,
+        description: "add search index over listing titles and descriptions",
+        statements: []string{
+            // the distinct analyzed terms of each listing
+            `CREATE TABLE search_terms (
+                term       TEXT    NOT NULL,
+                listing_id INTEGER NOT NULL REFERENCES listings (listing_id),
+                PRIMARY KEY (term, listing_id)
+            ) WITHOUT ROWID`,
+            `CREATE INDEX search_terms_listing_id ON search_terms (listing_id)`,
+            // the number of analyzed terms of each listing, for BM25 length normalization
+            `CREATE TABLE search_documents (
+                listing_id INTEGER NOT NULL PRIMARY KEY REFERENCES listings (listing_id),
+                length     INTEGER NOT NULL
+            )`,
+        },
+        backfill: func(ctx context.Context, tx *sql.Tx) error {
+            _, err := rebuildSearchIndex(ctx, tx)
+            return err
+        },
+    },
+    {
+        version:     7,
+        description: "roll up category counts to parent categories",
+        backfill:    rollUpCategoryCounts,
+    },
+    {
+        version:     8,
+        description: "add category catalog",
+        statements: []string{
+            `CREATE TABLE categories (
+                name   TEXT NOT NULL PRIMARY KEY,
+                status TEXT NOT NULL DEFAULT 'ACTIVE'
+            )`,
+        },
+        // categories already in use stay open
+        backfill: seedCategories,
+    },
+    {
+        version:     9,
+        description: "make usernames unique by their canonical key",
+        statements: []string{
+            `ALTER TABLE users ADD COLUMN username_key TEXT`,
+        },
+        backfill: addUsernameKeys,
+    },
+    {
+        version:     10,
+        description: "make categories unique by their canonical key",
+        statements: []string{
+            `ALTER TABLE categories ADD COLUMN name_key TEXT`,
+        },
+        // categories spelled with differing cases are merged into one spelling
+        backfill: mergeCategorySpellings,
+    },
+    {
+        version:     11,
+        description: "add user passwords and sessions",
+        statements: []string{
+            // users registered before passwords existed keep an empty hash until they register again
+            `ALTER TABLE users ADD COLUMN password_hash TEXT NOT NULL DEFAULT ''`,
+            `CREATE TABLE sessions (
+                token_hash TEXT    NOT NULL PRIMARY KEY,
+                username   TEXT    NOT NULL REFERENCES users (username),
+                created_at INTEGER NOT NULL,
+                expires_at INTEGER NOT NULL
+            )`,
+        },
+    },
+    {
+        version:     12,
+        description: "add user roles",
+        statements: []string{
+            // an empty role is a regular user
+            `ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT ''`,
+        },
+    },
+    {
+        version:     13,
+        description: "add api keys",
+        statements: []string{
+            `CREATE TABLE api_keys (
+                key_id      TEXT    NOT NULL PRIMARY KEY,
+                secret_hash TEXT    NOT NULL,
+                username    TEXT    NOT NULL REFERENCES users (username),
+                scope       TEXT    NOT NULL,
+                created_at  INTEGER NOT NULL,
+                expires_at  INTEGER NOT NULL
+            )`,
+            `CREATE INDEX api_keys_username ON api_keys (username)`,
+        },
+    },
+    {
+        version:     14,
+        description: "add rate limit buckets",
+        statements: []string{
+            `CREATE TABLE rate_limit_buckets (
+                bucket_key TEXT    NOT NULL PRIMARY KEY,
+                tokens     REAL    NOT NULL,
+                updated_at INTEGER NOT NULL
+            )`,
+        },
+    },
+    {
+        version:     15,
+        description: "add idempotency records",
+        statements: []string{
+            `CREATE TABLE idempotency_records (
+                record_key   TEXT    NOT NULL PRIMARY KEY,
+                request_hash TEXT    NOT NULL,
+                response     BLOB,
+                created_at   INTEGER NOT NULL,
+                expires_at   INTEGER NOT NULL
+            )`,
+        },
+    },
+}
+
+// Migrate brings the schema up to the latest version, applying each pending migration in its own transaction
+func (s *SqliteDataAccess) Migrate(ctx context.Context) error {
+    return s.migrateTo(ctx, migrations[len(migrations)-1].version)
+}
+
+// migrateTo applies the pending migrations up to version target
+func (s *SqliteDataAccess) migrateTo(ctx context.Context, target int) error {
+    _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version     INTEGER NOT NULL PRIMARY KEY,
+        description TEXT    NOT NULL,
+        applied_at  INTEGER NOT NULL
+    )`)
+    if err != nil {
+        return fmt.Errorf("failed to create schema_migrations table: %w", err)
+    }
+
+    current, err := s.SchemaVersion(ctx)
+    if err != nil {
+        return err
+    }
+
+    for _, m := range migrations {
+        if m.version <= current || m.version > target {
+            continue
+        }
+        s.log.Infof("Applying schema migration %d: %s", m.version, m.description)
+        err = s.applyMigration(ctx, m)
+        if err != nil {
+            return fmt.Errorf("failed to apply schema migration %d: %w", m.version, err)
+        }
+    }
+
+    return nil
+}
+
+// SchemaVersion returns the latest applied migration version, or 0 for an empty database
+func (s *SqliteDataAccess) SchemaVersion(ctx context.Context) (int, error) {
+    var version int
+    err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+    if err != nil {
+        return 0, fmt.Errorf("failed to read schema version: %w", err)
+    }
+    return version, nil
+}
+
+// Reset drops every table and all its data. Run Migrate afterwards to recreate the schema.
+func (s *SqliteDataAccess) Reset(ctx context.Context) error {
+    rows, err := s.db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+    if err != nil {
+        return err
+    }
+    var tables []string
+    for rows.Next() {
+        var table string
+        err = rows.Scan(&table)
+        if err != nil {
+            _ = rows.Close()
+            return err
+        }
+        tables = append(tables, table)
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return err
+    }
+
+    // tables are dropped in arbitrary order, so foreign keys are suspended meanwhile
+    _, err = s.db.ExecContext(ctx, `PRAGMA foreign_keys = OFF`)
+    if err != nil {
+        return err
+    }
+    defer s.db.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+
+    for _, table := range tables {
+        s.log.Infof("Dropping table %s", table)
+        _, err = s.db.ExecContext(ctx, `DROP TABLE "` + table + `"`)
+        if err != nil {
+            return fmt.Errorf("failed to drop table %s: %w", table, err)
+        }
+    }
+
+    return nil
+}
+
+func (s *SqliteDataAccess) applyMigration(ctx context.Context, m migration) error {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer rollback(tx)
+
+    for _, statement := range m.statements {
+        _, err = tx.ExecContext(ctx, statement)
+        if err != nil {
+            return err
+        }
+    }
+    if m.backfill != nil {
+        err = m.backfill(ctx, tx)
+        if err != nil {
+            return err
+        }
+    }
+
+    _, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)`,
+        m.version, m.description, time.Now().Unix())
+    if err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// rollback aborts a transaction unless it has already been committed
+func rollback(tx *sql.Tx) {
+    _ = tx.Rollback()
+}
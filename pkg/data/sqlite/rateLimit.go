@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+    "context"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "time"
+)
+
+// TakeRateLimitToken takes a token from the rate limit bucket under key. The transaction writes before it reads, so
+// that it holds the database write lock while the bucket is refilled and the processes sharing the database file take
+// tokens one at a time.
+// Returns false if the bucket is empty
+func (s *SqliteDataAccess) TakeRateLimitToken(ctx context.Context, key string, limit model.RateLimit, now time.Time) (bool, error) {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return false, err
+    }
+    defer rollback(tx)
+
+    bucket := model.NewRateLimitBucket(limit, now)
+    _, err = tx.ExecContext(ctx, `INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at) VALUES (?, ?, ?)
+        ON CONFLICT (bucket_key) DO NOTHING`, key, bucket.Tokens, bucket.UpdatedAt.UnixMilli())
+    if err != nil {
+        return false, fmt.Errorf("failed to create rate limit bucket: %w", err)
+    }
+
+    var updatedAt int64
+    err = tx.QueryRowContext(ctx, `SELECT tokens, updated_at FROM rate_limit_buckets WHERE bucket_key = ?`, key).Scan(
+        &bucket.Tokens, &updatedAt)
+    if err != nil {
+        return false, fmt.Errorf("failed to get rate limit bucket: %w", err)
+    }
+    bucket.UpdatedAt = time.UnixMilli(updatedAt)
+
+    bucket, allowed := bucket.Take(limit, now)
+    _, err = tx.ExecContext(ctx, `UPDATE rate_limit_buckets SET tokens = ?, updated_at = ? WHERE bucket_key = ?`,
+        bucket.Tokens, bucket.UpdatedAt.UnixMilli(), key)
+    if err != nil {
+        return false, fmt.Errorf("failed to update rate limit bucket: %w", err)
+    }
+
+    return allowed, tx.Commit()
+}
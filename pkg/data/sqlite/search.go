@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+    "context"
+    "database/sql"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/search"
+    "strings"
+)
+
+// SearchListings retrieves the active listings matching the query text, ranked by BM25 relevance
+func (s *SqliteDataAccess) SearchListings(ctx context.Context, query model.SearchQuery) ([]model.Listing, error) {
+    queryTerms := search.UniqueTerms(search.Analyze(query.Text))
+    if len(queryTerms) == 0 {
+        return nil, nil
+    }
+
+    // read the statistics and the candidates in one transaction so that they agree
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    corpus := search.Corpus{Frequencies: make(map[string]int, len(queryTerms))}
+    err = tx.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(length), 0) FROM search_documents`).Scan(&corpus.Documents, &corpus.TotalLength)
+    if err != nil {
+        s.log.Errorf("failed to read search corpus: %v", err)
+        return nil, err
+    }
+
+    placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(queryTerms)), ", ")
+    args := make([]any, len(queryTerms))
+    for i, term := range queryTerms {
+        args[i] = term
+    }
+    rows, err := tx.QueryContext(ctx, `SELECT term, COUNT(*) FROM search_terms WHERE term IN (`+placeholders+`) GROUP BY term`, args...)
+    if err != nil {
+        s.log.Errorf("failed to read search term frequencies: %v", err)
+        return nil, err
+    }
+    for rows.Next() {
+        var term string
+        var frequency int
+        err = rows.Scan(&term, &frequency)
+        if err != nil {
+            _ = rows.Close()
+            return nil, err
+        }
+        corpus.Frequencies[term] = frequency
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return nil, err
+    }
+
+    rows, err = tx.QueryContext(ctx, `SELECT `+listingColumns+` FROM listings WHERE listing_id IN (
+        SELECT DISTINCT listing_id FROM search_terms WHERE term IN (`+placeholders+`))`, args...)
+    if err != nil {
+        s.log.Errorf("failed to query search candidates: %v", err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    listings := make(map[int]model.Listing)
+    var documents []search.Document
+    for rows.Next() {
+        listing, err := scanListing(rows)
+        if err != nil {
+            s.log.Errorf("failed to scan listing: %v", err)
+            return nil, err
+        }
+        if query.Matches(listing) {
+            listings[listing.ListingId] = listing
+            documents = append(documents, search.Document{ListingId: listing.ListingId, Terms: search.DocumentTerms(listing.Title, listing.Description)})
+        }
+    }
+    if err = rows.Err(); err != nil {
+        return nil, err
+    }
+
+    results := search.Rank(queryTerms, documents, corpus)
+    if query.Limit > 0 && len(results) > query.Limit {
+        results = results[:query.Limit]
+    }
+    ranked := make([]model.Listing, 0, len(results))
+    for _, result := range results {
+        ranked = append(ranked, listings[result.ListingId])
+    }
+    return ranked, nil
+}
+
+// RebuildSearchIndex replaces the search index with one built from every listing
+func (s *SqliteDataAccess) RebuildSearchIndex(ctx context.Context) (int, error) {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return 0, err
+    }
+    defer rollback(tx)
+
+    indexed, err := rebuildSearchIndex(ctx, tx)
+    if err != nil {
+        s.log.Errorf("failed to rebuild search index: %v", err)
+        return 0, err
+    }
+
+    return indexed, tx.Commit()
+}
+
+// rebuildSearchIndex clears the search index and indexes every listing, returning the number of listings indexed
+func rebuildSearchIndex(ctx context.Context, tx *sql.Tx) (int, error) {
+    _, err := tx.ExecContext(ctx, `DELETE FROM search_terms`)
+    if err != nil {
+        return 0, err
+    }
+    _, err = tx.ExecContext(ctx, `DELETE FROM search_documents`)
+    if err != nil {
+        return 0, err
+    }
+
+    rows, err := tx.QueryContext(ctx, `SELECT ` + listingColumns + ` FROM listings`)
+    if err != nil {
+        return 0, err
+    }
+    var listings []model.Listing
+    for rows.Next() {
+        listing, err := scanListing(rows)
+        if err != nil {
+            _ = rows.Close()
+            return 0, err
+        }
+        listings = append(listings, listing)
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return 0, err
+    }
+
+    for _, listing := range listings {
+        err = indexListing(ctx, tx, listing)
+        if err != nil {
+            return 0, err
+        }
+    }
+    return len(listings), nil
+}
+
+// indexListing adds the terms and length of a listing to the search index
+func indexListing(ctx context.Context, tx *sql.Tx, listing model.Listing) error {
+    terms := search.DocumentTerms(listing.Title, listing.Description)
+    for _, term := range search.UniqueTerms(terms) {
+        _, err := tx.ExecContext(ctx, `INSERT INTO search_terms (term, listing_id) VALUES (?, ?)`, term, listing.ListingId)
+        if err != nil {
+            return err
+        }
+    }
+    _, err := tx.ExecContext(ctx, `INSERT INTO search_documents (listing_id, length) VALUES (?, ?)`, listing.ListingId, len(terms))
+    return err
+}
+
+// unindexListing removes a listing from the search index
+func unindexListing(ctx context.Context, tx *sql.Tx, listingId int) error {
+    _, err := tx.ExecContext(ctx, `DELETE FROM search_terms WHERE listing_id = ?`, listingId)
+    if err != nil {
+        return err
+    }
+    _, err = tx.ExecContext(ctx, `DELETE FROM search_documents WHERE listing_id = ?`, listingId)
+    return err
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "time"
+)
+
+// PutSession stores a session
+func (s *SqliteDataAccess) PutSession(ctx context.Context, session model.Session) error {
+    _, err := s.db.ExecContext(ctx, `INSERT INTO sessions (token_hash, username, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+        session.TokenHash, session.Username, session.CreatedAt.Unix(), session.ExpiresAt.Unix())
+    if err != nil {
+        return fmt.Errorf("failed to insert session: %w", err)
+    }
+
+    return nil
+}
+
+// GetSession retrieves a session by the hash of its token
+// Returns nil if the session does not exist
+func (s *SqliteDataAccess) GetSession(ctx context.Context, tokenHash string) (*model.Session, error) {
+    session := model.Session{TokenHash: tokenHash}
+    var createdAt, expiresAt int64
+    err := s.db.QueryRowContext(ctx, `SELECT username, created_at, expires_at FROM sessions WHERE token_hash = ?`, tokenHash).
+        Scan(&session.Username, &createdAt, &expiresAt)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, nil
+    }
+    if err != nil {
+        s.log.Errorw("failed to get session", "error", err)
+        return nil, err
+    }
+    session.CreatedAt = time.Unix(createdAt, 0)
+    session.ExpiresAt = time.Unix(expiresAt, 0)
+
+    return &session, nil
+}
+
+// DeleteSession revokes a session by the hash of its token
+func (s *SqliteDataAccess) DeleteSession(ctx context.Context, tokenHash string) error {
+    _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ?`, tokenHash)
+    if err != nil {
+        return fmt.Errorf("failed to delete session: %w", err)
+    }
+
+    return nil
+}
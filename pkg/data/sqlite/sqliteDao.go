@@ -0,0 +1,714 @@
+package sqlite
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "os"
+    "time"
+
+    _ "modernc.org/sqlite"
+)
+
+const listingColumns = `listing_id, username, title, description, price, category, created_at, version, status, reserved_by`
+
+// SqliteDataAccess is the embedded SQLite implementation of data.MarketplaceStore, meant for single-node deployments
+// and ad-hoc reporting
+type SqliteDataAccess struct {
+    db  *sql.DB
+    log *zap.SugaredLogger
+}
+
+var _ data.MarketplaceStore = (*SqliteDataAccess)(nil)
+
+func NewSqliteDataAccess(log *zap.SugaredLogger) *SqliteDataAccess {
+    // try to get the database path from the environment variable
+    path, pathExists := os.LookupEnv(constant.SqlitePathEnvKey)
+    if !pathExists {
+        path = "marketplace.db"
+    }
+    log.Debug("SQLite database path: " + path)
+
+    db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+    if err != nil {
+        log.Fatalf("unable to open SQLite database, %v", err)
+    }
+    // SQLite allows a single writer; serializing connections keeps read-modify-write transactions race free
+    db.SetMaxOpenConns(1)
+
+    return &SqliteDataAccess{
+        db:  db,
+        log: log,
+    }
+}
+
+// PutUser a new user, or sets the password hash of a user registered without one
+// Returns nil if the user already exists with a password
+func (s *SqliteDataAccess) PutUser(ctx context.Context, username string, passwordHash string) (*model.User, error) {
+    user := model.User{Username: username, PasswordHash: passwordHash}
+
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    // a conflict on either the username or its canonical key means the user exists
+    result, err := tx.ExecContext(ctx, `INSERT INTO users (username, username_key, password_hash) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+        username, user.Key(), passwordHash)
+    if err != nil {
+        return nil, fmt.Errorf("failed to insert user: %w", err)
+    }
+    inserted, err := result.RowsAffected()
+    if err != nil {
+        return nil, err
+    }
+    if inserted == 0 {
+        err = tx.QueryRowContext(ctx, `UPDATE users SET password_hash = ? WHERE username_key = ? AND password_hash = '' RETURNING username, role`,
+            passwordHash, user.Key()).Scan(&user.Username, &user.Role)
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, nil
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to set password: %w", err)
+        }
+    }
+
+    err = tx.Commit()
+    if err != nil {
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+// GetUser retrieves a user by username
+// Returns nil if the user does not exist
+func (s *SqliteDataAccess) GetUser(ctx context.Context, username string) (*model.User, error) {
+    var user model.User
+    err := s.db.QueryRowContext(ctx, `SELECT username, password_hash, role FROM users WHERE username_key = ?`, model.CanonicalKey(username)).
+        Scan(&user.Username, &user.PasswordHash, &user.Role)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, nil
+    }
+    if err != nil {
+        s.log.Errorw("failed to get user", "username", username, "error", err)
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+// SetUserRole sets the role of a user
+// Returns nil if the user does not exist
+func (s *SqliteDataAccess) SetUserRole(ctx context.Context, username string, role enum.Role) (*model.User, error) {
+    user := model.User{Role: role}
+    err := s.db.QueryRowContext(ctx, `UPDATE users SET role = ? WHERE username_key = ? RETURNING username, password_hash`,
+        role, model.CanonicalKey(username)).Scan(&user.Username, &user.PasswordHash)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, nil
+    }
+    if err != nil {
+        s.log.Errorw("failed to set user role", "username", username, "error", err)
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+// PutListing puts a listing and, in the same transaction, increments the category count if it is active and indexes
+// the listing for search
+func (s *SqliteDataAccess) PutListing(
+    ctx context.Context,
+    username string,
+    title string,
+    description string,
+    price int,
+    category string,
+    status enum.ListingStatus,
+) (*model.Listing, error) {
+    // validate before allocating an ID so that rejected input does not consume one
+    listing, err := model.NewListing(constant.FirstListingId, username, title, description, price, category, status)
+    if err != nil {
+        s.log.Error("failed to create new listing: ", err)
+        return nil, err
+    }
+
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    entry, err := model.CheckCategoryOpen(category, lookupCategory(ctx, tx))
+    if err != nil {
+        return nil, err
+    }
+    listing.Category = entry.Name
+
+    listing.ListingId, err = nextListingId(ctx, tx)
+    if err != nil {
+        s.log.Error("failed to get next listing id: ", err)
+        return nil, err
+    }
+
+    _, err = tx.ExecContext(ctx, `INSERT INTO listings (`+listingColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        listing.ListingId, listing.Username, listing.Title, listing.Description, listing.Price, listing.Category,
+        listing.CreatedAt.Unix(), listing.Version, listing.Status, listing.ReservedBy)
+    if err != nil {
+        s.log.Errorf("failed to insert listing %d: %v", listing.ListingId, err)
+        return nil, err
+    }
+
+    if listing.IsActive() {
+        err = addCategoryCount(ctx, tx, listing.Category, 1)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    err = indexListing(ctx, tx, listing)
+    if err != nil {
+        s.log.Errorf("failed to index listing %d: %v", listing.ListingId, err)
+        return nil, err
+    }
+
+    err = tx.Commit()
+    if err != nil {
+        return nil, err
+    }
+
+    return &listing, nil
+}
+
+// GetListing retrieves a listing by listingId
+// Returns nil if the listing does not exist
+func (s *SqliteDataAccess) GetListing(ctx context.Context, listingId int) (*model.Listing, error) {
+    row := s.db.QueryRowContext(ctx, `SELECT `+listingColumns+` FROM listings WHERE listing_id = ?`, listingId)
+    listing, err := scanListing(row)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, nil
+    }
+    if err != nil {
+        s.log.Errorf("failed to query listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    return &listing, nil
+}
+
+// GetCategory retrieves a page of the active listings of a specified category and its subcategories sorted by price or
+// creation time. Pages are keyed by the sort column and listing ID of the last listing, so they stay consistent under inserts.
+func (s *SqliteDataAccess) GetCategory(ctx context.Context, query model.CategoryQuery) (*model.ListingPage, error) {
+    cursor, err := data.DecodeKeysetCursor(query.Cursor, query.Category, query.SortBy)
+    if err != nil {
+        return nil, err
+    }
+
+    sortColumn := "price"
+    if query.SortBy == enum.SortByCreatedAt {
+        sortColumn = "created_at"
+    }
+    direction, comparison := "DESC", "<"
+    if query.OrderBy == enum.OrderByAscending {
+        direction, comparison = "ASC", ">"
+    }
+
+    condition, args := subtreeCondition("category", query.Category)
+    statement := `SELECT ` + listingColumns + ` FROM listings WHERE ` + condition + ` AND status = ?`
+    args = append(args, enum.ListingStatusActive)
+    if query.MinPrice != nil {
+        statement += ` AND price >= ?`
+        args = append(args, *query.MinPrice)
+    }
+    if query.MaxPrice != nil {
+        statement += ` AND price <= ?`
+        args = append(args, *query.MaxPrice)
+    }
+    createdFrom, createdTo := query.CreatedAtRange()
+    if createdFrom != nil {
+        statement += ` AND created_at >= ?`
+        args = append(args, *createdFrom)
+    }
+    if createdTo != nil {
+        statement += ` AND created_at <= ?`
+        args = append(args, *createdTo)
+    }
+    if query.TitleContains != "" {
+        // instr matches case-sensitively, like contains in DynamoDB
+        statement += ` AND instr(title, ?) > 0`
+        args = append(args, query.TitleContains)
+    }
+    if cursor != nil {
+        statement += ` AND (` + sortColumn + `, listing_id) ` + comparison + ` (?, ?)`
+        args = append(args, cursor.SortValue, cursor.ListingId)
+    }
+    statement += ` ORDER BY ` + sortColumn + ` ` + direction + `, listing_id ` + direction
+    if query.Limit > 0 {
+        // one extra row tells whether there is a next page
+        statement += ` LIMIT ?`
+        args = append(args, query.Limit+1)
+    }
+
+    rows, err := s.db.QueryContext(ctx, statement, args...)
+    if err != nil {
+        s.log.Errorf("failed to query category %s: %v", query.Category, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    page := &model.ListingPage{}
+    for rows.Next() {
+        listing, err := scanListing(rows)
+        if err != nil {
+            s.log.Errorf("failed to scan listing: %v", err)
+            return nil, err
+        }
+        page.Listings = append(page.Listings, listing)
+    }
+    if err = rows.Err(); err != nil {
+        return nil, err
+    }
+
+    if query.Limit > 0 && len(page.Listings) > query.Limit {
+        page.Listings = page.Listings[:query.Limit]
+        page.Cursor = data.EncodeKeysetCursor(data.NewKeysetCursor(query.Category, page.Listings[query.Limit-1], query.SortBy))
+    }
+
+    return page, nil
+}
+
+// GetTopCategory retrieves the subcategory of parent, or the top-level category if parent is empty, with the highest
+// total number of listings, the first of its ranking by GetTopCategories. Returns nil if no such category has ever
+// been used
+func (s *SqliteDataAccess) GetTopCategory(ctx context.Context, parent string) (*model.CategoryMetric, error) {
+    metrics, err := s.GetTopCategories(ctx, model.TopCategoriesQuery{Parent: parent, Limit: 1})
+    if err != nil || len(metrics) == 0 {
+        return nil, err
+    }
+    return &metrics[0], nil
+}
+
+// GetTopCategories ranks the categories selected by query by descending total number of listings, then by descending name
+func (s *SqliteDataAccess) GetTopCategories(ctx context.Context, query model.TopCategoriesQuery) ([]model.CategoryMetric, error) {
+    statement := `SELECT category, category_count FROM category_metrics WHERE instr(category, ?) = 0`
+    args := []any{model.CategorySeparator}
+    if query.Parent != "" {
+        // a direct subcategory has no separator after the one following its parent
+        statement = `SELECT category, category_count FROM category_metrics
+            WHERE category > ? AND category < ? AND instr(substr(category, length(?) + 2), ?) = 0`
+        args = []any{query.Parent + model.CategorySeparator, query.Parent + "0", query.Parent, model.CategorySeparator}
+    }
+    if query.ExcludeEmpty {
+        statement += ` AND category_count > 0`
+    }
+    // names compare in byte order, as in model.CategoryMetric.RanksBefore
+    statement += ` ORDER BY category_count DESC, category DESC`
+    if query.Limit > 0 {
+        statement += ` LIMIT ?`
+        args = append(args, query.Limit)
+    }
+
+    rows, err := s.db.QueryContext(ctx, statement, args...)
+    if err != nil {
+        s.log.Errorf("failed to query top categories: %v", err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    var metrics []model.CategoryMetric
+    for rows.Next() {
+        var metric model.CategoryMetric
+        err = rows.Scan(&metric.Category, &metric.CategoryCount)
+        if err != nil {
+            return nil, err
+        }
+        metrics = append(metrics, metric)
+    }
+
+    return metrics, rows.Err()
+}
+
+// UpdateListing applies update to a listing, conditional on the version that was read, and moves the category count
+// in the same transaction if an active listing changes category
+func (s *SqliteDataAccess) UpdateListing(ctx context.Context, username string, listingId int, update model.ListingUpdate) (*model.Listing, error) {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    listing, err := scanListing(tx.QueryRowContext(ctx, `SELECT `+listingColumns+` FROM listings WHERE listing_id = ?`, listingId))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+    if err != nil {
+        s.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    if listing.Username != username {
+        return nil, exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", listingId, username), nil)
+    }
+
+    updated, err := listing.Apply(update)
+    if err != nil {
+        return nil, err
+    }
+    if updated.Category != listing.Category {
+        entry, err := model.CheckCategoryOpen(updated.Category, lookupCategory(ctx, tx))
+        if err != nil {
+            return nil, err
+        }
+        updated.Category = entry.Name
+    }
+
+    result, err := tx.ExecContext(ctx, `UPDATE listings SET title = ?, description = ?, price = ?, category = ?, version = ?
+        WHERE listing_id = ? AND username = ? AND version = ?`,
+        updated.Title, updated.Description, updated.Price, updated.Category, updated.Version,
+        listingId, username, listing.Version)
+    if err != nil {
+        return nil, err
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return nil, err
+    }
+    if rowsAffected == 0 {
+        return nil, exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listingId), nil)
+    }
+
+    if updated.Category != listing.Category && listing.IsActive() {
+        err = addCategoryCount(ctx, tx, listing.Category, -1)
+        if err != nil {
+            return nil, err
+        }
+        err = addCategoryCount(ctx, tx, updated.Category, 1)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    err = unindexListing(ctx, tx, listingId)
+    if err != nil {
+        return nil, err
+    }
+    err = indexListing(ctx, tx, updated)
+    if err != nil {
+        s.log.Errorf("failed to index listing %d: %v", listingId, err)
+        return nil, err
+    }
+
+    err = tx.Commit()
+    if err != nil {
+        return nil, err
+    }
+
+    return &updated, nil
+}
+
+// DeleteListing deletes a listing and, if it is active, decrements the category count in the same transaction
+func (s *SqliteDataAccess) DeleteListing(ctx context.Context, username string, listingId int) error {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer rollback(tx)
+
+    listing, err := scanListing(tx.QueryRowContext(ctx, `SELECT `+listingColumns+` FROM listings WHERE listing_id = ?`, listingId))
+    if errors.Is(err, sql.ErrNoRows) {
+        return exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+    if err != nil {
+        s.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return err
+    }
+
+    if listing.Username != username {
+        return exception.NewOwnershipMismatchException(fmt.Sprintf("listing with listingId %d is not owned by %s", listingId, username), nil)
+    }
+
+    if listing.IsSold() {
+        return exception.NewListingSoldException(fmt.Sprintf("listing with listingId %d is sold", listingId), nil)
+    }
+
+    err = unindexListing(ctx, tx, listingId)
+    if err != nil {
+        return err
+    }
+    _, err = tx.ExecContext(ctx, `DELETE FROM listings WHERE listing_id = ? AND username = ?`, listingId, username)
+    if err != nil {
+        return err
+    }
+
+    if listing.IsActive() {
+        err = addCategoryCount(ctx, tx, listing.Category, -1)
+        if err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// TransitionListing moves a listing to the next status of transition, conditional on the version that was read, and
+// adjusts the category count in the same transaction
+func (s *SqliteDataAccess) TransitionListing(ctx context.Context, username string, listingId int, transition enum.ListingTransition) (*model.Listing, error) {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    listing, err := scanListing(tx.QueryRowContext(ctx, `SELECT `+listingColumns+` FROM listings WHERE listing_id = ?`, listingId))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+    if err != nil {
+        s.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    updated, err := listing.Transition(username, transition)
+    if err != nil {
+        return nil, err
+    }
+
+    err = updateStatus(ctx, tx, listing, updated)
+    if err != nil {
+        return nil, err
+    }
+
+    err = addCategoryCount(ctx, tx, listing.Category, model.CategoryCountDelta(listing, updated))
+    if err != nil {
+        return nil, err
+    }
+
+    err = tx.Commit()
+    if err != nil {
+        return nil, err
+    }
+
+    return &updated, nil
+}
+
+// BuyListing marks a listing as sold to buyer, records the order and adjusts the category count in the same
+// transaction
+func (s *SqliteDataAccess) BuyListing(ctx context.Context, buyer string, listingId int) (*model.Order, error) {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer rollback(tx)
+
+    listing, err := scanListing(tx.QueryRowContext(ctx, `SELECT `+listingColumns+` FROM listings WHERE listing_id = ?`, listingId))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+    if err != nil {
+        s.log.Errorf("failed to get listing with listingId %d: %v", listingId, err)
+        return nil, err
+    }
+
+    sold, err := listing.Transition(buyer, enum.ListingTransitionBuy)
+    if err != nil {
+        return nil, err
+    }
+
+    order, err := model.NewOrder(listing, buyer)
+    if err != nil {
+        return nil, err
+    }
+
+    err = updateStatus(ctx, tx, listing, sold)
+    if err != nil {
+        return nil, err
+    }
+
+    _, err = tx.ExecContext(ctx, `INSERT INTO orders (listing_id, title, price, seller, buyer, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+        order.ListingId, order.Title, order.Price, order.Seller, order.Buyer, order.CreatedAt.Unix())
+    if err != nil {
+        s.log.Errorf("failed to insert order for listing %d: %v", listingId, err)
+        return nil, err
+    }
+
+    err = addCategoryCount(ctx, tx, listing.Category, model.CategoryCountDelta(listing, sold))
+    if err != nil {
+        return nil, err
+    }
+
+    err = tx.Commit()
+    if err != nil {
+        return nil, err
+    }
+
+    return &order, nil
+}
+
+// GetOrders retrieves the orders in which username is the buyer or the seller, newest first
+func (s *SqliteDataAccess) GetOrders(ctx context.Context, username string) ([]model.Order, error) {
+    rows, err := s.db.QueryContext(ctx, `SELECT listing_id, title, price, seller, buyer, created_at FROM orders
+        WHERE buyer = ? OR seller = ? ORDER BY created_at DESC, listing_id DESC`, username, username)
+    if err != nil {
+        s.log.Errorf("failed to query orders of %s: %v", username, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    var orders []model.Order
+    for rows.Next() {
+        var order model.Order
+        var createdAt int64
+        err = rows.Scan(&order.ListingId, &order.Title, &order.Price, &order.Seller, &order.Buyer, &createdAt)
+        if err != nil {
+            s.log.Errorf("failed to scan order: %v", err)
+            return nil, err
+        }
+        order.CreatedAt = time.Unix(createdAt, 0)
+        orders = append(orders, order)
+    }
+
+    return orders, rows.Err()
+}
+
+// Close releases the underlying database handle
+func (s *SqliteDataAccess) Close() error {
+    return s.db.Close()
+}
+
+// nextListingId increments the listing ID counter within the transaction and returns the new value
+func nextListingId(ctx context.Context, tx *sql.Tx) (int, error) {
+    var listingId int
+    err := tx.QueryRowContext(ctx, `INSERT INTO sequences (name, last_value) VALUES ('listing_id', ?)
+        ON CONFLICT (name) DO UPDATE SET last_value = last_value + 1 RETURNING last_value`, constant.FirstListingId).Scan(&listingId)
+    return listingId, err
+}
+
+// updateStatus writes the status of updated, conditional on the version of listing
+func updateStatus(ctx context.Context, tx *sql.Tx, listing model.Listing, updated model.Listing) error {
+    result, err := tx.ExecContext(ctx, `UPDATE listings SET status = ?, reserved_by = ?, version = ? WHERE listing_id = ? AND version = ?`,
+        updated.Status, updated.ReservedBy, updated.Version, listing.ListingId, listing.Version)
+    if err != nil {
+        return err
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if rowsAffected == 0 {
+        return exception.NewStaleListingException(fmt.Sprintf("listing with listingId %d was modified concurrently", listing.ListingId), nil)
+    }
+    return nil
+}
+
+// addCategoryCount adds delta to the count of a category and its ancestors
+func addCategoryCount(ctx context.Context, tx *sql.Tx, category string, delta int) error {
+    if delta == 0 {
+        return nil
+    }
+    for _, ancestor := range model.CategoryAncestors(category) {
+        _, err := tx.ExecContext(ctx, `INSERT INTO category_metrics (category, category_count) VALUES (?, ?)
+            ON CONFLICT (category) DO UPDATE SET category_count = category_count + excluded.category_count`, ancestor, delta)
+        if err != nil {
+            return fmt.Errorf("failed to update count of category %s: %w", ancestor, err)
+        }
+    }
+    return nil
+}
+
+// rollUpCategoryCounts recounts the active listings of every category and its subcategories
+func rollUpCategoryCounts(ctx context.Context, tx *sql.Tx) error {
+    rows, err := tx.QueryContext(ctx, `SELECT category, COUNT(*) FROM listings WHERE status = ? GROUP BY category`, enum.ListingStatusActive)
+    if err != nil {
+        return err
+    }
+    counts := make(map[string]int)
+    for rows.Next() {
+        var category string
+        var count int
+        err = rows.Scan(&category, &count)
+        if err != nil {
+            _ = rows.Close()
+            return err
+        }
+        counts[category] = count
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return err
+    }
+
+    _, err = tx.ExecContext(ctx, `UPDATE category_metrics SET category_count = 0`)
+    if err != nil {
+        return err
+    }
+    for category, count := range counts {
+        err = addCategoryCount(ctx, tx, category, count)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// addUsernameKeys keys the existing users by their canonical username. Usernames that differ only by case belong to
+// distinct accounts, so they are reported rather than merged.
+func addUsernameKeys(ctx context.Context, tx *sql.Tx) error {
+    rows, err := tx.QueryContext(ctx, `SELECT username FROM users ORDER BY username`)
+    if err != nil {
+        return err
+    }
+    usernames := make(map[string]string)
+    var users []model.User
+    for rows.Next() {
+        var user model.User
+        err = rows.Scan(&user.Username)
+        if err != nil {
+            _ = rows.Close()
+            return err
+        }
+        if other, exists := usernames[user.Key()]; exists {
+            _ = rows.Close()
+            return fmt.Errorf("usernames '%s' and '%s' differ only by case, rename one of them before upgrading", other, user.Username)
+        }
+        usernames[user.Key()] = user.Username
+        users = append(users, user)
+    }
+    _ = rows.Close()
+    if err = rows.Err(); err != nil {
+        return err
+    }
+
+    for _, user := range users {
+        _, err = tx.ExecContext(ctx, `UPDATE users SET username_key = ? WHERE username = ?`, user.Key(), user.Username)
+        if err != nil {
+            return err
+        }
+    }
+    _, err = tx.ExecContext(ctx, `CREATE UNIQUE INDEX users_username_key ON users (username_key)`)
+    return err
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+    Scan(dest ...any) error
+}
+
+func scanListing(row rowScanner) (model.Listing, error) {
+    var listing model.Listing
+    var createdAt int64
+    err := row.Scan(&listing.ListingId, &listing.Username, &listing.Title, &listing.Description, &listing.Price,
+        &listing.Category, &createdAt, &listing.Version, &listing.Status, &listing.ReservedBy)
+    if err != nil {
+        return model.Listing{}, err
+    }
+    listing.CreatedAt = time.Unix(createdAt, 0)
+    return listing, nil
+}
@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+    "context"
+    "fmt"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/storetest"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func newTestStore(t *testing.T) *SqliteDataAccess {
+    ctx := context.Background()
+    t.Setenv(constant.SqlitePathEnvKey, filepath.Join(t.TempDir(), "marketplace.db"))
+    store := NewSqliteDataAccess(zap.NewNop().Sugar())
+    t.Cleanup(func() {
+        _ = store.Close()
+    })
+
+    err := store.Migrate(ctx)
+    if err != nil {
+        t.Fatalf("could not migrate schema: %v", err)
+    }
+    return store
+}
+
+func TestConcurrentPutListing(t *testing.T) {
+    storetest.ConcurrentPutListing(t, newTestStore(t), 200)
+}
+
+func TestConcurrentUpdateListing(t *testing.T) {
+    storetest.ConcurrentUpdateListing(t, newTestStore(t), 20)
+}
+
+func TestConcurrentBuyListing(t *testing.T) {
+    storetest.ConcurrentBuyListing(t, newTestStore(t), 20)
+}
+
+func TestListingLifecycle(t *testing.T) {
+    storetest.ListingLifecycle(t, newTestStore(t))
+}
+
+func TestCategoryPagination(t *testing.T) {
+    storetest.CategoryPagination(t, newTestStore(t))
+}
+
+func TestCategoryFilters(t *testing.T) {
+    storetest.CategoryFilters(t, newTestStore(t))
+}
+
+func TestCategoryHierarchy(t *testing.T) {
+    storetest.CategoryHierarchy(t, newTestStore(t))
+}
+
+func TestSearchListings(t *testing.T) {
+    storetest.SearchListings(t, newTestStore(t))
+}
+
+func TestCategoryCatalog(t *testing.T) {
+    storetest.CategoryCatalog(t, newTestStore(t))
+}
+
+func TestCanonicalNames(t *testing.T) {
+    storetest.CanonicalNames(t, newTestStore(t))
+}
+
+func TestTopCategories(t *testing.T) {
+    storetest.TopCategories(t, newTestStore(t))
+}
+
+func TestEmptyMarketplace(t *testing.T) {
+    storetest.EmptyMarketplace(t, newTestStore(t))
+}
+
+func TestSessions(t *testing.T) {
+    storetest.Sessions(t, newTestStore(t))
+}
+
+func TestRoles(t *testing.T) {
+    storetest.Roles(t, newTestStore(t))
+}
+
+func TestApiKeys(t *testing.T) {
+    storetest.ApiKeys(t, newTestStore(t))
+}
+
+func TestRateLimits(t *testing.T) {
+    storetest.RateLimits(t, newTestStore(t))
+}
+
+func TestConcurrentRateLimit(t *testing.T) {
+    storetest.ConcurrentRateLimit(t, newTestStore(t), 20)
+}
+
+func TestIdempotencyRecords(t *testing.T) {
+    storetest.IdempotencyRecords(t, newTestStore(t))
+}
+
+func TestConcurrentIdempotencyRecord(t *testing.T) {
+    storetest.ConcurrentIdempotencyRecord(t, newTestStore(t), 20)
+}
+
+func TestCancelledContext(t *testing.T) {
+    storetest.CancelledContext(t, newTestStore(t))
+}
+
+// newLegacyStore creates a store with the schema of version 8, before names were unique by their canonical key
+func newLegacyStore(t *testing.T, statements ...string) *SqliteDataAccess {
+    ctx := context.Background()
+    t.Setenv(constant.SqlitePathEnvKey, filepath.Join(t.TempDir(), "marketplace.db"))
+    store := NewSqliteDataAccess(zap.NewNop().Sugar())
+    t.Cleanup(func() {
+        _ = store.Close()
+    })
+
+    err := store.migrateTo(ctx, 8)
+    if err != nil {
+        t.Fatalf("could not migrate schema: %v", err)
+    }
+    for _, statement := range statements {
+        _, err = store.db.Exec(statement)
+        if err != nil {
+            t.Fatalf("could not run %q: %v", statement, err)
+        }
+    }
+    return store
+}
+
+func TestMigrateCanonicalNames(t *testing.T) {
+    ctx := context.Background()
+    store := newLegacyStore(t,
+        `INSERT INTO users (username) VALUES ('Alice'), ('bob')`,
+        `INSERT INTO categories (name, status) VALUES ('Electronics', 'ACTIVE'), ('electronics', 'RETIRED'), ('electronics/phones', 'ACTIVE')`,
+        `INSERT INTO listings (listing_id, username, title, description, price, category, created_at)
+            VALUES (100001, 'Alice', 'Phone', 'a phone', 100, 'electronics/phones', 1), (100002, 'bob', 'Radio', 'a radio', 50, 'electronics', 2)`,
+    )
+    err := store.Migrate(ctx)
+    if err != nil {
+        t.Fatalf("could not migrate schema: %v", err)
+    }
+
+    user, err := store.GetUser(ctx, "ALICE")
+    if err != nil || user == nil || user.Username != "Alice" || user.HasPassword() {
+        t.Fatalf("expected user Alice without a password, got %v, %v", user, err)
+    }
+    categories, err := store.GetCategories(ctx)
+    if err != nil {
+        t.Fatalf("could not get categories: %v", err)
+    }
+    if fmt.Sprint(categories) != "[Electronics|ACTIVE Electronics/phones|ACTIVE]" {
+        t.Fatalf("expected merged categories, got %v", categories)
+    }
+    for listingId, expected := range map[int]string{100001: "Electronics/phones", 100002: "Electronics"} {
+        listing, err := store.GetListing(ctx, listingId)
+        if err != nil || listing == nil || listing.Category != expected {
+            t.Fatalf("expected listing %d in %s, got %v, %v", listingId, expected, listing, err)
+        }
+    }
+    top, err := store.GetTopCategory(ctx, "")
+    if err != nil || top == nil || top.Category != "Electronics" || top.CategoryCount != 2 {
+        t.Fatalf("expected Electronics with 2 listings, got %v, %v", top, err)
+    }
+}
+
+func TestMigrateCanonicalNamesRejectsCollidingUsernames(t *testing.T) {
+    ctx := context.Background()
+    store := newLegacyStore(t, `INSERT INTO users (username) VALUES ('Alice'), ('alice')`)
+    err := store.Migrate(ctx)
+    if err == nil || !strings.Contains(err.Error(), "differ only by case") {
+        t.Fatalf("expected colliding usernames to be rejected, got %v", err)
+    }
+}
@@ -0,0 +1,1525 @@
+// Package storetest holds checks shared by the tests of every data.MarketplaceStore implementation
+package storetest
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "sort"
+    "sync"
+    "testing"
+    "time"
+)
+
+// passwordHash stands for the password hash of the users registered by the tests. Stores keep hashes as given.
+const passwordHash = "$2a$10$storetest"
+
+// ConcurrentPutListing creates listings from many goroutines at once and asserts that every call succeeds with a
+// distinct listing ID, that the IDs form a gap-free sequence, and that the category count matches
+func ConcurrentPutListing(t *testing.T, store data.MarketplaceStore, concurrency int) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "stress-user"
+    category := "stress-category"
+    putCategories(t, store, category)
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+
+    listingIds := make([]int, concurrency)
+    errs := make([]error, concurrency)
+
+    var start, done sync.WaitGroup
+    start.Add(1)
+    for i := 0; i < concurrency; i++ {
+        done.Add(1)
+        go func(i int) {
+            defer done.Done()
+            start.Wait()
+
+            listing, err := store.PutListing(ctx, username, fmt.Sprintf("Listing %d", i), "stress test", 100, category, enum.ListingStatusActive)
+            if err != nil {
+                errs[i] = err
+                return
+            }
+            if listing == nil {
+                errs[i] = fmt.Errorf("listing rejected as already existing")
+                return
+            }
+            listingIds[i] = listing.ListingId
+        }(i)
+    }
+    start.Done()
+    done.Wait()
+
+    for i, err := range errs {
+        if err != nil {
+            t.Fatalf("PutListing call %d failed: %v", i, err)
+        }
+    }
+
+    sort.Ints(listingIds)
+    for i := 1; i < len(listingIds); i++ {
+        if listingIds[i] != listingIds[i-1]+1 {
+            t.Fatalf("listing IDs are not unique and sequential: %v", listingIds)
+        }
+    }
+
+    listings := getCategory(t, store, category)
+    if len(listings) != concurrency {
+        t.Fatalf("expected %d listings in category, got %d", concurrency, len(listings))
+    }
+}
+
+// ConcurrentUpdateListing updates one listing from many goroutines at once and asserts that every call either succeeds
+// or is rejected as stale, that the version counts the successful updates, and that the category counts stay in step
+func ConcurrentUpdateListing(t *testing.T, store data.MarketplaceStore, concurrency int) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "update-user"
+    putCategories(t, store, "update-category-0", "update-category-1")
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    listing, err := store.PutListing(ctx, username, "Listing", "update test", 100, "update-category-0", enum.ListingStatusActive)
+    if err != nil {
+        t.Fatalf("could not create listing: %v", err)
+    }
+
+    successes := make([]bool, concurrency)
+    errs := make([]error, concurrency)
+
+    var start, done sync.WaitGroup
+    start.Add(1)
+    for i := 0; i < concurrency; i++ {
+        done.Add(1)
+        go func(i int) {
+            defer done.Done()
+            start.Wait()
+
+            price := i
+            category := fmt.Sprintf("update-category-%d", i%2)
+            _, err := store.UpdateListing(ctx, username, listing.ListingId, model.ListingUpdate{Price: &price, Category: &category})
+            var staleErr *exception.StaleListingException
+            if errors.As(err, &staleErr) {
+                return
+            }
+            errs[i] = err
+            successes[i] = err == nil
+        }(i)
+    }
+    start.Done()
+    done.Wait()
+
+    updates := 0
+    for i, err := range errs {
+        if err != nil {
+            t.Fatalf("UpdateListing call %d failed: %v", i, err)
+        }
+        if successes[i] {
+            updates++
+        }
+    }
+
+    updated, err := store.GetListing(ctx, listing.ListingId)
+    if err != nil {
+        t.Fatalf("could not get listing: %v", err)
+    }
+    if updated.Version != listing.Version+updates {
+        t.Fatalf("expected version %d after %d successful updates, got %d", listing.Version+updates, updates, updated.Version)
+    }
+
+    for i := 0; i < 2; i++ {
+        category := fmt.Sprintf("update-category-%d", i)
+        listings := getCategory(t, store, category)
+        expected := 0
+        if category == updated.Category {
+            expected = 1
+        }
+        if len(listings) != expected {
+            t.Fatalf("expected %d listings in %s, got %d", expected, category, len(listings))
+        }
+    }
+}
+
+// ConcurrentBuyListing buys one listing from many buyers at once and asserts that exactly one purchase succeeds, the
+// others are rejected as sold or stale, and that a single order is recorded and the listing leaves its category
+func ConcurrentBuyListing(t *testing.T, store data.MarketplaceStore, concurrency int) {
+    t.Helper()
+    ctx := context.Background()
+
+    seller := "seller"
+    category := "buy-category"
+    putCategories(t, store, category)
+    _, err := store.PutUser(ctx, seller, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    listing, err := store.PutListing(ctx, seller, "Listing", "buy test", 100, category, enum.ListingStatusActive)
+    if err != nil {
+        t.Fatalf("could not create listing: %v", err)
+    }
+    for i := 0; i < concurrency; i++ {
+        _, err = store.PutUser(ctx, fmt.Sprintf("buyer-%d", i), passwordHash)
+        if err != nil {
+            t.Fatalf("could not register user: %v", err)
+        }
+    }
+
+    successes := make([]bool, concurrency)
+    errs := make([]error, concurrency)
+
+    var start, done sync.WaitGroup
+    start.Add(1)
+    for i := 0; i < concurrency; i++ {
+        done.Add(1)
+        go func(i int) {
+            defer done.Done()
+            start.Wait()
+
+            _, err := store.BuyListing(ctx, fmt.Sprintf("buyer-%d", i), listing.ListingId)
+            var soldErr *exception.ListingSoldException
+            var staleErr *exception.StaleListingException
+            if errors.As(err, &soldErr) || errors.As(err, &staleErr) {
+                return
+            }
+            errs[i] = err
+            successes[i] = err == nil
+        }(i)
+    }
+    start.Done()
+    done.Wait()
+
+    purchases := 0
+    for i, err := range errs {
+        if err != nil {
+            t.Fatalf("BuyListing call %d failed: %v", i, err)
+        }
+        if successes[i] {
+            purchases++
+        }
+    }
+    if purchases != 1 {
+        t.Fatalf("expected exactly 1 successful purchase, got %d", purchases)
+    }
+
+    orders, err := store.GetOrders(ctx, seller)
+    if err != nil {
+        t.Fatalf("could not get orders: %v", err)
+    }
+    if len(orders) != 1 || orders[0].ListingId != listing.ListingId || orders[0].Price != listing.Price {
+        t.Fatalf("expected a single order of listing %d, got %v", listing.ListingId, orders)
+    }
+
+    listings := getCategory(t, store, category)
+    if len(listings) != 0 {
+        t.Fatalf("expected sold listing to leave %s, got %d listings", category, len(listings))
+    }
+}
+
+// ListingLifecycle walks listings through the status machine and asserts that invalid transitions are rejected, that
+// only active listings are returned by GetCategory, and that the category count follows the active listings
+func ListingLifecycle(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    seller, buyer, other := "lifecycle-seller", "lifecycle-buyer", "lifecycle-other"
+    category := "lifecycle-category"
+    putCategories(t, store, category)
+    for _, username := range []string{seller, buyer, other} {
+        _, err := store.PutUser(ctx, username, passwordHash)
+        if err != nil {
+            t.Fatalf("could not register user: %v", err)
+        }
+    }
+
+    var invalidTransitionErr *exception.InvalidStatusTransitionException
+    var ownershipErr *exception.OwnershipMismatchException
+    var selfPurchaseErr *exception.SelfPurchaseException
+
+    transition := func(username string, listingId int, transition enum.ListingTransition, target any) {
+        t.Helper()
+        _, err := store.TransitionListing(ctx, username, listingId, transition)
+        if target == nil && err != nil {
+            t.Fatalf("%s by %s failed: %v", transition, username, err)
+        }
+        if target != nil && !errors.As(err, target) {
+            t.Fatalf("expected %s by %s to fail with %T, got %v", transition, username, target, err)
+        }
+    }
+    assertActive := func(expected int) {
+        t.Helper()
+        listings := getCategory(t, store, category)
+        if len(listings) != expected {
+            t.Fatalf("expected %d active listings in %s, got %d", expected, category, len(listings))
+        }
+        top, err := store.GetTopCategory(ctx, "")
+        if err != nil {
+            t.Fatalf("could not get top category: %v", err)
+        }
+        if top == nil || top.Category != category || top.CategoryCount != expected {
+            t.Fatalf("expected %s to count %d listings, got %v", category, expected, top)
+        }
+    }
+
+    listing, err := store.PutListing(ctx, seller, "Listing", "lifecycle test", 100, category, enum.ListingStatusDraft)
+    if err != nil {
+        t.Fatalf("could not create listing: %v", err)
+    }
+    id := listing.ListingId
+    _, err = store.PutListing(ctx, seller, "Listing", "lifecycle test", 100, category, enum.ListingStatusSold)
+    if err == nil {
+        t.Fatalf("expected listing creation in status SOLD to fail")
+    }
+
+    // drafts are hidden until published by their owner
+    transition(buyer, id, enum.ListingTransitionReserve, &invalidTransitionErr)
+    transition(other, id, enum.ListingTransitionPublish, &ownershipErr)
+    transition(seller, id, enum.ListingTransitionPublish, nil)
+    assertActive(1)
+    transition(seller, id, enum.ListingTransitionPublish, &invalidTransitionErr)
+
+    // a reservation hides the listing and only the reserving user can buy it
+    transition(seller, id, enum.ListingTransitionReserve, &selfPurchaseErr)
+    transition(buyer, id, enum.ListingTransitionReserve, nil)
+    assertActive(0)
+    reserved, err := store.GetListing(ctx, id)
+    if err != nil || reserved.Status != enum.ListingStatusReserved || reserved.ReservedBy != buyer {
+        t.Fatalf("expected listing reserved by %s, got %v: %v", buyer, reserved, err)
+    }
+    _, err = store.BuyListing(ctx, other, id)
+    if !errors.As(err, &invalidTransitionErr) {
+        t.Fatalf("expected purchase of a listing reserved by another user to fail, got %v", err)
+    }
+    transition(other, id, enum.ListingTransitionUnreserve, &ownershipErr)
+    transition(seller, id, enum.ListingTransitionUnreserve, nil)
+    assertActive(1)
+
+    transition(buyer, id, enum.ListingTransitionReserve, nil)
+    _, err = store.BuyListing(ctx, buyer, id)
+    if err != nil {
+        t.Fatalf("could not buy reserved listing: %v", err)
+    }
+    assertActive(0)
+    transition(seller, id, enum.ListingTransitionWithdraw, &invalidTransitionErr)
+
+    // withdrawn listings are final
+    listing, err = store.PutListing(ctx, seller, "Listing", "lifecycle test", 100, category, enum.ListingStatusActive)
+    if err != nil {
+        t.Fatalf("could not create listing: %v", err)
+    }
+    assertActive(1)
+    transition(seller, listing.ListingId, enum.ListingTransitionWithdraw, nil)
+    assertActive(0)
+    transition(seller, listing.ListingId, enum.ListingTransitionPublish, &invalidTransitionErr)
+    price := 200
+    _, err = store.UpdateListing(ctx, seller, listing.ListingId, model.ListingUpdate{Price: &price})
+    if !errors.As(err, &invalidTransitionErr) {
+        t.Fatalf("expected update of a withdrawn listing to fail, got %v", err)
+    }
+    err = store.DeleteListing(ctx, seller, listing.ListingId)
+    if err != nil {
+        t.Fatalf("could not delete withdrawn listing: %v", err)
+    }
+    assertActive(0)
+
+    // hiding drops a reservation and keeps the listing out of its category until unhidden; roles are checked by callers
+    listing, err = store.PutListing(ctx, seller, "Listing", "lifecycle test", 100, category, enum.ListingStatusActive)
+    if err != nil {
+        t.Fatalf("could not create listing: %v", err)
+    }
+    id = listing.ListingId
+    transition(buyer, id, enum.ListingTransitionReserve, nil)
+    transition(other, id, enum.ListingTransitionHide, nil)
+    assertActive(0)
+    hidden, err := store.GetListing(ctx, id)
+    if err != nil || hidden.Status != enum.ListingStatusHidden || hidden.ReservedBy != "" {
+        t.Fatalf("expected hidden listing without reservation, got %v: %v", hidden, err)
+    }
+    transition(other, id, enum.ListingTransitionHide, &invalidTransitionErr)
+    transition(buyer, id, enum.ListingTransitionReserve, &invalidTransitionErr)
+    _, err = store.BuyListing(ctx, buyer, id)
+    if !errors.As(err, &invalidTransitionErr) {
+        t.Fatalf("expected purchase of a hidden listing to fail, got %v", err)
+    }
+    transition(other, id, enum.ListingTransitionUnhide, nil)
+    assertActive(1)
+    transition(other, id, enum.ListingTransitionUnhide, &invalidTransitionErr)
+    transition(other, id, enum.ListingTransitionHide, nil)
+    transition(seller, id, enum.ListingTransitionWithdraw, nil)
+    assertActive(0)
+}
+
+// CategoryPagination pages through a category in every sort order and asserts that following the cursors returns the
+// same listings in the same order as fetching the whole category, and that foreign or malformed cursors are rejected
+func CategoryPagination(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "pagination-user"
+    category := "pagination-category"
+    putCategories(t, store, category, "other-category")
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    for i := 0; i < 25; i++ {
+        // repeated prices exercise the tie-breaking, and drafts are filtered out after the store pages are read
+        status := enum.ListingStatusActive
+        if i%4 == 0 {
+            status = enum.ListingStatusDraft
+        }
+        _, err = store.PutListing(ctx, username, fmt.Sprintf("Listing %d", i), "pagination test", i%5*100, category, status)
+        if err != nil {
+            t.Fatalf("could not put listing: %v", err)
+        }
+    }
+
+    for _, sortBy := range []enum.SortBy{enum.SortByPrice, enum.SortByCreatedAt} {
+        for _, orderBy := range []enum.OrderBy{enum.OrderByAscending, enum.OrderByDescending} {
+            query := model.CategoryQuery{Category: category, SortBy: sortBy, OrderBy: orderBy}
+            all, err := store.GetCategory(ctx, query)
+            if err != nil {
+                t.Fatalf("could not get category: %v", err)
+            }
+            if len(all.Listings) != 18 || all.Cursor != "" {
+                t.Fatalf("expected all 18 active listings without a cursor, got %d and %q", len(all.Listings), all.Cursor)
+            }
+
+            var paged []model.Listing
+            query.Limit = 4
+            for pages := 0; ; pages++ {
+                if pages > len(all.Listings) {
+                    t.Fatalf("cursor of sort %d order %d does not terminate", sortBy, orderBy)
+                }
+                page, err := store.GetCategory(ctx, query)
+                if err != nil {
+                    t.Fatalf("could not get page %d: %v", pages, err)
+                }
+                if len(page.Listings) > query.Limit {
+                    t.Fatalf("expected at most %d listings per page, got %d", query.Limit, len(page.Listings))
+                }
+                paged = append(paged, page.Listings...)
+                if page.Cursor == "" {
+                    break
+                }
+                query.Cursor = page.Cursor
+            }
+
+            if len(paged) != len(all.Listings) {
+                t.Fatalf("expected %d paged listings of sort %d order %d, got %d", len(all.Listings), sortBy, orderBy, len(paged))
+            }
+            for i := range paged {
+                if paged[i].ListingId != all.Listings[i].ListingId {
+                    t.Fatalf("paged listing %d of sort %d order %d is %d, expected %d", i, sortBy, orderBy, paged[i].ListingId, all.Listings[i].ListingId)
+                }
+            }
+        }
+    }
+
+    var invalidInputErr *exception.InvalidInputException
+    page, err := store.GetCategory(ctx, model.CategoryQuery{Category: category, SortBy: enum.SortByPrice, OrderBy: enum.OrderByAscending, Limit: 4})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    _, err = store.GetCategory(ctx, model.CategoryQuery{Category: "other-category", SortBy: enum.SortByPrice, OrderBy: enum.OrderByAscending, Cursor: page.Cursor})
+    if !errors.As(err, &invalidInputErr) {
+        t.Fatalf("expected cursor of another category to be rejected, got %v", err)
+    }
+    _, err = store.GetCategory(ctx, model.CategoryQuery{Category: category, SortBy: enum.SortByPrice, OrderBy: enum.OrderByAscending, Cursor: "not a cursor"})
+    if !errors.As(err, &invalidInputErr) {
+        t.Fatalf("expected malformed cursor to be rejected, got %v", err)
+    }
+}
+
+// CategoryFilters queries a category with price, creation time and title filters under both sort keys, so the price
+// bounds are tested both as a key condition and as a filter, and asserts that paging through filtered results is complete
+func CategoryFilters(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "filter-user"
+    category := "filter-category"
+    putCategories(t, store, category)
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    for i := 0; i < 10; i++ {
+        title := fmt.Sprintf("Phone %d", i)
+        if i%2 == 1 {
+            title = fmt.Sprintf("Tablet %d", i)
+        }
+        _, err = store.PutListing(ctx, username, title, "filter test", i*1000, category, enum.ListingStatusActive)
+        if err != nil {
+            t.Fatalf("could not put listing: %v", err)
+        }
+    }
+
+    minPrice, maxPrice := 2000, 7000
+    hourAgo, inAnHour := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+    testCases := []struct {
+        name     string
+        query    model.CategoryQuery
+        expected []string
+    }{
+        {"price range", model.CategoryQuery{MinPrice: &minPrice, MaxPrice: &maxPrice},
+            []string{"Phone 2", "Tablet 3", "Phone 4", "Tablet 5", "Phone 6", "Tablet 7"}},
+        {"minimum price", model.CategoryQuery{MinPrice: &maxPrice}, []string{"Tablet 7", "Phone 8", "Tablet 9"}},
+        {"maximum price", model.CategoryQuery{MaxPrice: &minPrice}, []string{"Phone 0", "Tablet 1", "Phone 2"}},
+        {"title", model.CategoryQuery{TitleContains: "Tablet"}, []string{"Tablet 1", "Tablet 3", "Tablet 5", "Tablet 7", "Tablet 9"}},
+        {"title is case-sensitive", model.CategoryQuery{TitleContains: "tablet"}, nil},
+        {"price range and title", model.CategoryQuery{MinPrice: &minPrice, MaxPrice: &maxPrice, TitleContains: "Phone"},
+            []string{"Phone 2", "Phone 4", "Phone 6"}},
+        {"created within", model.CategoryQuery{CreatedAfter: &hourAgo, CreatedBefore: &inAnHour, MaxPrice: &minPrice},
+            []string{"Phone 0", "Tablet 1", "Phone 2"}},
+        {"created after", model.CategoryQuery{CreatedAfter: &inAnHour}, nil},
+        {"created before", model.CategoryQuery{CreatedBefore: &hourAgo}, nil},
+    }
+
+    for _, tc := range testCases {
+        for _, sortBy := range []enum.SortBy{enum.SortByPrice, enum.SortByCreatedAt} {
+            query := tc.query
+            query.Category = category
+            query.SortBy = sortBy
+            query.OrderBy = enum.OrderByAscending
+            query.Limit = 2
+
+            var titles []string
+            for pages := 0; ; pages++ {
+                if pages > 10 {
+                    t.Fatalf("%s: cursor of sort %d does not terminate", tc.name, sortBy)
+                }
+                page, err := store.GetCategory(ctx, query)
+                if err != nil {
+                    t.Fatalf("%s: could not get category: %v", tc.name, err)
+                }
+                for _, listing := range page.Listings {
+                    titles = append(titles, listing.Title)
+                }
+                if page.Cursor == "" {
+                    break
+                }
+                query.Cursor = page.Cursor
+            }
+
+            // listings created within the same second are ordered differently by the backends, so compare as sets
+            expected := append([]string(nil), tc.expected...)
+            sort.Strings(expected)
+            sort.Strings(titles)
+            if fmt.Sprint(titles) != fmt.Sprint(expected) {
+                t.Fatalf("%s: expected %v with sort %d, got %v", tc.name, tc.expected, sortBy, titles)
+            }
+        }
+    }
+}
+
+// CategoryHierarchy queries parent categories for the listings of their subcategories, pages through them under both
+// sort keys, and asserts that category counts roll up to the ancestors as listings are created, moved and withdrawn
+func CategoryHierarchy(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "hierarchy-user"
+    putCategories(t, store, "Electronics", "Electronics/Phones", "Electronics/Phones/Android", "Electronics/Tablets",
+        "Electronicsware", "Fashion", "Fashion/Shoes")
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    listingIds := make(map[int]int)
+    for price, category := range map[int]string{
+        100: "Electronics",
+        200: "Electronics/Phones",
+        300: "Electronics/Phones",
+        400: "Electronics/Phones/Android",
+        500: "Electronics/Tablets",
+        600: "Electronicsware",
+        700: "Fashion",
+    } {
+        listing, err := store.PutListing(ctx, username, "Listing", "hierarchy test", price, category, enum.ListingStatusActive)
+        if err != nil {
+            t.Fatalf("could not put listing in %s: %v", category, err)
+        }
+        listingIds[price] = listing.ListingId
+    }
+    for _, category := range []string{"Electronics//Phones", "/Electronics", "Electronics/", "A/B/C/D/E/F"} {
+        _, err = store.PutListing(ctx, username, "Listing", "hierarchy test", 100, category, enum.ListingStatusActive)
+        if err == nil {
+            t.Fatalf("expected invalid category %q to be rejected", category)
+        }
+    }
+
+    assertPrices := func(category string, expected ...int) {
+        t.Helper()
+        var prices []int
+        for _, listing := range getCategory(t, store, category) {
+            prices = append(prices, listing.Price)
+        }
+        if fmt.Sprint(prices) != fmt.Sprint(expected) {
+            t.Fatalf("expected prices %v in %s, got %v", expected, category, prices)
+        }
+    }
+    assertPrices("Electronics", 100, 200, 300, 400, 500)
+    assertPrices("Electronics/Phones", 200, 300, 400)
+    assertPrices("Electronics/Phones/Android", 400)
+
+    for _, sortBy := range []enum.SortBy{enum.SortByPrice, enum.SortByCreatedAt} {
+        query := model.CategoryQuery{Category: "Electronics", SortBy: sortBy, OrderBy: enum.OrderByDescending, Limit: 2}
+        var prices []int
+        for pages := 0; ; pages++ {
+            if pages > 5 {
+                t.Fatalf("cursor of sort %d does not terminate", sortBy)
+            }
+            page, err := store.GetCategory(ctx, query)
+            if err != nil {
+                t.Fatalf("could not get category: %v", err)
+            }
+            for _, listing := range page.Listings {
+                prices = append(prices, listing.Price)
+            }
+            if page.Cursor == "" {
+                break
+            }
+            query.Cursor = page.Cursor
+        }
+        // listings created within the same second are ordered differently by the backends, so compare as sets
+        sort.Ints(prices)
+        if fmt.Sprint(prices) != fmt.Sprint([]int{100, 200, 300, 400, 500}) {
+            t.Fatalf("expected every listing of the subcategories to be paged with sort %d, got %v", sortBy, prices)
+        }
+    }
+
+    assertTop := func(parent string, expectedCategory string, expectedCount int) {
+        t.Helper()
+        top, err := store.GetTopCategory(ctx, parent)
+        if err != nil {
+            t.Fatalf("could not get top category of %q: %v", parent, err)
+        }
+        if expectedCategory == "" {
+            if top != nil {
+                t.Fatalf("expected no subcategory of %q, got %v", parent, top)
+            }
+            return
+        }
+        if top == nil || top.Category != expectedCategory || top.CategoryCount != expectedCount {
+            t.Fatalf("expected top category of %q to be %s with %d listings, got %v", parent, expectedCategory, expectedCount, top)
+        }
+    }
+    assertTop("", "Electronics", 5)
+    assertTop("Electronics", "Electronics/Phones", 3)
+    assertTop("Electronics/Phones", "Electronics/Phones/Android", 1)
+    assertTop("Fashion", "", 0)
+
+    // moving within a parent leaves the parent count unchanged, and ties are broken by descending name
+    tablets := "Electronics/Tablets"
+    _, err = store.UpdateListing(ctx, username, listingIds[200], model.ListingUpdate{Category: &tablets})
+    if err != nil {
+        t.Fatalf("could not move listing: %v", err)
+    }
+    assertTop("", "Electronics", 5)
+    assertTop("Electronics", "Electronics/Tablets", 2)
+
+    fashion := "Fashion/Shoes"
+    _, err = store.UpdateListing(ctx, username, listingIds[300], model.ListingUpdate{Category: &fashion})
+    if err != nil {
+        t.Fatalf("could not move listing: %v", err)
+    }
+    assertTop("Electronics", "Electronics/Tablets", 2)
+    assertTop("Fashion", "Fashion/Shoes", 1)
+    assertPrices("Electronics/Phones", 400)
+    assertPrices("Fashion", 300, 700)
+
+    _, err = store.TransitionListing(ctx, username, listingIds[400], enum.ListingTransitionWithdraw)
+    if err != nil {
+        t.Fatalf("could not withdraw listing: %v", err)
+    }
+    assertTop("Electronics/Phones", "Electronics/Phones/Android", 0)
+    assertTop("Electronics", "Electronics/Tablets", 2)
+    assertPrices("Electronics", 100, 200, 500)
+
+    err = store.DeleteListing(ctx, username, listingIds[100])
+    if err != nil {
+        t.Fatalf("could not delete listing: %v", err)
+    }
+    // Electronics ties with Fashion at 2 listings
+    assertTop("", "Fashion", 2)
+}
+
+// putCategories adds categories to the catalog, parents first
+func putCategories(t *testing.T, store data.MarketplaceStore, names ...string) {
+    t.Helper()
+    ctx := context.Background()
+
+    for _, name := range names {
+        _, err := store.PutCategory(ctx, name)
+        if err != nil {
+            t.Fatalf("could not put category %s: %v", name, err)
+        }
+    }
+}
+
+// getCategory fetches every active listing of category
+func getCategory(t *testing.T, store data.MarketplaceStore, category string) []model.Listing {
+    t.Helper()
+    ctx := context.Background()
+
+    page, err := store.GetCategory(ctx, model.CategoryQuery{Category: category, SortBy: enum.SortByPrice, OrderBy: enum.OrderByAscending})
+    if err != nil {
+        t.Fatalf("could not get category: %v", err)
+    }
+    return page.Listings
+}
+
+// SearchListings searches listings by stemmed words of their title and description and asserts the BM25 ranking, the
+// filters, and that the index follows updates, deletes and a rebuild
+func SearchListings(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "search-user"
+    putCategories(t, store, "Sports", "Fashion", "Electronics")
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    putListing := func(title string, description string, price int, category string, status enum.ListingStatus) int {
+        listing, err := store.PutListing(ctx, username, title, description, price, category, status)
+        if err != nil {
+            t.Fatalf("could not put listing: %v", err)
+        }
+        return listing.ListingId
+    }
+    running := putListing("Running shoes", "Lightweight shoes for running races", 5000, "Sports", enum.ListingStatusActive)
+    leather := putListing("Leather shoes", "Formal shoes", 12000, "Fashion", enum.ListingStatusActive)
+    putListing("Football", "Ball for running drills", 2000, "Sports", enum.ListingStatusActive)
+    phone := putListing("Phone", "Black phone", 50000, "Electronics", enum.ListingStatusDraft)
+
+    assertSearch := func(name string, query model.SearchQuery, expected []string) {
+        t.Helper()
+        listings, err := store.SearchListings(ctx, query)
+        if err != nil {
+            t.Fatalf("%s: could not search: %v", name, err)
+        }
+        var titles []string
+        for _, listing := range listings {
+            titles = append(titles, listing.Title)
+        }
+        if fmt.Sprint(titles) != fmt.Sprint(expected) {
+            t.Fatalf("%s: expected %v, got %v", name, expected, titles)
+        }
+    }
+
+    maxPrice := 3000
+    // both listings mention shoes twice, so the shorter one ranks first
+    assertSearch("stemmed", model.SearchQuery{Text: "SHOE"}, []string{"Leather shoes", "Running shoes"})
+    assertSearch("term frequency", model.SearchQuery{Text: "runs"}, []string{"Running shoes", "Football"})
+    assertSearch("any term", model.SearchQuery{Text: "formal drill"}, []string{"Leather shoes", "Football"})
+    assertSearch("limit", model.SearchQuery{Text: "shoes", Limit: 1}, []string{"Leather shoes"})
+    assertSearch("category", model.SearchQuery{Text: "shoes", Category: "Sports"}, []string{"Running shoes"})
+    assertSearch("price", model.SearchQuery{Text: "running", MaxPrice: &maxPrice}, []string{"Football"})
+    assertSearch("stop words", model.SearchQuery{Text: "for the"}, nil)
+    assertSearch("inactive", model.SearchQuery{Text: "phone"}, nil)
+
+    _, err = store.TransitionListing(ctx, username, phone, enum.ListingTransitionPublish)
+    if err != nil {
+        t.Fatalf("could not publish listing: %v", err)
+    }
+    assertSearch("published", model.SearchQuery{Text: "phone"}, []string{"Phone"})
+
+    title, description := "Leather boots", "Formal boots"
+    _, err = store.UpdateListing(ctx, username, leather, model.ListingUpdate{Title: &title, Description: &description})
+    if err != nil {
+        t.Fatalf("could not update listing: %v", err)
+    }
+    assertSearch("updated", model.SearchQuery{Text: "shoes"}, []string{"Running shoes"})
+    assertSearch("updated terms", model.SearchQuery{Text: "boot"}, []string{"Leather boots"})
+
+    err = store.DeleteListing(ctx, username, running)
+    if err != nil {
+        t.Fatalf("could not delete listing: %v", err)
+    }
+    assertSearch("deleted", model.SearchQuery{Text: "shoes"}, nil)
+
+    count, err := store.RebuildSearchIndex(ctx)
+    if err != nil {
+        t.Fatalf("could not rebuild search index: %v", err)
+    }
+    if count != 3 {
+        t.Fatalf("expected 3 listings indexed, got %d", count)
+    }
+    assertSearch("rebuilt", model.SearchQuery{Text: "boots running phone"}, []string{"Phone", "Leather boots", "Football"})
+}
+
+// CategoryCatalog manages the category catalog and asserts that listings are only accepted in open categories, and that
+// renames and merges move the subtree with its listings, drafts included, and its counts
+func CategoryCatalog(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "catalog-user"
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    putCategories(t, store, "Electronics", "Electronics/Phones", "Fashion")
+
+    var doesNotExistErr *exception.CategoryDoesNotExistException
+    var alreadyExistErr *exception.CategoryAlreadyExistException
+    var retiredErr *exception.CategoryRetiredException
+    var invalidInputErr *exception.InvalidInputException
+
+    category, err := store.PutCategory(ctx, "Electronics")
+    if err != nil || category != nil {
+        t.Fatalf("expected existing category to be reported as nil, got %v, %v", category, err)
+    }
+    _, err = store.PutCategory(ctx, "Toys/Cars")
+    if !errors.As(err, &doesNotExistErr) {
+        t.Fatalf("expected category without parent to be rejected, got %v", err)
+    }
+    _, err = store.PutListing(ctx, username, "Listing", "catalog test", 100, "Toys", enum.ListingStatusActive)
+    if !errors.As(err, &doesNotExistErr) {
+        t.Fatalf("expected listing in unknown category to be rejected, got %v", err)
+    }
+
+    putListing := func(price int, category string, status enum.ListingStatus) int {
+        listing, err := store.PutListing(ctx, username, "Listing", "catalog test", price, category, status)
+        if err != nil {
+            t.Fatalf("could not put listing in %s: %v", category, err)
+        }
+        return listing.ListingId
+    }
+    phone := putListing(100, "Electronics/Phones", enum.ListingStatusActive)
+    draft := putListing(200, "Electronics/Phones", enum.ListingStatusDraft)
+    putListing(300, "Fashion", enum.ListingStatusActive)
+
+    err = store.RetireCategory(ctx, "Fashion")
+    if err != nil {
+        t.Fatalf("could not retire category: %v", err)
+    }
+    err = store.RetireCategory(ctx, "Toys")
+    if !errors.As(err, &doesNotExistErr) {
+        t.Fatalf("expected retiring an unknown category to fail, got %v", err)
+    }
+    _, err = store.PutListing(ctx, username, "Listing", "catalog test", 100, "Fashion", enum.ListingStatusActive)
+    if !errors.As(err, &retiredErr) {
+        t.Fatalf("expected listing in retired category to be rejected, got %v", err)
+    }
+    _, err = store.PutCategory(ctx, "Fashion/Shoes")
+    if !errors.As(err, &retiredErr) {
+        t.Fatalf("expected subcategory of retired category to be rejected, got %v", err)
+    }
+    fashion := "Fashion"
+    _, err = store.UpdateListing(ctx, username, phone, model.ListingUpdate{Category: &fashion})
+    if !errors.As(err, &retiredErr) {
+        t.Fatalf("expected move to retired category to be rejected, got %v", err)
+    }
+
+    assertCategories := func(expected ...string) {
+        t.Helper()
+        categories, err := store.GetCategories(ctx)
+        if err != nil {
+            t.Fatalf("could not get categories: %v", err)
+        }
+        var names []string
+        for _, category := range categories {
+            names = append(names, category.String())
+        }
+        if fmt.Sprint(names) != fmt.Sprint(expected) {
+            t.Fatalf("expected categories %v, got %v", expected, names)
+        }
+    }
+    assertTop := func(parent string, expectedCategory string, expectedCount int) {
+        t.Helper()
+        top, err := store.GetTopCategory(ctx, parent)
+        if err != nil {
+            t.Fatalf("could not get top category of %q: %v", parent, err)
+        }
+        if expectedCategory == "" {
+            if top != nil {
+                t.Fatalf("expected no subcategory of %q, got %v", parent, top)
+            }
+            return
+        }
+        if top == nil || top.Category != expectedCategory || top.CategoryCount != expectedCount {
+            t.Fatalf("expected top category of %q to be %s with %d listings, got %v", parent, expectedCategory, expectedCount, top)
+        }
+    }
+    assertListingCategory := func(listingId int, expected string) {
+        t.Helper()
+        listing, err := store.GetListing(ctx, listingId)
+        if err != nil || listing == nil {
+            t.Fatalf("could not get listing %d: %v", listingId, err)
+        }
+        if listing.Category != expected {
+            t.Fatalf("expected listing %d in %s, got %s", listingId, expected, listing.Category)
+        }
+    }
+
+    err = store.RenameCategory(ctx, "Electronics", "Electronics/Gadgets")
+    if !errors.As(err, &invalidInputErr) {
+        t.Fatalf("expected move into itself to be rejected, got %v", err)
+    }
+    err = store.RenameCategory(ctx, "Electronics", "Fashion")
+    if !errors.As(err, &alreadyExistErr) {
+        t.Fatalf("expected rename to existing category to be rejected, got %v", err)
+    }
+    err = store.MergeCategory(ctx, "Electronics", "Toys")
+    if !errors.As(err, &doesNotExistErr) {
+        t.Fatalf("expected merge into unknown category to be rejected, got %v", err)
+    }
+
+    err = store.RenameCategory(ctx, "Electronics", "Tech")
+    if err != nil {
+        t.Fatalf("could not rename category: %v", err)
+    }
+    assertCategories("Fashion|RETIRED", "Tech|ACTIVE", "Tech/Phones|ACTIVE")
+    assertListingCategory(phone, "Tech/Phones")
+    assertListingCategory(draft, "Tech/Phones")
+    assertTop("Tech", "Tech/Phones", 1)
+    assertTop("Electronics", "", 0)
+
+    // merged counts are gone from the source subtree and rolled up under the target
+    putCategories(t, store, "Gadgets")
+    err = store.MergeCategory(ctx, "Tech/Phones", "Gadgets")
+    if err != nil {
+        t.Fatalf("could not merge category: %v", err)
+    }
+    assertCategories("Fashion|RETIRED", "Gadgets|ACTIVE", "Tech|ACTIVE")
+    assertListingCategory(phone, "Gadgets")
+    assertListingCategory(draft, "Gadgets")
+    assertTop("Tech", "", 0)
+    assertTop("", "Gadgets", 1)
+
+    putCategories(t, store, "Tech/Old")
+    old := putListing(400, "Tech/Old", enum.ListingStatusActive)
+    err = store.MergeCategory(ctx, "Tech/Old", "Tech")
+    if err != nil {
+        t.Fatalf("could not merge category into its parent: %v", err)
+    }
+    assertCategories("Fashion|RETIRED", "Gadgets|ACTIVE", "Tech|ACTIVE")
+    assertListingCategory(old, "Tech")
+    assertTop("", "Tech", 1)
+    assertTop("Tech", "", 0)
+}
+
+// CanonicalNames asserts that usernames and category names are unique by their canonical key and keep the spelling
+// they were first given, whatever the case they are looked up with
+func CanonicalNames(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    user, err := store.PutUser(ctx, "Zoë", passwordHash)
+    if err != nil || user == nil {
+        t.Fatalf("could not register user: %v, %v", user, err)
+    }
+    for _, username := range []string{"zoë", "ZOË", "zoë"} {
+        user, err = store.PutUser(ctx, username, passwordHash)
+        if err != nil || user != nil {
+            t.Fatalf("expected %q to be reported as an existing user, got %v, %v", username, user, err)
+        }
+        user, err = store.GetUser(ctx, username)
+        if err != nil || user == nil || user.Username != "Zoë" {
+            t.Fatalf("expected %q to retrieve user Zoë, got %v, %v", username, user, err)
+        }
+    }
+
+    putCategories(t, store, "Electronics", "electronics/Phones")
+    category, err := store.PutCategory(ctx, "ELECTRONICS")
+    if err != nil || category != nil {
+        t.Fatalf("expected existing category to be reported as nil, got %v, %v", category, err)
+    }
+    category, err = store.LookupCategory(ctx, "electronics/phones")
+    if err != nil || category == nil || category.Name != "Electronics/Phones" {
+        t.Fatalf("expected category Electronics/Phones, got %v, %v", category, err)
+    }
+
+    listing, err := store.PutListing(ctx, "Zoë", "Phone", "canonical test", 100, "ELECTRONICS/PHONES", enum.ListingStatusActive)
+    if err != nil {
+        t.Fatalf("could not put listing: %v", err)
+    }
+    if listing.Category != "Electronics/Phones" {
+        t.Fatalf("expected listing in Electronics/Phones, got %s", listing.Category)
+    }
+    if listings := getCategory(t, store, "Electronics"); len(listings) != 1 {
+        t.Fatalf("expected 1 listing in Electronics, got %d", len(listings))
+    }
+
+    var invalidInputErr *exception.InvalidInputException
+    err = store.RenameCategory(ctx, "electronics/phones", "ELECTRONICS/PHONES")
+    if !errors.As(err, &invalidInputErr) {
+        t.Fatalf("expected rename to the same canonical name to be rejected, got %v", err)
+    }
+    err = store.RenameCategory(ctx, "electronics/phones", "electronics/mobiles")
+    if err != nil {
+        t.Fatalf("could not rename category: %v", err)
+    }
+    listing, err = store.GetListing(ctx, listing.ListingId)
+    if err != nil || listing == nil || listing.Category != "Electronics/mobiles" {
+        t.Fatalf("expected listing in Electronics/mobiles, got %v, %v", listing, err)
+    }
+
+    putCategories(t, store, "Gadgets")
+    err = store.MergeCategory(ctx, "ELECTRONICS/MOBILES", "gadgets")
+    if err != nil {
+        t.Fatalf("could not merge category: %v", err)
+    }
+    listing, err = store.GetListing(ctx, listing.ListingId)
+    if err != nil || listing == nil || listing.Category != "Gadgets" {
+        t.Fatalf("expected listing in Gadgets, got %v, %v", listing, err)
+    }
+    top, err := store.GetTopCategory(ctx, "")
+    if err != nil || top == nil || top.Category != "Gadgets" || top.CategoryCount != 1 {
+        t.Fatalf("expected Gadgets with 1 listing, got %v, %v", top, err)
+    }
+}
+
+// TopCategories asserts that categories are ranked by descending count with ties broken by descending name, that the
+// ranking stops at the limit, and that the categories left without listings can be left out
+func TopCategories(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "ranking-user"
+    _, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    putCategories(t, store, "Books", "Electronics", "Electronics/Phones", "Fashion", "Garden", "Toys")
+
+    putListings := func(category string, count int) []int {
+        var listingIds []int
+        for i := 0; i < count; i++ {
+            listing, err := store.PutListing(ctx, username, "Listing", "ranking test", 100, category, enum.ListingStatusActive)
+            if err != nil {
+                t.Fatalf("could not put listing in %s: %v", category, err)
+            }
+            listingIds = append(listingIds, listing.ListingId)
+        }
+        return listingIds
+    }
+    putListings("Books", 1)
+    putListings("Electronics", 1)
+    putListings("Electronics/Phones", 2)
+    putListings("Fashion", 3)
+    putListings("Garden", 1)
+    for _, listingId := range putListings("Toys", 1) {
+        err = store.DeleteListing(ctx, username, listingId)
+        if err != nil {
+            t.Fatalf("could not delete listing %d: %v", listingId, err)
+        }
+    }
+
+    assertRanking := func(query model.TopCategoriesQuery, expected ...string) {
+        t.Helper()
+        metrics, err := store.GetTopCategories(ctx, query)
+        if err != nil {
+            t.Fatalf("could not get top categories of %+v: %v", query, err)
+        }
+        var ranking []string
+        for _, metric := range metrics {
+            ranking = append(ranking, metric.String())
+        }
+        if fmt.Sprint(ranking) != fmt.Sprint(expected) {
+            t.Fatalf("expected ranking %v of %+v, got %v", expected, query, ranking)
+        }
+    }
+    // subcategory counts roll up, so Electronics ties with Fashion
+    assertRanking(model.TopCategoriesQuery{}, "Fashion|3", "Electronics|3", "Garden|1", "Books|1", "Toys|0")
+    assertRanking(model.TopCategoriesQuery{ExcludeEmpty: true}, "Fashion|3", "Electronics|3", "Garden|1", "Books|1")
+    assertRanking(model.TopCategoriesQuery{Limit: 3}, "Fashion|3", "Electronics|3", "Garden|1")
+    assertRanking(model.TopCategoriesQuery{Parent: "Electronics"}, "Electronics/Phones|2")
+    assertRanking(model.TopCategoriesQuery{Parent: "Books"})
+
+    top, err := store.GetTopCategory(ctx, "")
+    if err != nil || top == nil || top.Category != "Fashion" {
+        t.Fatalf("expected the top category to head the ranking, got %v, %v", top, err)
+    }
+}
+
+// EmptyMarketplace asserts that the top categories of a marketplace without listings are reported as missing, and that
+// the categories of a marketplace whose listings were all deleted are kept with a count of zero
+func EmptyMarketplace(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    for _, parent := range []string{"", "Electronics"} {
+        top, err := store.GetTopCategory(ctx, parent)
+        if err != nil || top != nil {
+            t.Fatalf("expected no top category of %q in an empty marketplace, got %v, %v", parent, top, err)
+        }
+    }
+    metrics, err := store.GetTopCategories(ctx, model.TopCategoriesQuery{})
+    if err != nil || len(metrics) != 0 {
+        t.Fatalf("expected no ranked category in an empty marketplace, got %v, %v", metrics, err)
+    }
+
+    username := "empty-user"
+    _, err = store.PutUser(ctx, username, passwordHash)
+    if err != nil {
+        t.Fatalf("could not register user: %v", err)
+    }
+    putCategories(t, store, "Electronics", "Electronics/Phones")
+    for _, category := range []string{"Electronics", "Electronics/Phones"} {
+        listing, err := store.PutListing(ctx, username, "Listing", "empty test", 100, category, enum.ListingStatusActive)
+        if err != nil {
+            t.Fatalf("could not put listing in %s: %v", category, err)
+        }
+        err = store.DeleteListing(ctx, username, listing.ListingId)
+        if err != nil {
+            t.Fatalf("could not delete listing %d: %v", listing.ListingId, err)
+        }
+    }
+
+    top, err := store.GetTopCategory(ctx, "")
+    if err != nil || top == nil || top.Category != "Electronics" || top.CategoryCount != 0 {
+        t.Fatalf("expected Electronics with 0 listings, got %v, %v", top, err)
+    }
+    metrics, err = store.GetTopCategories(ctx, model.TopCategoriesQuery{Parent: "Electronics"})
+    if err != nil || len(metrics) != 1 || metrics[0].String() != "Electronics/Phones|0" {
+        t.Fatalf("expected Electronics/Phones with 0 listings, got %v, %v", metrics, err)
+    }
+    metrics, err = store.GetTopCategories(ctx, model.TopCategoriesQuery{ExcludeEmpty: true})
+    if err != nil || len(metrics) != 0 {
+        t.Fatalf("expected no non-empty category, got %v, %v", metrics, err)
+    }
+    if listings := getCategory(t, store, "Electronics"); len(listings) != 0 {
+        t.Fatalf("expected no listing in Electronics, got %d", len(listings))
+    }
+}
+
+// Sessions asserts that sessions are stored by the hash of their token until revoked, that expired sessions are kept
+// for the caller to reject, and that users registered without a password can register one once
+func Sessions(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "session-user"
+    user, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil || user == nil || user.PasswordHash != passwordHash {
+        t.Fatalf("could not register user: %v, %v", user, err)
+    }
+    user, err = store.GetUser(ctx, username)
+    if err != nil || user == nil || !user.HasPassword() {
+        t.Fatalf("expected user with a password, got %v, %v", user, err)
+    }
+
+    session, token, err := model.NewSession(username, time.Hour)
+    if err != nil {
+        t.Fatalf("could not create session: %v", err)
+    }
+    err = store.PutSession(ctx, session)
+    if err != nil {
+        t.Fatalf("could not put session: %v", err)
+    }
+    stored, err := store.GetSession(ctx, model.HashSessionToken(token))
+    if err != nil || stored == nil || stored.Username != username || !stored.ExpiresAt.Equal(session.ExpiresAt) {
+        t.Fatalf("expected session of %s expiring at %v, got %v, %v", username, session.ExpiresAt, stored, err)
+    }
+    stored, err = store.GetSession(ctx, token)
+    if err != nil || stored != nil {
+        t.Fatalf("expected no session stored under the token itself, got %v, %v", stored, err)
+    }
+
+    expired, expiredToken, err := model.NewSession(username, -time.Second)
+    if err != nil {
+        t.Fatalf("could not create session: %v", err)
+    }
+    err = store.PutSession(ctx, expired)
+    if err != nil {
+        t.Fatalf("could not put session: %v", err)
+    }
+    stored, err = store.GetSession(ctx, model.HashSessionToken(expiredToken))
+    if err != nil || stored == nil || !stored.IsExpired(time.Now()) {
+        t.Fatalf("expected expired session, got %v, %v", stored, err)
+    }
+
+    for _, tokenHash := range []string{session.TokenHash, session.TokenHash, expired.TokenHash} {
+        err = store.DeleteSession(ctx, tokenHash)
+        if err != nil {
+            t.Fatalf("could not delete session: %v", err)
+        }
+    }
+    stored, err = store.GetSession(ctx, session.TokenHash)
+    if err != nil || stored != nil {
+        t.Fatalf("expected revoked session to be gone, got %v, %v", stored, err)
+    }
+
+    legacy := "Legacy-User"
+    user, err = store.PutUser(ctx, legacy, "")
+    if err != nil || user == nil || user.HasPassword() {
+        t.Fatalf("could not register user without a password: %v, %v", user, err)
+    }
+    user, err = store.PutUser(ctx, "legacy-user", passwordHash)
+    if err != nil || user == nil || user.Username != legacy || user.PasswordHash != passwordHash {
+        t.Fatalf("expected %s to get a password, got %v, %v", legacy, user, err)
+    }
+    user, err = store.PutUser(ctx, legacy, "$2a$10$other")
+    if err != nil || user != nil {
+        t.Fatalf("expected %s to be reported as an existing user, got %v, %v", legacy, user, err)
+    }
+    user, err = store.GetUser(ctx, legacy)
+    if err != nil || user == nil || user.PasswordHash != passwordHash {
+        t.Fatalf("expected the first password of %s to be kept, got %v, %v", legacy, user, err)
+    }
+}
+
+// Roles asserts that roles are set on existing users by the canonical key of their username, and that users are
+// registered without a role
+func Roles(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    username := "Role-User"
+    user, err := store.PutUser(ctx, username, passwordHash)
+    if err != nil || user == nil {
+        t.Fatalf("could not register user: %v, %v", user, err)
+    }
+    user, err = store.GetUser(ctx, username)
+    if err != nil || user == nil || user.CurrentRole() != enum.RoleUser {
+        t.Fatalf("expected a regular user, got %v, %v", user, err)
+    }
+
+    for _, role := range []enum.Role{enum.RoleModerator, enum.RoleAdmin, enum.RoleUser} {
+        user, err = store.SetUserRole(ctx, "role-user", role)
+        if err != nil || user == nil || user.Username != username || user.Role != role ||
+            user.PasswordHash != passwordHash {
+            t.Fatalf("expected %s to get role %s, got %v, %v", username, role, user, err)
+        }
+        user, err = store.GetUser(ctx, username)
+        if err != nil || user == nil || user.CurrentRole() != role || user.PasswordHash != passwordHash {
+            t.Fatalf("expected %s to have role %s, got %v, %v", username, role, user, err)
+        }
+    }
+
+    user, err = store.SetUserRole(ctx, "missing-role-user", enum.RoleAdmin)
+    if err != nil || user != nil {
+        t.Fatalf("expected no role set on a missing user, got %v, %v", user, err)
+    }
+    user, err = store.GetUser(ctx, "missing-role-user")
+    if err != nil || user != nil {
+        t.Fatalf("expected setting a role not to create a user, got %v, %v", user, err)
+    }
+}
+
+// ApiKeys asserts that API keys are stored by their ID until revoked, that expired keys are kept, and that the keys of
+// a user are listed oldest first by the canonical key of the username
+func ApiKeys(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    owner, other := "Api-Key-User", "api-key-other"
+    for _, username := range []string{owner, other} {
+        _, err := store.PutUser(ctx, username, passwordHash)
+        if err != nil {
+            t.Fatalf("could not register user: %v", err)
+        }
+    }
+
+    var keys []model.ApiKey
+    for i, scope := range []enum.ApiKeyScope{enum.ApiKeyScopeReadOnly, enum.ApiKeyScopeAdmin} {
+        apiKey, key, err := model.NewApiKey(owner, scope, time.Duration(i+1)*time.Hour)
+        if err != nil {
+            t.Fatalf("could not create api key: %v", err)
+        }
+        // keys created in the same second are listed by ID
+        apiKey.CreatedAt = apiKey.CreatedAt.Add(time.Duration(i) * time.Second)
+        err = store.PutApiKey(ctx, apiKey)
+        if err != nil {
+            t.Fatalf("could not put api key: %v", err)
+        }
+        keyId, secret, ok := model.ParseApiKey(key)
+        if !ok || keyId != apiKey.KeyId {
+            t.Fatalf("expected key %s to parse to ID %s", key, apiKey.KeyId)
+        }
+        stored, err := store.GetApiKey(ctx, keyId)
+        if err != nil || stored == nil || stored.Username != owner || stored.Scope != scope || !stored.Matches(secret) ||
+            !stored.ExpiresAt.Equal(apiKey.ExpiresAt) {
+            t.Fatalf("expected api key %s of %s with scope %s, got %v, %v", keyId, owner, scope, stored, err)
+        }
+        keys = append(keys, apiKey)
+    }
+    expired, _, err := model.NewApiKey(other, enum.ApiKeyScopeListingsWrite, -time.Second)
+    if err != nil {
+        t.Fatalf("could not create api key: %v", err)
+    }
+    err = store.PutApiKey(ctx, expired)
+    if err != nil {
+        t.Fatalf("could not put api key: %v", err)
+    }
+    stored, err := store.GetApiKey(ctx, expired.KeyId)
+    if err != nil || stored == nil || !stored.IsExpired(time.Now()) {
+        t.Fatalf("expected expired api key, got %v, %v", stored, err)
+    }
+
+    listed, err := store.GetApiKeys(ctx, "api-key-user")
+    if err != nil || len(listed) != 2 || listed[0].KeyId != keys[0].KeyId || listed[1].KeyId != keys[1].KeyId {
+        t.Fatalf("expected the api keys of %s oldest first, got %v, %v", owner, listed, err)
+    }
+
+    for _, keyId := range []string{keys[0].KeyId, keys[0].KeyId, expired.KeyId} {
+        err = store.DeleteApiKey(ctx, keyId)
+        if err != nil {
+            t.Fatalf("could not delete api key: %v", err)
+        }
+    }
+    stored, err = store.GetApiKey(ctx, keys[0].KeyId)
+    if err != nil || stored != nil {
+        t.Fatalf("expected revoked api key to be gone, got %v, %v", stored, err)
+    }
+    listed, err = store.GetApiKeys(ctx, owner)
+    if err != nil || len(listed) != 1 || listed[0].KeyId != keys[1].KeyId {
+        t.Fatalf("expected the remaining api key of %s, got %v, %v", owner, listed, err)
+    }
+    listed, err = store.GetApiKeys(ctx, other)
+    if err != nil || len(listed) != 0 {
+        t.Fatalf("expected no api keys of %s, got %v, %v", other, listed, err)
+    }
+}
+
+// RateLimits asserts that a bucket lets a burst of commands through, then one command per refill interval, and that
+// the buckets of different keys are independent
+func RateLimits(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    limit := model.RateLimit{Burst: 3, Period: 3 * time.Minute}
+    now := time.Now()
+    user := model.RateLimitKey(enum.CommandClassWrite, "Rate-User")
+    take := func(key string, at time.Time, expected bool) {
+        t.Helper()
+        allowed, err := store.TakeRateLimitToken(ctx, key, limit, at)
+        if err != nil {
+            t.Fatalf("could not take rate limit token: %v", err)
+        }
+        if allowed != expected {
+            t.Fatalf("expected take from %s at %v to be allowed %t", key, at.Sub(now), expected)
+        }
+    }
+
+    for i := 0; i < limit.Burst; i++ {
+        take(user, now, true)
+    }
+    take(user, now, false)
+    // keys are canonical and each class has its own bucket
+    take(model.RateLimitKey(enum.CommandClassWrite, "rate-user"), now, false)
+    take(model.RateLimitKey(enum.CommandClassRead, "rate-user"), now, true)
+    take(model.RateLimitKey(enum.CommandClassWrite, "rate-other"), now, true)
+
+    // a token is refilled every minute
+    take(user, now.Add(30*time.Second), false)
+    take(user, now.Add(time.Minute), true)
+    take(user, now.Add(time.Minute), false)
+    // the bucket holds at most a burst of tokens however long it is idle
+    later := now.Add(time.Hour)
+    for i := 0; i < limit.Burst; i++ {
+        take(user, later, true)
+    }
+    take(user, later, false)
+}
+
+// ConcurrentRateLimit takes tokens from one bucket from many callers at once and asserts that no more than a burst of
+// them is let through
+func ConcurrentRateLimit(t *testing.T, store data.MarketplaceStore, concurrency int) {
+    t.Helper()
+    ctx := context.Background()
+
+    limit := model.RateLimit{Burst: concurrency / 2, Period: time.Hour}
+    key := model.RateLimitKey(enum.CommandClassWrite, "concurrent-rate-user")
+    now := time.Now()
+
+    successes := make([]bool, concurrency)
+    errs := make([]error, concurrency)
+
+    var start, done sync.WaitGroup
+    start.Add(1)
+    for i := 0; i < concurrency; i++ {
+        done.Add(1)
+        go func(i int) {
+            defer done.Done()
+            start.Wait()
+
+            successes[i], errs[i] = store.TakeRateLimitToken(ctx, key, limit, now)
+        }(i)
+    }
+    start.Done()
+    done.Wait()
+
+    taken := 0
+    for i, err := range errs {
+        if err != nil {
+            t.Fatalf("TakeRateLimitToken call %d failed: %v", i, err)
+        }
+        if successes[i] {
+            taken++
+        }
+    }
+    if taken == 0 || taken > limit.Burst {
+        t.Fatalf("expected between 1 and %d tokens taken, got %d", limit.Burst, taken)
+    }
+}
+
+// IdempotencyRecords asserts that a record holds its key until it expires, that a completed record keeps its response,
+// that a record completed without a response keeps holding its key, and that releasing a key only deletes records
+// without a response
+func IdempotencyRecords(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx := context.Background()
+
+    now := time.Now().Truncate(time.Second)
+    key := model.IdempotencyRecordKey("Idempotent-User", "key-1")
+    put := func(requestHash string, at time.Time) *model.IdempotencyRecord {
+        t.Helper()
+        existing, err := store.PutIdempotencyRecord(ctx, model.IdempotencyRecord{
+            Key:         key,
+            RequestHash: requestHash,
+            CreatedAt:   at,
+            ExpiresAt:   at.Add(10 * time.Second),
+        })
+        if err != nil {
+            t.Fatalf("could not put idempotency record: %v", err)
+        }
+        return existing
+    }
+
+    if existing := put("hash-1", now); existing != nil {
+        t.Fatalf("expected the key to be free, got %+v", existing)
+    }
+    existing := put("hash-2", now.Add(time.Second))
+    if existing == nil || existing.RequestHash != "hash-1" || existing.IsCompleted() {
+        t.Fatalf("expected the key to be held by the running command, got %+v", existing)
+    }
+
+    // a released key is free again
+    err := store.DeleteIdempotencyRecord(ctx, key)
+    if err != nil {
+        t.Fatalf("could not delete idempotency record: %v", err)
+    }
+    if existing := put("hash-1", now); existing != nil {
+        t.Fatalf("expected the released key to be free, got %+v", existing)
+    }
+
+    // a record completed without a response keeps holding its key until its new expiry
+    err = store.CompleteIdempotencyRecord(ctx, model.IdempotencyRecord{
+        Key:         key,
+        RequestHash: "hash-1",
+        CreatedAt:   now,
+        ExpiresAt:   now.Add(30 * time.Second),
+    })
+    if err != nil {
+        t.Fatalf("could not complete idempotency record: %v", err)
+    }
+    existing = put("hash-2", now.Add(20*time.Second))
+    if existing == nil || existing.RequestHash != "hash-1" || existing.IsCompleted() {
+        t.Fatalf("expected the key to stay held without a response, got %+v", existing)
+    }
+
+    // a completed record is replayed until it expires, and is not released
+    err = store.CompleteIdempotencyRecord(ctx, model.IdempotencyRecord{
+        Key:         key,
+        RequestHash: "hash-1",
+        Response:    []byte(`{"listingId":100001}`),
+        CreatedAt:   now,
+        ExpiresAt:   now.Add(time.Hour),
+    })
+    if err != nil {
+        t.Fatalf("could not complete idempotency record: %v", err)
+    }
+    err = store.DeleteIdempotencyRecord(ctx, key)
+    if err != nil {
+        t.Fatalf("could not delete idempotency record: %v", err)
+    }
+    existing = put("hash-2", now.Add(time.Minute))
+    if existing == nil || existing.RequestHash != "hash-1" || string(existing.Response) != `{"listingId":100001}` ||
+        !existing.ExpiresAt.Equal(now.Add(time.Hour)) {
+        t.Fatalf("expected the completed record to be kept, got %+v", existing)
+    }
+
+    // keys are per user, and a key is free again once its record expired
+    other := model.IdempotencyRecordKey("other-user", "key-1")
+    _, err = store.PutIdempotencyRecord(ctx, model.IdempotencyRecord{Key: other, RequestHash: "hash-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+    if err != nil {
+        t.Fatalf("expected the key of another user to be free, got %v", err)
+    }
+    if existing := put("hash-2", now.Add(time.Hour)); existing != nil {
+        t.Fatalf("expected the expired key to be free, got %+v", existing)
+    }
+    err = store.DeleteIdempotencyRecord(ctx, "missing")
+    if err != nil {
+        t.Fatalf("expected deleting a missing record to succeed, got %v", err)
+    }
+}
+
+// ConcurrentIdempotencyRecord puts records under one key from many callers at once and asserts that exactly one of
+// them takes the key
+func ConcurrentIdempotencyRecord(t *testing.T, store data.MarketplaceStore, concurrency int) {
+    t.Helper()
+    ctx := context.Background()
+
+    now := time.Now()
+    record := model.IdempotencyRecord{
+        Key:         model.IdempotencyRecordKey("concurrent-idempotent-user", "key"),
+        RequestHash: "hash",
+        CreatedAt:   now,
+        ExpiresAt:   now.Add(time.Minute),
+    }
+
+    existing := make([]*model.IdempotencyRecord, concurrency)
+    errs := make([]error, concurrency)
+
+    var start, done sync.WaitGroup
+    start.Add(1)
+    for i := 0; i < concurrency; i++ {
+        done.Add(1)
+        go func(i int) {
+            defer done.Done()
+            start.Wait()
+
+            existing[i], errs[i] = store.PutIdempotencyRecord(ctx, record)
+        }(i)
+    }
+    start.Done()
+    done.Wait()
+
+    taken := 0
+    for i, err := range errs {
+        if err != nil {
+            t.Fatalf("PutIdempotencyRecord call %d failed: %v", i, err)
+        }
+        if existing[i] == nil {
+            taken++
+        }
+    }
+    if taken != 1 {
+        t.Fatalf("expected the key to be taken once, got %d", taken)
+    }
+}
+
+// CancelledContext asserts that a store waiting on a database gives up with the error of a cancelled context
+func CancelledContext(t *testing.T, store data.MarketplaceStore) {
+    t.Helper()
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    _, err := store.PutUser(ctx, "cancelled-user", passwordHash)
+    if !errors.Is(err, context.Canceled) {
+        t.Fatalf("expected PutUser to be cancelled, got %v", err)
+    }
+    _, err = store.GetListing(ctx, constant.FirstListingId)
+    if !errors.Is(err, context.Canceled) {
+        t.Fatalf("expected GetListing to be cancelled, got %v", err)
+    }
+    user, err := store.GetUser(context.Background(), "cancelled-user")
+    if err != nil || user != nil {
+        t.Fatalf("expected the cancelled user not to be registered, got %v, %v", user, err)
+    }
+}
@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type ApiKeyDoesNotExistException struct {
+    Context string
+    Err     error
+}
+
+func NewApiKeyDoesNotExistException(message string, err error) *ApiKeyDoesNotExistException {
+    return &ApiKeyDoesNotExistException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *ApiKeyDoesNotExistException) Error() string {
+    return fmt.Sprintf("ApiKeyDoesNotExistException: %s: %v", e.Context, e.Err)
+}
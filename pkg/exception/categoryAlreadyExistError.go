@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type CategoryAlreadyExistException struct {
+    Context string
+    Err     error
+}
+
+func NewCategoryAlreadyExistException(message string, err error) *CategoryAlreadyExistException {
+    return &CategoryAlreadyExistException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *CategoryAlreadyExistException) Error() string {
+    return fmt.Sprintf("CategoryAlreadyExistException: %s: %v", e.Context, e.Err)
+}
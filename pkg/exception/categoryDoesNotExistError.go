@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type CategoryDoesNotExistException struct {
+    Context string
+    Err     error
+}
+
+func NewCategoryDoesNotExistException(message string, err error) *CategoryDoesNotExistException {
+    return &CategoryDoesNotExistException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *CategoryDoesNotExistException) Error() string {
+    return fmt.Sprintf("CategoryDoesNotExistException: %s: %v", e.Context, e.Err)
+}
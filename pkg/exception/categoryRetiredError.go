@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type CategoryRetiredException struct {
+    Context string
+    Err     error
+}
+
+func NewCategoryRetiredException(message string, err error) *CategoryRetiredException {
+    return &CategoryRetiredException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *CategoryRetiredException) Error() string {
+    return fmt.Sprintf("CategoryRetiredException: %s: %v", e.Context, e.Err)
+}
@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type IdempotencyKeyReusedException struct {
+    Context string
+    Err     error
+}
+
+func NewIdempotencyKeyReusedException(message string, err error) *IdempotencyKeyReusedException {
+    return &IdempotencyKeyReusedException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *IdempotencyKeyReusedException) Error() string {
+    return fmt.Sprintf("IdempotencyKeyReusedException: %s: %v", e.Context, e.Err)
+}
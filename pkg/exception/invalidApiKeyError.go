@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type InvalidApiKeyException struct {
+    Context string
+    Err     error
+}
+
+func NewInvalidApiKeyException(message string, err error) *InvalidApiKeyException {
+    return &InvalidApiKeyException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *InvalidApiKeyException) Error() string {
+    return fmt.Sprintf("InvalidApiKeyException: %s: %v", e.Context, e.Err)
+}
@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type InvalidCredentialsException struct {
+    Context string
+    Err     error
+}
+
+func NewInvalidCredentialsException(message string, err error) *InvalidCredentialsException {
+    return &InvalidCredentialsException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *InvalidCredentialsException) Error() string {
+    return fmt.Sprintf("InvalidCredentialsException: %s: %v", e.Context, e.Err)
+}
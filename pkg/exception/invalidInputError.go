@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type InvalidInputException struct {
+    Context string
+    Err     error
+}
+
+func NewInvalidInputException(message string, err error) *InvalidInputException {
+    return &InvalidInputException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *InvalidInputException) Error() string {
+    return fmt.Sprintf("InvalidInputException: %s: %v", e.Context, e.Err)
+}
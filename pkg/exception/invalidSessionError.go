@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type InvalidSessionException struct {
+    Context string
+    Err     error
+}
+
+func NewInvalidSessionException(message string, err error) *InvalidSessionException {
+    return &InvalidSessionException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *InvalidSessionException) Error() string {
+    return fmt.Sprintf("InvalidSessionException: %s: %v", e.Context, e.Err)
+}
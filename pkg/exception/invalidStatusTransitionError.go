@@ -0,0 +1,21 @@
+package exception
+
+import "fmt"
+
+// InvalidStatusTransitionException is returned when a listing cannot be moved out of its current status.
+// Context is a message that can be shown to the user as is.
+type InvalidStatusTransitionException struct {
+    Context string
+    Err     error
+}
+
+func NewInvalidStatusTransitionException(message string, err error) *InvalidStatusTransitionException {
+    return &InvalidStatusTransitionException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *InvalidStatusTransitionException) Error() string {
+    return fmt.Sprintf("InvalidStatusTransitionException: %s: %v", e.Context, e.Err)
+}
@@ -0,0 +1,104 @@
+package exception
+
+import (
+    "context"
+    "errors"
+    "github.com/go-playground/validator/v10"
+)
+
+// Kind classifies the errors of the marketplace, so that every transport maps them to its own status
+type Kind int
+
+const (
+    KindInternal Kind = iota
+    KindTimeout
+    KindCancelled
+    KindUnknownUser
+    KindInvalidCredentials
+    KindInvalidSession
+    KindInvalidApiKey
+    KindApiKeyDoesNotExist
+    KindRateLimited
+    KindIdempotencyKeyReused
+    KindRequestInProgress
+    KindOwnershipMismatch
+    KindListingDoesNotExist
+    KindStaleListing
+    KindListingSold
+    KindSelfPurchase
+    KindInvalidStatusTransition
+    KindPermissionDenied
+    KindCategoryDoesNotExist
+    KindCategoryAlreadyExist
+    KindCategoryRetired
+    KindInvalidInput
+)
+
+// Classify finds the marketplace error in the chain of err, which the stores and the service may have wrapped
+// Returns the kind of the error and the message shown to users for it, or KindInternal if err is not a marketplace
+// error
+func Classify(err error) (Kind, string) {
+    // the stores return the error of the context, possibly wrapped
+    if errors.Is(err, context.DeadlineExceeded) {
+        return KindTimeout, "timeout"
+    }
+    if errors.Is(err, context.Canceled) {
+        return KindCancelled, "cancelled"
+    }
+    if transitionErr, ok := as[*InvalidStatusTransitionException](err); ok {
+        return KindInvalidStatusTransition, transitionErr.Context
+    }
+
+    switch {
+    case is[*UnknownUserException](err):
+        return KindUnknownUser, "unknown user"
+    case is[*InvalidCredentialsException](err):
+        return KindInvalidCredentials, "invalid credentials"
+    case is[*InvalidSessionException](err):
+        return KindInvalidSession, "invalid session"
+    case is[*InvalidApiKeyException](err):
+        return KindInvalidApiKey, "invalid api key"
+    case is[*ApiKeyDoesNotExistException](err):
+        return KindApiKeyDoesNotExist, "api key does not exist"
+    case is[*RateLimitedException](err):
+        return KindRateLimited, "rate limited"
+    case is[*IdempotencyKeyReusedException](err):
+        return KindIdempotencyKeyReused, "idempotency key reused"
+    case is[*RequestInProgressException](err):
+        return KindRequestInProgress, "request in progress"
+    case is[*OwnershipMismatchException](err):
+        return KindOwnershipMismatch, "listing owner mismatch"
+    case is[*ListingDoesNotExistException](err):
+        return KindListingDoesNotExist, "listing does not exist"
+    case is[*StaleListingException](err):
+        return KindStaleListing, "listing was modified concurrently"
+    case is[*ListingSoldException](err):
+        return KindListingSold, "listing already sold"
+    case is[*SelfPurchaseException](err):
+        return KindSelfPurchase, "cannot buy or reserve own listing"
+    case is[*PermissionDeniedException](err):
+        return KindPermissionDenied, "permission denied"
+    case is[*CategoryDoesNotExistException](err):
+        return KindCategoryDoesNotExist, "category does not exist"
+    case is[*CategoryAlreadyExistException](err):
+        return KindCategoryAlreadyExist, "category already existing"
+    case is[*CategoryRetiredException](err):
+        return KindCategoryRetired, "category is retired"
+    case is[*InvalidInputException](err), is[validator.ValidationErrors](err):
+        return KindInvalidInput, "invalid input"
+    default:
+        return KindInternal, "internal server error"
+    }
+}
+
+// as finds the first error of type T in the chain of err
+func as[T error](err error) (T, bool) {
+    var target T
+    return target, errors.As(err, &target)
+}
+
+// is reports whether the chain of err holds an error of type T
+func is[T error](err error) bool {
+    _, ok := as[T](err)
+    return ok
+}
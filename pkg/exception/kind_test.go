@@ -0,0 +1,31 @@
+package exception
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "testing"
+)
+
+func TestClassify(t *testing.T) {
+    testCases := []struct {
+        err             error
+        expectedKind    Kind
+        expectedMessage string
+    }{
+        {NewUnknownUserException("user1", nil), KindUnknownUser, "unknown user"},
+        {NewInvalidStatusTransitionException("listing is sold", nil), KindInvalidStatusTransition, "listing is sold"},
+        {context.DeadlineExceeded, KindTimeout, "timeout"},
+        {errors.New("connection reset"), KindInternal, "internal server error"},
+        // errors wrapped by the stores and the service keep their kind
+        {fmt.Errorf("failed to put listing: %w", NewCategoryRetiredException("Phones", nil)), KindCategoryRetired, "category is retired"},
+        {fmt.Errorf("failed to buy listing: %w", NewListingSoldException("100001", nil)), KindListingSold, "listing already sold"},
+        {fmt.Errorf("failed to query: %w", context.Canceled), KindCancelled, "cancelled"},
+    }
+    for _, tc := range testCases {
+        kind, message := Classify(tc.err)
+        if kind != tc.expectedKind || message != tc.expectedMessage {
+            t.Fatalf("%v: expected kind %d with message %q, got %d with %q", tc.err, tc.expectedKind, tc.expectedMessage, kind, message)
+        }
+    }
+}
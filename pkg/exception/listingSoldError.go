@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type ListingSoldException struct {
+    Context string
+    Err     error
+}
+
+func NewListingSoldException(message string, err error) *ListingSoldException {
+    return &ListingSoldException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *ListingSoldException) Error() string {
+    return fmt.Sprintf("ListingSoldException: %s: %v", e.Context, e.Err)
+}
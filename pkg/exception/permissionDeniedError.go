@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type PermissionDeniedException struct {
+    Context string
+    Err     error
+}
+
+func NewPermissionDeniedException(message string, err error) *PermissionDeniedException {
+    return &PermissionDeniedException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *PermissionDeniedException) Error() string {
+    return fmt.Sprintf("PermissionDeniedException: %s: %v", e.Context, e.Err)
+}
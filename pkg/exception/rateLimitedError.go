@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type RateLimitedException struct {
+    Context string
+    Err     error
+}
+
+func NewRateLimitedException(message string, err error) *RateLimitedException {
+    return &RateLimitedException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *RateLimitedException) Error() string {
+    return fmt.Sprintf("RateLimitedException: %s: %v", e.Context, e.Err)
+}
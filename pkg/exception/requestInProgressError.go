@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type RequestInProgressException struct {
+    Context string
+    Err     error
+}
+
+func NewRequestInProgressException(message string, err error) *RequestInProgressException {
+    return &RequestInProgressException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *RequestInProgressException) Error() string {
+    return fmt.Sprintf("RequestInProgressException: %s: %v", e.Context, e.Err)
+}
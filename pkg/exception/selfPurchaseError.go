@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type SelfPurchaseException struct {
+    Context string
+    Err     error
+}
+
+func NewSelfPurchaseException(message string, err error) *SelfPurchaseException {
+    return &SelfPurchaseException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *SelfPurchaseException) Error() string {
+    return fmt.Sprintf("SelfPurchaseException: %s: %v", e.Context, e.Err)
+}
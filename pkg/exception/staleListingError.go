@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type StaleListingException struct {
+    Context string
+    Err     error
+}
+
+func NewStaleListingException(message string, err error) *StaleListingException {
+    return &StaleListingException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *StaleListingException) Error() string {
+    return fmt.Sprintf("StaleListingException: %s: %v", e.Context, e.Err)
+}
@@ -0,0 +1,19 @@
+package exception
+
+import "fmt"
+
+type UnknownUserException struct {
+    Context string
+    Err     error
+}
+
+func NewUnknownUserException(message string, err error) *UnknownUserException {
+    return &UnknownUserException{
+        Context: message,
+        Err:     err,
+    }
+}
+
+func (e *UnknownUserException) Error() string {
+    return fmt.Sprintf("UnknownUserException: %s: %v", e.Context, e.Err)
+}
@@ -15,5 +15,5 @@ func NewListingDoesNotExistException(message string, err error) *ListingDoesNotE
 }
 
 func (e *ListingDoesNotExistException) Error() string {
-    return fmt.Sprintf("OwnershipMismatchException: %s: %v", e.Context, e.Err)
+    return fmt.Sprintf("ListingDoesNotExistException: %s: %v", e.Context, e.Err)
 }
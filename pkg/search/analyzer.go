@@ -0,0 +1,52 @@
+// Package search implements the text analysis and BM25 ranking shared by the search indexes of every
+// data.MarketplaceStore implementation
+package search
+
+import (
+    "strings"
+    "unicode"
+)
+
+// stopWords are common English words that carry no meaning for ranking and are left out of the index
+var stopWords = map[string]bool{
+    "a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true, "by": true,
+    "for": true, "if": true, "in": true, "into": true, "is": true, "it": true, "no": true, "not": true, "of": true,
+    "on": true, "or": true, "such": true, "that": true, "the": true, "their": true, "then": true, "there": true,
+    "these": true, "they": true, "this": true, "to": true, "was": true, "will": true, "with": true,
+}
+
+// Analyze splits text into words on every character that is not a letter or digit, folds their case, drops stop words
+// and stems them. The returned terms keep their order and repetitions.
+func Analyze(text string) []string {
+    words := strings.FieldsFunc(text, func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+
+    terms := make([]string, 0, len(words))
+    for _, word := range words {
+        word = strings.ToLower(word)
+        if stopWords[word] {
+            continue
+        }
+        terms = append(terms, Stem(word))
+    }
+    return terms
+}
+
+// DocumentTerms returns the analyzed terms of a listing, which is indexed by its title and description
+func DocumentTerms(title string, description string) []string {
+    return Analyze(title + " " + description)
+}
+
+// UniqueTerms returns the distinct terms in order of first occurrence
+func UniqueTerms(terms []string) []string {
+    seen := make(map[string]bool, len(terms))
+    unique := make([]string, 0, len(terms))
+    for _, term := range terms {
+        if !seen[term] {
+            seen[term] = true
+            unique = append(unique, term)
+        }
+    }
+    return unique
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+    "math"
+    "sort"
+)
+
+// BM25 parameters: k1 saturates the weight of repeated terms and b normalizes by document length
+const (
+    k1 = 1.2
+    b  = 0.75
+)
+
+// Corpus holds the statistics of the indexed documents that BM25 needs
+type Corpus struct {
+    Documents   int            // number of indexed documents
+    TotalLength int            // total number of terms in the indexed documents
+    Frequencies map[string]int // number of indexed documents containing each query term
+}
+
+// Document is an indexed document and its analyzed terms
+type Document struct {
+    ListingId int
+    Terms     []string
+}
+
+// Result is a document that matches at least one query term and its relevance score
+type Result struct {
+    ListingId int
+    Score     float64
+}
+
+// Rank scores documents against the analyzed query terms with Okapi BM25 and returns the matching documents ordered by
+// descending score, breaking ties by ascending listing ID so that results are deterministic
+func Rank(queryTerms []string, documents []Document, corpus Corpus) []Result {
+    averageLength := 1.0
+    if corpus.Documents > 0 && corpus.TotalLength > 0 {
+        averageLength = float64(corpus.TotalLength) / float64(corpus.Documents)
+    }
+
+    queryTerms = UniqueTerms(queryTerms)
+    idf := make(map[string]float64, len(queryTerms))
+    for _, term := range queryTerms {
+        n := float64(corpus.Frequencies[term])
+        idf[term] = math.Log(1 + (float64(corpus.Documents)-n+0.5)/(n+0.5))
+    }
+
+    var results []Result
+    for _, document := range documents {
+        frequencies := make(map[string]int, len(document.Terms))
+        for _, term := range document.Terms {
+            frequencies[term]++
+        }
+
+        lengthNorm := k1 * (1 - b + b*float64(len(document.Terms))/averageLength)
+        score, matched := 0.0, false
+        for _, term := range queryTerms {
+            tf := float64(frequencies[term])
+            if tf == 0 {
+                continue
+            }
+            matched = true
+            score += idf[term] * tf * (k1 + 1) / (tf + lengthNorm)
+        }
+        if matched {
+            results = append(results, Result{ListingId: document.ListingId, Score: score})
+        }
+    }
+
+    sort.Slice(results, func(i, j int) bool {
+        if results[i].Score != results[j].Score {
+            return results[i].Score > results[j].Score
+        }
+        return results[i].ListingId < results[j].ListingId
+    })
+    return results
+}
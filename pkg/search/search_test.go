@@ -0,0 +1,74 @@
+package search
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestStem(t *testing.T) {
+    testCases := map[string]string{
+        "caresses":        "caress",
+        "ponies":          "poni",
+        "cats":            "cat",
+        "feed":            "feed",
+        "agreed":          "agre",
+        "plastered":       "plaster",
+        "motoring":        "motor",
+        "sing":            "sing",
+        "hopping":         "hop",
+        "falling":         "fall",
+        "filing":          "file",
+        "happy":           "happi",
+        "relational":      "relat",
+        "conditional":     "condit",
+        "rational":        "ration",
+        "generalizations": "gener",
+        "hopefulness":     "hope",
+        "adjustment":      "adjust",
+        "adoption":        "adopt",
+        "controlling":     "control",
+        "running":         "run",
+        "runs":            "run",
+        "phones":          "phone",
+        "shoes":           "shoe",
+        "go":              "go",
+        "café":            "café",
+    }
+    for word, expected := range testCases {
+        if stem := Stem(word); stem != expected {
+            t.Errorf("expected %q to stem to %q, got %q", word, expected, stem)
+        }
+    }
+}
+
+func TestAnalyze(t *testing.T) {
+    terms := Analyze("The Running-Shoes, for RUNNERS: 2 pairs!")
+    expected := []string{"run", "shoe", "runner", "2", "pair"}
+    if !reflect.DeepEqual(terms, expected) {
+        t.Fatalf("expected terms %v, got %v", expected, terms)
+    }
+}
+
+func TestRank(t *testing.T) {
+    documents := []Document{
+        {ListingId: 100001, Terms: Analyze("Phone case, fits every phone")},
+        {ListingId: 100002, Terms: Analyze("Phone")},
+        {ListingId: 100003, Terms: Analyze("Running shoes")},
+        {ListingId: 100004, Terms: Analyze("Phone")},
+    }
+    corpus := Corpus{Documents: 4, TotalLength: 9, Frequencies: map[string]int{"phone": 3}}
+
+    results := Rank(Analyze("phones"), documents, corpus)
+    var listingIds []int
+    for _, result := range results {
+        listingIds = append(listingIds, result.ListingId)
+        if result.Score <= 0 {
+            t.Fatalf("expected a positive score, got %v", result)
+        }
+    }
+    // short documents rank above the long one despite fewer occurrences, and equal scores are ordered by listing ID
+    expected := []int{100002, 100004, 100001}
+    if !reflect.DeepEqual(listingIds, expected) {
+        t.Fatalf("expected ranking %v, got %v", expected, listingIds)
+    }
+}
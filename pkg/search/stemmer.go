@@ -0,0 +1,203 @@
+package search
+
+import "strings"
+
+// suffixRule replaces a suffix of a word if the measure of the remaining stem is above a minimum
+type suffixRule struct {
+    suffix      string
+    replacement string
+}
+
+// rules of steps 2 to 4, ordered so that the longest matching suffix is found first
+var (
+    step2Rules = []suffixRule{
+        {"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+        {"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"},
+        {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+        {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+    }
+    step3Rules = []suffixRule{
+        {"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+    }
+    step4Suffixes = []string{
+        "ement", "ment", "ance", "ence", "able", "ible", "ant", "ent", "ion", "ism", "ate", "iti", "ous", "ive", "ize",
+        "al", "er", "ic", "ou",
+    }
+)
+
+// Stem reduces a lower case English word to its stem with the Porter stemming algorithm, so that inflections such as
+// "running" and "runs" both become "run". Words of up to two letters and words with other characters than a to z are
+// returned unchanged.
+func Stem(word string) string {
+    if len(word) <= 2 {
+        return word
+    }
+    for i := 0; i < len(word); i++ {
+        if word[i] < 'a' || word[i] > 'z' {
+            return word
+        }
+    }
+
+    word = step1a(word)
+    word = step1b(word)
+    word = step1c(word)
+    word = replaceSuffix(word, step2Rules, 0)
+    word = replaceSuffix(word, step3Rules, 0)
+    word = step4(word)
+    word = step5(word)
+    return word
+}
+
+// step1a removes plurals
+func step1a(word string) string {
+    switch {
+    case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "ies"):
+        return word[:len(word)-2]
+    case strings.HasSuffix(word, "ss"):
+        return word
+    case strings.HasSuffix(word, "s"):
+        return word[:len(word)-1]
+    default:
+        return word
+    }
+}
+
+// step1b removes past participles and gerunds, restoring an "e" or undoubling the final consonant where needed
+func step1b(word string) string {
+    if strings.HasSuffix(word, "eed") {
+        if measure(word[:len(word)-3]) > 0 {
+            return word[:len(word)-1]
+        }
+        return word
+    }
+
+    var stem string
+    switch {
+    case strings.HasSuffix(word, "ed") && containsVowel(word[:len(word)-2]):
+        stem = word[:len(word)-2]
+    case strings.HasSuffix(word, "ing") && containsVowel(word[:len(word)-3]):
+        stem = word[:len(word)-3]
+    default:
+        return word
+    }
+
+    switch {
+    case strings.HasSuffix(stem, "at"), strings.HasSuffix(stem, "bl"), strings.HasSuffix(stem, "iz"):
+        return stem + "e"
+    case endsWithDoubleConsonant(stem) && !strings.ContainsAny(stem[len(stem)-1:], "lsz"):
+        return stem[:len(stem)-1]
+    case measure(stem) == 1 && endsWithCvc(stem):
+        return stem + "e"
+    default:
+        return stem
+    }
+}
+
+// step1c turns a final "y" into "i" if the stem has a vowel
+func step1c(word string) string {
+    if strings.HasSuffix(word, "y") && containsVowel(word[:len(word)-1]) {
+        return word[:len(word)-1] + "i"
+    }
+    return word
+}
+
+// step4 removes the suffixes of stems with a measure above 1
+func step4(word string) string {
+    for _, suffix := range step4Suffixes {
+        if !strings.HasSuffix(word, suffix) {
+            continue
+        }
+        stem := word[:len(word)-len(suffix)]
+        if measure(stem) <= 1 {
+            return word
+        }
+        if suffix == "ion" && !strings.HasSuffix(stem, "s") && !strings.HasSuffix(stem, "t") {
+            return word
+        }
+        return stem
+    }
+    return word
+}
+
+// step5 removes a final "e" and undoubles a final "ll" on long stems
+func step5(word string) string {
+    if strings.HasSuffix(word, "e") {
+        stem := word[:len(word)-1]
+        if m := measure(stem); m > 1 || (m == 1 && !endsWithCvc(stem)) {
+            word = stem
+        }
+    }
+    if strings.HasSuffix(word, "ll") && measure(word) > 1 {
+        word = word[:len(word)-1]
+    }
+    return word
+}
+
+// replaceSuffix applies the rule of the longest matching suffix if the measure of the stem is above minMeasure
+func replaceSuffix(word string, rules []suffixRule, minMeasure int) string {
+    for _, rule := range rules {
+        if !strings.HasSuffix(word, rule.suffix) {
+            continue
+        }
+        stem := word[:len(word)-len(rule.suffix)]
+        if measure(stem) > minMeasure {
+            return stem + rule.replacement
+        }
+        return word
+    }
+    return word
+}
+
+// isConsonant reports whether the letter at i is a consonant. "y" is a consonant unless it follows a consonant.
+func isConsonant(word string, i int) bool {
+    switch word[i] {
+    case 'a', 'e', 'i', 'o', 'u':
+        return false
+    case 'y':
+        return i == 0 || !isConsonant(word, i-1)
+    default:
+        return true
+    }
+}
+
+// measure counts the vowel-consonant sequences of a stem, i.e. m in [C](VC){m}[V]
+func measure(stem string) int {
+    m, i := 0, 0
+    for i < len(stem) && isConsonant(stem, i) {
+        i++
+    }
+    for i < len(stem) {
+        for i < len(stem) && !isConsonant(stem, i) {
+            i++
+        }
+        if i == len(stem) {
+            break
+        }
+        for i < len(stem) && isConsonant(stem, i) {
+            i++
+        }
+        m++
+    }
+    return m
+}
+
+func containsVowel(stem string) bool {
+    for i := range stem {
+        if !isConsonant(stem, i) {
+            return true
+        }
+    }
+    return false
+}
+
+func endsWithDoubleConsonant(stem string) bool {
+    n := len(stem)
+    return n >= 2 && stem[n-1] == stem[n-2] && isConsonant(stem, n-1)
+}
+
+// endsWithCvc reports whether the stem ends with consonant-vowel-consonant where the last consonant is not w, x or y
+func endsWithCvc(stem string) bool {
+    n := len(stem)
+    return n >= 3 && isConsonant(stem, n-3) && !isConsonant(stem, n-2) && isConsonant(stem, n-1) &&
+        !strings.ContainsAny(stem[n-1:], "wxy")
+}
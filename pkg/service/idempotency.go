@@ -0,0 +1,119 @@
+package service
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/exception"
+    "time"
+)
+
+// idempotencyKeyContextKey is the context key of the idempotency key of a command
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context running the mutating commands of a user once per key: a command replayed with
+// the same key returns the response of the first one instead of running again. An empty key leaves ctx unchanged.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+    if key == "" {
+        return ctx
+    }
+    return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotent runs a mutating command of username once per idempotency key of ctx, and runs it every time without a
+// key. The key is held while the command runs, and the JSON of its result is kept for the idempotency TTL once it
+// succeeds. A failed command releases the key, so that it can be tried again, unless its context ended: it may have
+// gone through then, so the key stays held without a response for another command timeout. A command whose result
+// cannot be stored keeps the key held for the idempotency TTL, so that it does not run twice.
+// Returns the result of the first run under the key, exception.IdempotencyKeyReusedException if the key was used with
+// another command or other parameters, or exception.RequestInProgressException if the first run has not finished or
+// its outcome is unknown
+func idempotent[T any](ctx context.Context, m *Marketplace, username string, command string, params []any, run func() (T, error)) (T, error) {
+    var result T
+    key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+    if key == "" {
+        return run()
+    }
+    if len(key) > model.MaxIdempotencyKeyLength {
+        return result, exception.NewInvalidInputException(
+            fmt.Sprintf("idempotency key is longer than %d characters", model.MaxIdempotencyKeyLength), nil)
+    }
+    requestHash, err := model.HashIdempotentRequest(command, params...)
+    if err != nil {
+        return result, err
+    }
+
+    // the key is held until the deadline of the command, after which it is free again if the command never finished
+    now := time.Now()
+    deadline, hasDeadline := ctx.Deadline()
+    if !hasDeadline {
+        deadline = now.Add(m.timeout)
+    }
+    record := model.IdempotencyRecord{
+        Key:         model.IdempotencyRecordKey(username, key),
+        RequestHash: requestHash,
+        CreatedAt:   now,
+        ExpiresAt:   deadline,
+    }
+    existing, err := m.store.PutIdempotencyRecord(ctx, record)
+    if err != nil {
+        return result, err
+    }
+    if existing != nil {
+        if existing.RequestHash != requestHash {
+            return result, exception.NewIdempotencyKeyReusedException(
+                fmt.Sprintf("idempotency key '%s' of user '%s' was used with other parameters", key, username), nil)
+        }
+        if !existing.IsCompleted() {
+            return result, exception.NewRequestInProgressException(fmt.Sprintf(
+                "command with idempotency key '%s' of user '%s' is still running or its outcome is unknown", key,
+                username), nil)
+        }
+        m.log.Infow("Replaying command", "user", username, "command", command, "idempotencyKey", key)
+        err = json.Unmarshal(existing.Response, &result)
+        return result, err
+    }
+
+    result, err = run()
+    // the context of the command may be over, while its record is updated in any case
+    storeCtx, cancel := context.WithTimeout(context.Background(), m.timeout)
+    defer cancel()
+    if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+        // a write sent before the context ended may still be applied, which takes no longer than another command
+        // timeout, so the key is held until then and the command runs again if it was not
+        holdIdempotencyKey(storeCtx, m, record, time.Now().Add(m.Timeout(command)))
+        return result, err
+    }
+    if err != nil {
+        releaseErr := m.store.DeleteIdempotencyRecord(storeCtx, record.Key)
+        if releaseErr != nil {
+            m.log.Errorf("Error releasing idempotency key '%s' of user '%s': %v", key, username, releaseErr)
+        }
+        return result, err
+    }
+
+    record.Response, err = json.Marshal(result)
+    if err == nil {
+        record.ExpiresAt = time.Now().Add(m.idempotencyTtl)
+        err = m.store.CompleteIdempotencyRecord(storeCtx, record)
+    }
+    if err != nil {
+        // the command succeeded, so its result is returned even if it cannot be replayed, and the key is held for the
+        // idempotency TTL so that a replay does not run it twice
+        m.log.Errorf("Error storing the response of idempotency key '%s' of user '%s': %v", key, username, err)
+        record.Response = nil
+        holdIdempotencyKey(storeCtx, m, record, time.Now().Add(m.idempotencyTtl))
+    }
+    return result, nil
+}
+
+// holdIdempotencyKey keeps the key of a record without a response held until expiresAt
+func holdIdempotencyKey(ctx context.Context, m *Marketplace, record model.IdempotencyRecord, expiresAt time.Time) {
+    record.ExpiresAt = expiresAt
+    err := m.store.CompleteIdempotencyRecord(ctx, record)
+    if err != nil {
+        m.log.Errorf("Error holding idempotency key of record '%s': %v", record.Key, err)
+    }
+}
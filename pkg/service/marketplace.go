@@ -0,0 +1,722 @@
+package service
+
+import (
+    "context"
+    "fmt"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "os"
+    "strings"
+    "time"
+)
+
+// Marketplace implements the marketplace operations shared by the CLI and the server modes.
+// Reading operations authenticate the user first and fail with exception.UnknownUserException if the user is not
+// registered. Mutating operations take the token of a session opened with Login instead, and fail with
+// exception.InvalidSessionException if it is unknown, expired or revoked. Both accept an API key of the user in place of
+// the username or token, failing with exception.InvalidApiKeyException if it is unknown, expired or revoked, and with
+// exception.PermissionDeniedException if its scope does not cover the operation. Operations on the resources of other users and
+// managing the category catalog are reserved to roles by the authorization policy. Every authenticated operation and
+// login attempt is rate limited per user and command class, failing with exception.RateLimitedException. Every operation
+// takes the context of its command, and fails with the error of the context once it is cancelled or past its deadline.
+// Operations changing listings, categories and roles run once per idempotency key set with WithIdempotencyKey.
+type Marketplace struct {
+    store          data.MarketplaceStore
+    admins         map[string]bool
+    sessionTtl     time.Duration
+    rateLimits     map[enum.CommandClass]model.RateLimit
+    // timeout is the deadline of every command, unless timeouts overrides it for a CLI command
+    timeout        time.Duration
+    timeouts       map[string]time.Duration
+    // idempotencyTtl is how long the response of a command run with an idempotency key is replayed
+    idempotencyTtl time.Duration
+    log            *zap.SugaredLogger
+}
+
+// NewMarketplace creates the marketplace operations on top of store, with the admins listed in the ADMIN_USERS
+// environment variable, sessions lasting SESSION_TTL, the rate limits of the RATE_LIMIT_* variables, the command
+// deadlines of COMMAND_TIMEOUT and COMMAND_TIMEOUTS, and responses to idempotency keys replayed for IDEMPOTENCY_TTL
+func NewMarketplace(store data.MarketplaceStore, log *zap.SugaredLogger) *Marketplace {
+    admins := make(map[string]bool)
+    for _, username := range strings.Split(os.Getenv(constant.AdminUsersEnvKey), ",") {
+        username = strings.TrimSpace(username)
+        if username != "" {
+            admins[model.CanonicalKey(username)] = true
+        }
+    }
+
+    sessionTtl := constant.DefaultSessionTtl
+    if value, exists := os.LookupEnv(constant.SessionTtlEnvKey); exists {
+        ttl, err := time.ParseDuration(value)
+        if err != nil || ttl <= 0 {
+            log.Fatalf("invalid %s '%s', expected a positive duration such as 24h", constant.SessionTtlEnvKey, value)
+        }
+        sessionTtl = ttl
+    }
+
+    timeout := constant.DefaultCommandTimeout
+    if value, exists := os.LookupEnv(constant.CommandTimeoutEnvKey); exists {
+        duration, err := time.ParseDuration(value)
+        if err != nil || duration <= 0 {
+            log.Fatalf("invalid %s '%s', expected a positive duration such as 10s", constant.CommandTimeoutEnvKey, value)
+        }
+        timeout = duration
+    }
+    timeouts := make(map[string]time.Duration)
+    for _, pair := range strings.Split(os.Getenv(constant.CommandTimeoutsEnvKey), ",") {
+        if strings.TrimSpace(pair) == "" {
+            continue
+        }
+        command, value, _ := strings.Cut(pair, "=")
+        duration, err := time.ParseDuration(strings.TrimSpace(value))
+        if err != nil || duration <= 0 {
+            log.Fatalf("invalid %s '%s', expected pairs such as SEARCH=30s", constant.CommandTimeoutsEnvKey, pair)
+        }
+        timeouts[strings.ToUpper(strings.TrimSpace(command))] = duration
+    }
+
+    idempotencyTtl := constant.DefaultIdempotencyTtl
+    if value, exists := os.LookupEnv(constant.IdempotencyTtlEnvKey); exists {
+        ttl, err := time.ParseDuration(value)
+        if err != nil || ttl <= 0 {
+            log.Fatalf("invalid %s '%s', expected a positive duration such as 24h", constant.IdempotencyTtlEnvKey, value)
+        }
+        idempotencyTtl = ttl
+    }
+
+    return &Marketplace{
+        store:          store,
+        admins:         admins,
+        sessionTtl:     sessionTtl,
+        rateLimits:     loadRateLimits(log),
+        timeout:        timeout,
+        timeouts:       timeouts,
+        idempotencyTtl: idempotencyTtl,
+        log:            log,
+    }
+}
+
+// Timeout returns the deadline of a CLI command, or of the requests to the servers if command is empty
+func (m *Marketplace) Timeout(command string) time.Duration {
+    if timeout, exists := m.timeouts[strings.ToUpper(command)]; exists {
+        return timeout
+    }
+    return m.timeout
+}
+
+// Register registers a new user with a password. A user registered before passwords existed sets its password.
+// Returns nil if the user already exists
+func (m *Marketplace) Register(ctx context.Context, username string, password string) (*model.User, error) {
+    err := model.User{Username: username}.Validate()
+    if err != nil {
+        return nil, err
+    }
+    if model.IsApiKey(username) {
+        return nil, exception.NewInvalidInputException(
+            fmt.Sprintf("username cannot start with '%s'", model.ApiKeyPrefix), nil)
+    }
+    if len(password) < model.MinPasswordLength || len(password) > model.MaxPasswordLength {
+        return nil, exception.NewInvalidInputException(
+            fmt.Sprintf("password must be between %d and %d bytes", model.MinPasswordLength, model.MaxPasswordLength), nil)
+    }
+
+    passwordHash, err := model.HashPassword(password)
+    if err != nil {
+        return nil, err
+    }
+    return m.store.PutUser(ctx, username, passwordHash)
+}
+
+// Login opens a session of a user that expires after the session TTL
+// Returns the token of the session, otherwise exception.InvalidCredentialsException if the user is unknown or the
+// password is wrong. Attempts are rate limited by username, whether they succeed or not.
+func (m *Marketplace) Login(ctx context.Context, username string, password string) (string, *model.Session, error) {
+    err := m.throttle(ctx, username, enum.CommandClassLogin)
+    if err != nil {
+        return "", nil, err
+    }
+
+    user, err := m.store.GetUser(ctx, username)
+    if err != nil {
+        return "", nil, err
+    }
+    if user == nil || !user.CheckPassword(password) {
+        m.log.Debugf("Failed login of user '%s'", username)
+        return "", nil, exception.NewInvalidCredentialsException("invalid username or password", nil)
+    }
+
+    session, token, err := model.NewSession(user.Username, m.sessionTtl)
+    if err != nil {
+        return "", nil, err
+    }
+    err = m.store.PutSession(ctx, session)
+    if err != nil {
+        return "", nil, err
+    }
+
+    m.log.Infof("User '%s' logged in", user.Username)
+    return token, &session, nil
+}
+
+// Logout revokes the session of a token. Revoking an unknown or expired session succeeds.
+func (m *Marketplace) Logout(ctx context.Context, token string) error {
+    return m.store.DeleteSession(ctx, model.HashSessionToken(token))
+}
+
+// CreateListing creates a listing owned by the user of the session of token, either active or as a draft to be published later
+// Returns nil if the listing ID is already taken
+func (m *Marketplace) CreateListing(ctx context.Context, token string, title string, description string, price int, category string, draft bool) (*model.Listing, error) {
+    user, err := m.authToken(ctx, token, enum.ApiKeyScopeListingsWrite)
+    if err != nil {
+        return nil, err
+    }
+
+    status := enum.ListingStatusActive
+    if draft {
+        status = enum.ListingStatusDraft
+    }
+    return idempotent(ctx, m, user.Username, "CREATE_LISTING", []any{title, description, price, category, draft},
+        func() (*model.Listing, error) {
+            return m.store.PutListing(ctx, user.Username, title, description, price, category, status)
+        })
+}
+
+// GetListing retrieves a listing by listingId
+// Returns nil if the listing does not exist
+func (m *Marketplace) GetListing(ctx context.Context, username string, listingId int) (*model.Listing, error) {
+    _, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    return m.store.GetListing(ctx, listingId)
+}
+
+// GetCategory retrieves a page of the active listings of a category sorted by price or creation time, optionally
+// filtered by price range, creation time and title
+// A query without a limit retrieves every listing after the cursor. Returns an empty page if the category does not exist
+func (m *Marketplace) GetCategory(ctx context.Context, username string, query model.CategoryQuery) (*model.ListingPage, error) {
+    _, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    if query.Limit < 0 || query.Limit > constant.MaxCategoryPageSize {
+        return nil, exception.NewInvalidInputException(fmt.Sprintf("limit must be between 1 and %d", constant.MaxCategoryPageSize), nil)
+    }
+    err = query.Validate()
+    if err != nil {
+        return nil, err
+    }
+    query.Category, err = m.resolveCategory(ctx, query.Category)
+    if err != nil {
+        return nil, err
+    }
+
+    return m.store.GetCategory(ctx, query)
+}
+
+// Search retrieves the active listings whose title or description match the query text, most relevant first
+// A query without a limit retrieves every match
+func (m *Marketplace) Search(ctx context.Context, username string, query model.SearchQuery) ([]model.Listing, error) {
+    _, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    if query.Limit < 0 || query.Limit > constant.MaxSearchLimit {
+        return nil, exception.NewInvalidInputException(fmt.Sprintf("limit must be between 1 and %d", constant.MaxSearchLimit), nil)
+    }
+    err = query.Validate()
+    if err != nil {
+        return nil, err
+    }
+    if query.Category != "" {
+        query.Category, err = m.resolveCategory(ctx, query.Category)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return m.store.SearchListings(ctx, query)
+}
+
+// GetTopCategory retrieves the subcategory of parent, or the top-level category if parent is empty, with the highest
+// total number of listings including those of its subcategories
+// Returns nil if there is no such category
+func (m *Marketplace) GetTopCategory(ctx context.Context, username string, parent string) (*model.CategoryMetric, error) {
+    _, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    if parent != "" {
+        parent, err = m.resolveCategory(ctx, parent)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return m.store.GetTopCategory(ctx, parent)
+}
+
+// GetTopCategories ranks the subcategories of the query parent, or the top-level categories, by their total number of
+// listings including those of their subcategories, breaking ties by descending name
+// A query without a limit ranks every category
+func (m *Marketplace) GetTopCategories(ctx context.Context, username string, query model.TopCategoriesQuery) ([]model.CategoryMetric, error) {
+    _, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    if query.Limit < 0 || query.Limit > constant.MaxTopCategoriesLimit {
+        return nil, exception.NewInvalidInputException(fmt.Sprintf("limit must be between 1 and %d", constant.MaxTopCategoriesLimit), nil)
+    }
+    if query.Parent != "" {
+        query.Parent, err = m.resolveCategory(ctx, query.Parent)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return m.store.GetTopCategories(ctx, query)
+}
+
+// UpdateListing changes the fields of a listing owned by the user of the session of token that are set in update
+func (m *Marketplace) UpdateListing(ctx context.Context, token string, listingId int, update model.ListingUpdate) (*model.Listing, error) {
+    user, err := m.authToken(ctx, token, enum.ApiKeyScopeListingsWrite)
+    if err != nil {
+        return nil, err
+    }
+
+    if update.IsEmpty() {
+        return nil, exception.NewInvalidInputException("no listing field to update", nil)
+    }
+
+    return idempotent(ctx, m, user.Username, "UPDATE_LISTING", []any{listingId, update}, func() (*model.Listing, error) {
+        return m.store.UpdateListing(ctx, user.Username, listingId, update)
+    })
+}
+
+// DeleteListing deletes a listing owned by the user of the session of token. Admins delete the listings of any user.
+func (m *Marketplace) DeleteListing(ctx context.Context, token string, listingId int) error {
+    user, err := m.authToken(ctx, token, enum.ApiKeyScopeListingsWrite)
+    if err != nil {
+        return err
+    }
+
+    _, err = idempotent(ctx, m, user.Username, "DELETE_LISTING", []any{listingId}, func() (struct{}, error) {
+        return struct{}{}, m.deleteListing(ctx, user, listingId)
+    })
+    return err
+}
+
+// deleteListing deletes a listing on behalf of an authenticated user
+func (m *Marketplace) deleteListing(ctx context.Context, user *model.User, listingId int) error {
+    listing, err := m.store.GetListing(ctx, listingId)
+    if err != nil {
+        return err
+    }
+    if listing == nil {
+        return exception.NewListingDoesNotExistException(fmt.Sprintf("listing with listingId %d does not exist", listingId), nil)
+    }
+
+    // the owner of a listing never changes, so deleting on behalf of the owner passes the store ownership check
+    owner := user.Username
+    if listing.Username != user.Username && m.allows(user, ActionDeleteAnyListing, listingResource(listingId)) {
+        owner = listing.Username
+    }
+
+    err = m.store.DeleteListing(ctx, owner, listingId)
+    if err == nil && owner != user.Username {
+        m.log.Infof("User '%s' deleted listing %d of user '%s'", user.Username, listingId, owner)
+    }
+    return err
+}
+
+// TransitionListing publishes, reserves, unreserves or withdraws a listing on behalf of the user of the session of token,
+// or hides or unhides it if the role of the user allows it
+// Buying is done with BuyListing, and expiry is not available to users. Hiding with an API key takes the admin scope.
+func (m *Marketplace) TransitionListing(ctx context.Context, token string, listingId int, transition enum.ListingTransition) (*model.Listing, error) {
+    moderation := false
+    switch transition {
+    case enum.ListingTransitionPublish, enum.ListingTransitionReserve, enum.ListingTransitionUnreserve, enum.ListingTransitionWithdraw:
+    case enum.ListingTransitionHide, enum.ListingTransitionUnhide:
+        moderation = true
+    default:
+        return nil, exception.NewInvalidInputException(fmt.Sprintf("transition '%s' is not available", transition), nil)
+    }
+
+    scope := enum.ApiKeyScopeListingsWrite
+    if moderation {
+        scope = enum.ApiKeyScopeAdmin
+    }
+    user, err := m.authToken(ctx, token, scope)
+    if err != nil {
+        return nil, err
+    }
+    if moderation {
+        err = m.authorize(user, ActionHideListing, listingResource(listingId))
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return idempotent(ctx, m, user.Username, "TRANSITION_LISTING", []any{listingId, transition}, func() (*model.Listing, error) {
+        return m.store.TransitionListing(ctx, user.Username, listingId, transition)
+    })
+}
+
+// BuyListing buys a listing on behalf of the user of the session of token and returns the recorded order
+func (m *Marketplace) BuyListing(ctx context.Context, token string, listingId int) (*model.Order, error) {
+    user, err := m.authToken(ctx, token, enum.ApiKeyScopeListingsWrite)
+    if err != nil {
+        return nil, err
+    }
+
+    return idempotent(ctx, m, user.Username, "BUY", []any{listingId}, func() (*model.Order, error) {
+        return m.store.BuyListing(ctx, user.Username, listingId)
+    })
+}
+
+// GetOrders retrieves the orders in which username is the buyer or the seller, newest first
+func (m *Marketplace) GetOrders(ctx context.Context, username string) ([]model.Order, error) {
+    user, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    return m.store.GetOrders(ctx, user.Username)
+}
+
+// CreateCategory adds a category to the catalog on behalf of an admin
+// Returns nil if the category already exists
+func (m *Marketplace) CreateCategory(ctx context.Context, token string, name string) (*model.Category, error) {
+    admin, err := m.authSessionFor(ctx, token, ActionManageCategories, categoryResource(name))
+    if err != nil {
+        return nil, err
+    }
+
+    return idempotent(ctx, m, admin.Username, "CREATE_CATEGORY", []any{name}, func() (*model.Category, error) {
+        category, err := m.store.PutCategory(ctx, name)
+        if err == nil && category != nil {
+            m.log.Infof("Admin '%s' created category '%s'", admin.Username, name)
+        }
+        return category, err
+    })
+}
+
+// GetCategories retrieves the category catalog sorted by name
+func (m *Marketplace) GetCategories(ctx context.Context, username string) ([]model.Category, error) {
+    _, err := m.authUser(ctx, username)
+    if err != nil {
+        return nil, err
+    }
+
+    return m.store.GetCategories(ctx)
+}
+
+// RenameCategory renames a category and its subcategories on behalf of an admin, moving their listings
+func (m *Marketplace) RenameCategory(ctx context.Context, token string, from string, to string) error {
+    admin, err := m.authSessionFor(ctx, token, ActionManageCategories, categoryResource(from))
+    if err != nil {
+        return err
+    }
+
+    _, err = idempotent(ctx, m, admin.Username, "RENAME_CATEGORY", []any{from, to}, func() (struct{}, error) {
+        err := m.store.RenameCategory(ctx, from, to)
+        if err == nil {
+            m.log.Infof("Admin '%s' renamed category '%s' to '%s'", admin.Username, from, to)
+        }
+        return struct{}{}, err
+    })
+    return err
+}
+
+// MergeCategory merges a category and its subcategories into another category on behalf of an admin, moving their
+// listings
+func (m *Marketplace) MergeCategory(ctx context.Context, token string, from string, to string) error {
+    admin, err := m.authSessionFor(ctx, token, ActionManageCategories, categoryResource(from))
+    if err != nil {
+        return err
+    }
+
+    _, err = idempotent(ctx, m, admin.Username, "MERGE_CATEGORY", []any{from, to}, func() (struct{}, error) {
+        err := m.store.MergeCategory(ctx, from, to)
+        if err == nil {
+            m.log.Infof("Admin '%s' merged category '%s' into '%s'", admin.Username, from, to)
+        }
+        return struct{}{}, err
+    })
+    return err
+}
+
+// RetireCategory closes a category and its subcategories to new listings on behalf of an admin
+func (m *Marketplace) RetireCategory(ctx context.Context, token string, name string) error {
+    admin, err := m.authSessionFor(ctx, token, ActionManageCategories, categoryResource(name))
+    if err != nil {
+        return err
+    }
+
+    _, err = idempotent(ctx, m, admin.Username, "RETIRE_CATEGORY", []any{name}, func() (struct{}, error) {
+        err := m.store.RetireCategory(ctx, name)
+        if err == nil {
+            m.log.Infof("Admin '%s' retired category '%s'", admin.Username, name)
+        }
+        return struct{}{}, err
+    })
+    return err
+}
+
+// GrantRole grants the moderator or admin role to a user on behalf of an admin
+// Returns exception.UnknownUserException if the user does not exist
+func (m *Marketplace) GrantRole(ctx context.Context, token string, username string, role enum.Role) (*model.User, error) {
+    admin, err := m.authSessionFor(ctx, token, ActionManageRoles, userResource(username))
+    if err != nil {
+        return nil, err
+    }
+
+    if role != enum.RoleModerator && role != enum.RoleAdmin {
+        return nil, exception.NewInvalidInputException(
+            fmt.Sprintf("role '%s' cannot be granted, expected %s or %s", role, enum.RoleModerator, enum.RoleAdmin), nil)
+    }
+
+    return idempotent(ctx, m, admin.Username, "GRANT_ROLE", []any{username, role}, func() (*model.User, error) {
+        return m.setRole(ctx, admin, username, role)
+    })
+}
+
+// RevokeRole makes a user a regular user again on behalf of an admin. The users listed in ADMIN_USERS stay admins.
+// Returns exception.UnknownUserException if the user does not exist
+func (m *Marketplace) RevokeRole(ctx context.Context, token string, username string) (*model.User, error) {
+    admin, err := m.authSessionFor(ctx, token, ActionManageRoles, userResource(username))
+    if err != nil {
+        return nil, err
+    }
+
+    return idempotent(ctx, m, admin.Username, "REVOKE_ROLE", []any{username}, func() (*model.User, error) {
+        return m.setRole(ctx, admin, username, enum.RoleUser)
+    })
+}
+
+// setRole stores the role of a user on behalf of an authorized admin
+func (m *Marketplace) setRole(ctx context.Context, admin *model.User, username string, role enum.Role) (*model.User, error) {
+    user, err := m.store.SetUserRole(ctx, username, role)
+    if err != nil {
+        return nil, err
+    }
+    if user == nil {
+        return nil, exception.NewUnknownUserException(fmt.Sprintf("user '%s' does not exist", username), nil)
+    }
+
+    m.log.Infof("Admin '%s' set the role of user '%s' to %s", admin.Username, user.Username, role)
+    return user, nil
+}
+
+// CreateApiKey creates an API key of the user of the session of token with scope, expiring after ttl. API keys
+// cannot be managed with API keys.
+// Returns the key, which is only known to the caller, otherwise exception.InvalidInputException if the scope or ttl are
+// invalid
+func (m *Marketplace) CreateApiKey(ctx context.Context, token string, scope enum.ApiKeyScope, ttl time.Duration) (string, *model.ApiKey, error) {
+    user, err := m.authSessionOnly(ctx, token)
+    if err != nil {
+        return "", nil, err
+    }
+
+    if !scope.IsValid() {
+        return "", nil, exception.NewInvalidInputException(fmt.Sprintf("unknown api key scope '%s'", scope), nil)
+    }
+    if ttl <= 0 || ttl > constant.MaxApiKeyTtl {
+        return "", nil, exception.NewInvalidInputException(
+            fmt.Sprintf("api key lifetime must be positive and at most %v", constant.MaxApiKeyTtl), nil)
+    }
+
+    apiKey, key, err := model.NewApiKey(user.Username, scope, ttl)
+    if err != nil {
+        return "", nil, err
+    }
+    err = m.store.PutApiKey(ctx, apiKey)
+    if err != nil {
+        return "", nil, err
+    }
+
+    m.log.Infof("User '%s' created api key %s with scope %s", user.Username, apiKey.KeyId, scope)
+    return key, &apiKey, nil
+}
+
+// GetApiKeys retrieves the API keys of the user of the session of token, oldest first
+func (m *Marketplace) GetApiKeys(ctx context.Context, token string) ([]model.ApiKey, error) {
+    user, err := m.authSessionOnly(ctx, token)
+    if err != nil {
+        return nil, err
+    }
+
+    return m.store.GetApiKeys(ctx, user.Username)
+}
+
+// RevokeApiKey revokes an API key of the user of the session of token by its ID
+// Returns exception.ApiKeyDoesNotExistException if the user has no such key
+func (m *Marketplace) RevokeApiKey(ctx context.Context, token string, keyId string) error {
+    user, err := m.authSessionOnly(ctx, token)
+    if err != nil {
+        return err
+    }
+
+    apiKey, err := m.store.GetApiKey(ctx, keyId)
+    if err != nil {
+        return err
+    }
+    // the keys of other users are reported as missing so that their IDs are not disclosed
+    if apiKey == nil || model.CanonicalKey(apiKey.Username) != user.Key() {
+        return exception.NewApiKeyDoesNotExistException(fmt.Sprintf("api key %s does not exist", keyId), nil)
+    }
+
+    err = m.store.DeleteApiKey(ctx, keyId)
+    if err == nil {
+        m.log.Infof("User '%s' revoked api key %s", user.Username, keyId)
+    }
+    return err
+}
+
+// listingResource, categoryResource and userResource name the resources of the authorization decisions in the logs
+func listingResource(listingId int) string {
+    return fmt.Sprintf("listing %d", listingId)
+}
+
+func categoryResource(name string) string {
+    return fmt.Sprintf("category '%s'", name)
+}
+
+func userResource(username string) string {
+    return fmt.Sprintf("user '%s'", username)
+}
+
+// resolveCategory returns the spelling of a category in the catalog, under which its listings are stored, so that
+// categories are queried whatever their case. A category missing from the catalog is returned unchanged.
+func (m *Marketplace) resolveCategory(ctx context.Context, category string) (string, error) {
+    entry, err := m.store.LookupCategory(ctx, category)
+    if err != nil {
+        return "", err
+    }
+    if entry == nil {
+        return category, nil
+    }
+    return entry.Name, nil
+}
+
+// authToken authenticates the user of an API key with scope, or of the session of a token, rate limited by the class
+// of scope
+// Returns the user if authenticated, otherwise the errors of authApiKey or authSession, or
+// exception.RateLimitedException
+func (m *Marketplace) authToken(ctx context.Context, token string, scope enum.ApiKeyScope) (*model.User, error) {
+    var user *model.User
+    var err error
+    if model.IsApiKey(token) {
+        user, err = m.authApiKey(ctx, token, scope)
+    } else {
+        user, err = m.authSession(ctx, token)
+    }
+    if err != nil {
+        return nil, err
+    }
+    err = m.throttle(ctx, user.Username, scopeClasses[scope])
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
+
+// authApiKey authenticates the user of an API key and checks that the key covers scope. Expired keys are kept so that
+// their owner still sees them listed.
+// Returns the user if authorized, otherwise exception.InvalidApiKeyException or exception.PermissionDeniedException
+func (m *Marketplace) authApiKey(ctx context.Context, key string, scope enum.ApiKeyScope) (*model.User, error) {
+    keyId, secret, ok := model.ParseApiKey(key)
+    if !ok {
+        return nil, exception.NewInvalidApiKeyException("malformed api key", nil)
+    }
+    apiKey, err := m.store.GetApiKey(ctx, keyId)
+    if err != nil {
+        return nil, err
+    }
+    if apiKey == nil || !apiKey.Matches(secret) {
+        m.log.Debugf("Api key %s does not exist", keyId)
+        return nil, exception.NewInvalidApiKeyException("api key does not exist or was revoked", nil)
+    }
+    if apiKey.IsExpired(time.Now()) {
+        m.log.Debugf("Api key %s of user '%s' expired at %v", keyId, apiKey.Username, apiKey.ExpiresAt)
+        return nil, exception.NewInvalidApiKeyException("api key expired", nil)
+    }
+    err = m.authorizeScope(*apiKey, scope)
+    if err != nil {
+        return nil, err
+    }
+
+    user, err := m.store.GetUser(ctx, apiKey.Username)
+    if err != nil {
+        return nil, err
+    }
+    if user == nil {
+        return nil, exception.NewInvalidApiKeyException("api key user does not exist", nil)
+    }
+    return user, nil
+}
+
+// authSession authenticates the user of the session of a token. Expired sessions are deleted.
+// Returns the user if the session is valid, otherwise exception.InvalidSessionException
+func (m *Marketplace) authSession(ctx context.Context, token string) (*model.User, error) {
+    tokenHash := model.HashSessionToken(token)
+    session, err := m.store.GetSession(ctx, tokenHash)
+    if err != nil {
+        return nil, err
+    }
+    if session == nil {
+        m.log.Debug("Session does not exist")
+        return nil, exception.NewInvalidSessionException("session does not exist or was revoked", nil)
+    }
+    if session.IsExpired(time.Now()) {
+        m.log.Debugf("Session of user '%s' expired at %v", session.Username, session.ExpiresAt)
+        err = m.store.DeleteSession(ctx, tokenHash)
+        if err != nil {
+            m.log.Warnf("Could not delete expired session: %v", err)
+        }
+        return nil, exception.NewInvalidSessionException("session expired", nil)
+    }
+
+    user, err := m.store.GetUser(ctx, session.Username)
+    if err != nil {
+        return nil, err
+    }
+    if user == nil {
+        return nil, exception.NewInvalidSessionException("session user does not exist", nil)
+    }
+    return user, nil
+}
+
+// authUser determines if the user is authorized to perform the action, identified by username or by a read-only API
+// key, rate limited as a read
+// Returns the user if authorized, otherwise exception.UnknownUserException, the errors of authApiKey or
+// exception.RateLimitedException
+func (m *Marketplace) authUser(ctx context.Context, username string) (*model.User, error) {
+    if model.IsApiKey(username) {
+        return m.authToken(ctx, username, enum.ApiKeyScopeReadOnly)
+    }
+    user, err := m.store.GetUser(ctx, username)
+    if err != nil {
+        m.log.Debugf("Error getting user '%s': %v", username, err)
+        return nil, err
+    }
+    if user == nil {
+        m.log.Debugf("User '%s' does not exist", username)
+        return nil, exception.NewUnknownUserException(fmt.Sprintf("user '%s' does not exist", username), nil)
+    }
+    m.log.Debugf("User with username '%s' exist", username)
+    err = m.throttle(ctx, user.Username, enum.CommandClassRead)
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
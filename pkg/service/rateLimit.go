@@ -0,0 +1,83 @@
+package service
+
+import (
+    "context"
+    "fmt"
+    "go.uber.org/zap"
+    "marketplace-platform/pkg/constant"
+    "marketplace-platform/pkg/data/model"
+    "marketplace-platform/pkg/data/model/enum"
+    "marketplace-platform/pkg/exception"
+    "os"
+    "time"
+)
+
+// scopeClasses maps the scope required by an operation to the class of its rate limit
+var scopeClasses = map[enum.ApiKeyScope]enum.CommandClass{
+    enum.ApiKeyScopeReadOnly:      enum.CommandClassRead,
+    enum.ApiKeyScopeListingsWrite: enum.CommandClassWrite,
+    enum.ApiKeyScopeAdmin:         enum.CommandClassAdmin,
+}
+
+// loadRateLimits reads the rate limit of each command class from the environment, falling back to the defaults
+func loadRateLimits(log *zap.SugaredLogger) map[enum.CommandClass]model.RateLimit {
+    envKeys := []struct {
+        class        enum.CommandClass
+        envKey       string
+        defaultValue string
+    }{
+        {enum.CommandClassRead, constant.RateLimitReadEnvKey, constant.DefaultRateLimitRead},
+        {enum.CommandClassWrite, constant.RateLimitWriteEnvKey, constant.DefaultRateLimitWrite},
+        {enum.CommandClassAdmin, constant.RateLimitAdminEnvKey, constant.DefaultRateLimitAdmin},
+        {enum.CommandClassLogin, constant.RateLimitLoginEnvKey, constant.DefaultRateLimitLogin},
+    }
+
+    limits := make(map[enum.CommandClass]model.RateLimit)
+    for _, entry := range envKeys {
+        value, exists := os.LookupEnv(entry.envKey)
+        if !exists {
+            value = entry.defaultValue
+        }
+        limit, err := model.ParseRateLimit(value)
+        if err != nil {
+            log.Fatalf("invalid %s '%s': %v", entry.envKey, value, err)
+        }
+        limits[entry.class] = limit
+    }
+    return limits
+}
+
+// throttle takes a token from the rate limit bucket of a user for a command of class
+// Returns exception.RateLimitedException if the user sent too many commands of the class
+func (m *Marketplace) throttle(ctx context.Context, username string, class enum.CommandClass) error {
+    limit := m.rateLimits[class]
+    if !limit.IsEnabled() {
+        return nil
+    }
+
+    allowed, err := m.store.TakeRateLimitToken(ctx, model.RateLimitKey(class, username), limit, time.Now())
+    if err != nil {
+        return err
+    }
+    if !allowed {
+        m.log.Warnw("Rate limited", "user", username, "class", class, "limit", limit)
+        return exception.NewRateLimitedException(
+            fmt.Sprintf("user '%s' exceeded the %s rate limit of %v", username, class, limit), nil)
+    }
+    return nil
+}
+
+// authSessionOnly authenticates the user of the session of token for the operations that API keys cannot perform,
+// rate limited as writes
+// Returns the user if authenticated, otherwise the errors of authSession or exception.RateLimitedException
+func (m *Marketplace) authSessionOnly(ctx context.Context, token string) (*model.User, error) {
+    user, err := m.authSession(ctx, token)
+    if err != nil {
+        return nil, err
+    }
+    err = m.throttle(ctx, user.Username, enum.CommandClassWrite)
+    if err != nil {
+        return nil, err
+    }
+    return user, nil
+}
@@ -5,6 +5,7 @@ import (
     "errors"
     "strconv"
     "strings"
+    "time"
     "unicode"
 )
 
@@ -38,6 +39,18 @@ func ConvertPriceStringToInt(s string) (int, error) {
     return int(f * 100), nil
 }
 
+// ParseTime parses a time in local time, either as a date or as a date and time in the listing output format
+// For example
+// 2019-02-22 -> 2019-02-22 00:00:00
+// 2019-02-22 12:34:56 -> 2019-02-22 12:34:56
+func ParseTime(s string) (time.Time, error) {
+    t, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local)
+    if err == nil {
+        return t, nil
+    }
+    return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
 func SplitArgs(input string) []string {
     var args []string
     var inQuotes bool